@@ -0,0 +1,81 @@
+// Package adapters wraps logger to satisfy logging interfaces of libraries,
+// so their records are written by configured backend: logr.LogSink of
+// Kubernetes controllers, grpclog.LoggerV2 of gRPC and log.Logger of go-kit.
+//
+// Verbosity 0 is info, verbosity 1 is debug and higher verbosity is trace.
+// Key-value pairs are Vars of records.
+package adapters
+
+import (
+	"fmt"
+	"errors"
+
+	"bctrader/logs"
+)
+
+// KEY_BAD is key of the last value of pairs which has no key
+const KEY_BAD = "!BADKEY"
+
+// verbosityLevel maps verbosity of library onto level of logger
+func verbosityLevel(v int) int {
+	switch {
+	case v <= 0:
+		return logs.INFO_LEVEL
+	case v == 1:
+		return logs.DEBUG_LEVEL
+	}
+
+	return logs.TRACE_LEVEL
+}
+
+// isEnabled 
+func isEnabled(ls *logs.Logs, l int) bool {
+	return l == logs.PRINT_LEVEL || ls.Level() >= l
+}
+
+// keyValues returns key-value pairs as Vars, key which is not string is
+// formatted, nil is returned when there are no pairs
+func keyValues(pairs ...[]interface{}) logs.Vars {
+	var v logs.Vars
+
+	for _, kv := range pairs {
+		for i := 0; i < len(kv); i += 2 {
+			if v == nil {
+				v = logs.Vars{}
+			}
+			if i+1 == len(kv) {
+				v[KEY_BAD] = kv[i]
+				break
+			}
+			if k, ok := kv[i].(string); ok {
+				v[k] = kv[i+1]
+			} else {
+				v[fmt.Sprint(kv[i])] = kv[i+1]
+			}
+		}
+	}
+
+	return v
+}
+
+// logv writes message with vars by level, message of error levels is error
+func logv(ls *logs.Logs, l int, m string, v logs.Vars) {
+	switch l {
+	case logs.PANIC_LEVEL:
+		ls.Panicv(errors.New(m), v)
+	case logs.FATAL_LEVEL:
+		ls.Fatalv(errors.New(m), v)
+	case logs.ERROR_LEVEL:
+		ls.Errorv(errors.New(m), v)
+	case logs.WARN_LEVEL:
+		ls.Warnv(m, v)
+	case logs.INFO_LEVEL:
+		ls.Infov(m, v)
+	case logs.DEBUG_LEVEL:
+		ls.Debugv(m, v)
+	case logs.TRACE_LEVEL:
+		ls.Tracev(m, v)
+	default:
+		ls.Printv(m, v)
+	}
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"os"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"log/slog"
+	"encoding/json"
+
+	"bctrader/logs"
+)
+
+// newTestLogs returns logger writing JSON records of slog to buffer
+func newTestLogs(t *testing.T, l int) (*logs.Logs, *bytes.Buffer) {
+	w := &bytes.Buffer{}
+	s, err := logs.NewSlog(&logs.SlogSettings{Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: logs.SLOG_LEVEL_TRACE})}, &logs.Formatter{Level: l})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, err := logs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ls.SetBackend(s); err != nil {
+		t.Fatal(err)
+	}
+
+	return ls, w
+}
+
+// records decodes JSON records written to buffer
+func records(t *testing.T, w *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+
+	for _, line := range strings.Split(strings.TrimSpace(w.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("%v: %s", err, line)
+		}
+		records = append(records, m)
+	}
+	w.Reset()
+
+	return records
+}
+
+func TestLogr(t *testing.T) {
+	ls, w := newTestLogs(t, logs.DEBUG_LEVEL)
+	logger := NewLogr(ls).WithName("controller").WithValues("kind", "Pod")
+
+	logger.Info("reconciled", "name", "web", "odd")
+	logger.V(1).Info("debug")
+	logger.V(2).Info("trace")
+	logger.Error(errors.New("conflict"), "update failed", "retry", true)
+
+	r := records(t, w)
+	if len(r) != 3 {
+		t.Fatalf("unexpected records %v", r)
+	}
+	if r[0]["level"] != "INFO" || r[0]["logger"] != "controller" || r[0]["kind"] != "Pod" || r[0]["name"] != "web" || r[0][KEY_BAD] != "odd" {
+		t.Errorf("unexpected info record %v", r[0])
+	}
+	if r[1]["level"] != "DEBUG" || r[1]["msg"] != "debug" {
+		t.Errorf("unexpected debug record %v", r[1])
+	}
+	if r[2]["level"] != "ERROR" || r[2]["msg"] != "update failed" || r[2][logs.KEY_ERROR] != "conflict" || r[2]["retry"] != true {
+		t.Errorf("unexpected error record %v", r[2])
+	}
+	if source, _ := r[0]["source"].(map[string]interface{}); !strings.HasSuffix(source["file"].(string), "adapters_test.go") {
+		t.Errorf("unexpected source %v", r[0]["source"])
+	}
+}
+
+func TestGRPCLogger(t *testing.T) {
+	ls, w := newTestLogs(t, logs.INFO_LEVEL)
+	g := NewGRPCLogger(ls)
+
+	g.Info("transport: ", "closing")
+	g.Warningf("retry %d", 3)
+	g.Errorln("failed", 1)
+	if g.V(0) != true || g.V(2) != false {
+		t.Error("unexpected verbosity")
+	}
+
+	r := records(t, w)
+	if len(r) != 3 || r[0]["msg"] != "transport: closing" || r[1]["level"] != "WARN" || r[1]["msg"] != "retry 3" || r[2]["level"] != "ERROR" || r[2]["msg"] != "failed 1" {
+		t.Errorf("unexpected records %v", r)
+	}
+}
+
+func TestGRPCLoggerFatal(t *testing.T) {
+	ls, w := newTestLogs(t, logs.INFO_LEVEL)
+	g := NewGRPCLogger(ls)
+	codes := []int{}
+	exit = func(code int) {
+		codes = append(codes, code)
+	}
+	defer func() {
+		exit = os.Exit
+	}()
+
+	g.Fatal("listener: ", "closed")
+	g.Fatalln("listener", 2)
+	g.Fatalf("listener %d", 3)
+
+	if r := records(t, w); len(r) != 3 || r[0]["msg"] != "listener: closed" || r[2]["msg"] != "listener 3" {
+		t.Errorf("unexpected records %v", r)
+	}
+	if len(codes) != 3 || codes[0] != 1 || codes[1] != 1 || codes[2] != 1 {
+		t.Errorf("unexpected exit codes %v", codes)
+	}
+}
+
+func TestKitLogger(t *testing.T) {
+	ls, w := newTestLogs(t, logs.INFO_LEVEL)
+	k := NewKitLogger(ls)
+
+	k.Log("level", "warn", "msg", "slow", "ms", 1500)
+	k.Log("level", "debug", "msg", "skipped")
+	k.Log("event", "start")
+
+	r := records(t, w)
+	if len(r) != 2 || r[0]["level"] != "WARN" || r[0]["msg"] != "slow" || r[0]["ms"] != float64(1500) || r[1]["level"] != "INFO" || r[1]["event"] != "start" {
+		t.Errorf("unexpected records %v", r)
+	}
+}
@@ -0,0 +1,99 @@
+package adapters
+
+import (
+	"os"
+	"fmt"
+	"errors"
+
+	"bctrader/logs"
+)
+
+// exit ends program after fatal record, it is replaced by tests
+var exit = os.Exit
+
+// GRPCLogger implements grpclog.LoggerV2, so it is set by
+// grpclog.SetLoggerV2(adapters.NewGRPCLogger(ls)). Warning is warn level and
+// arguments are formatted like fmt.Print, fmt.Println and fmt.Printf do.
+// Fatal methods exit by os.Exit(1) whichever backend writes the record.
+type GRPCLogger struct {
+	logs *logs.Logs
+}
+
+// NewGRPCLogger 
+func NewGRPCLogger(ls *logs.Logs) *GRPCLogger {
+	return &GRPCLogger{logs: ls}
+}
+
+// Info 
+func (g *GRPCLogger) Info(args ...interface{}) {
+	if isEnabled(g.logs, logs.INFO_LEVEL) {
+		g.logs.Info(fmt.Sprint(args...))
+	}
+}
+
+// Infoln 
+func (g *GRPCLogger) Infoln(args ...interface{}) {
+	g.logs.Infoln(args...)
+}
+
+// Infof 
+func (g *GRPCLogger) Infof(format string, args ...interface{}) {
+	g.logs.Infof(format, args...)
+}
+
+// Warning 
+func (g *GRPCLogger) Warning(args ...interface{}) {
+	if isEnabled(g.logs, logs.WARN_LEVEL) {
+		g.logs.Warn(fmt.Sprint(args...))
+	}
+}
+
+// Warningln 
+func (g *GRPCLogger) Warningln(args ...interface{}) {
+	g.logs.Warnln(args...)
+}
+
+// Warningf 
+func (g *GRPCLogger) Warningf(format string, args ...interface{}) {
+	g.logs.Warnf(format, args...)
+}
+
+// Error 
+func (g *GRPCLogger) Error(args ...interface{}) {
+	if isEnabled(g.logs, logs.ERROR_LEVEL) {
+		g.logs.Error(errors.New(fmt.Sprint(args...)))
+	}
+}
+
+// Errorln 
+func (g *GRPCLogger) Errorln(args ...interface{}) {
+	g.logs.Errorln(args...)
+}
+
+// Errorf 
+func (g *GRPCLogger) Errorf(format string, args ...interface{}) {
+	g.logs.Errorf(errors.New(format), args...)
+}
+
+// Fatal 
+func (g *GRPCLogger) Fatal(args ...interface{}) {
+	g.logs.Fatal(errors.New(fmt.Sprint(args...)))
+	exit(1)
+}
+
+// Fatalln 
+func (g *GRPCLogger) Fatalln(args ...interface{}) {
+	g.logs.Fatalln(args...)
+	exit(1)
+}
+
+// Fatalf 
+func (g *GRPCLogger) Fatalf(format string, args ...interface{}) {
+	g.logs.Fatalf(errors.New(format), args...)
+	exit(1)
+}
+
+// V reports whether level of verbosity is enabled by logger
+func (g *GRPCLogger) V(l int) bool {
+	return isEnabled(g.logs, verbosityLevel(l))
+}
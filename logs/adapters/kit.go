@@ -0,0 +1,58 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+
+	"bctrader/logs"
+)
+
+// Keys of go-kit records
+const (
+	KIT_KEY_LEVEL   = "level"
+	KIT_KEY_MESSAGE = "msg"
+)
+
+// KitLogger implements log.Logger of go-kit. Value under KIT_KEY_LEVEL, e.g.
+// of go-kit level package, is level name of record, record without it is
+// info. Value under KIT_KEY_MESSAGE is message, other pairs are vars.
+type KitLogger struct {
+	logs *logs.Logs
+}
+
+// NewKitLogger 
+func NewKitLogger(ls *logs.Logs) *KitLogger {
+	return &KitLogger{logs: ls}
+}
+
+// Log writes key-value pairs as record, unknown level name is info
+func (k *KitLogger) Log(keyvals ...interface{}) error {
+	l := logs.INFO_LEVEL
+	v := keyValues(keyvals)
+
+	if level, ok := v[KIT_KEY_LEVEL]; ok {
+		name := strings.ToLower(fmt.Sprint(level))
+		for i, levelName := range logs.LevelNames() {
+			if name == levelName || (name == "warning" && i == logs.WARN_LEVEL) {
+				l = i
+				break
+			}
+		}
+		delete(v, KIT_KEY_LEVEL)
+	}
+	if !isEnabled(k.logs, l) {
+		return nil
+	}
+
+	var m string
+	if message, ok := v[KIT_KEY_MESSAGE]; ok {
+		m = fmt.Sprint(message)
+		delete(v, KIT_KEY_MESSAGE)
+	}
+	if len(v) == 0 {
+		v = nil
+	}
+	logv(k.logs, l, m, v)
+
+	return nil
+}
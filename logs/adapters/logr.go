@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"bctrader/logs"
+
+	"github.com/go-logr/logr"
+)
+
+// LogrSink is logr.LogSink writing records by logger. Values of WithValues
+// come before pairs of record, name of WithName is name of named logger and
+// error of Error is under logs.KEY_ERROR.
+type LogrSink struct {
+	logs   *logs.Logs
+	values []interface{}
+}
+
+// NewLogr returns logr.Logger writing records by logger
+func NewLogr(ls *logs.Logs) logr.Logger {
+	return logr.New(NewLogrSink(ls))
+}
+
+// NewLogrSink 
+func NewLogrSink(ls *logs.Logs) *LogrSink {
+	return &LogrSink{logs: ls}
+}
+
+// Init does nothing, caller of record is found by logger
+func (s *LogrSink) Init(info logr.RuntimeInfo) {
+}
+
+// Enabled reports whether level of verbosity is enabled by logger
+func (s *LogrSink) Enabled(level int) bool {
+	return isEnabled(s.logs, verbosityLevel(level))
+}
+
+// Info writes record by level of verbosity
+func (s *LogrSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if l := verbosityLevel(level); isEnabled(s.logs, l) {
+		logv(s.logs, l, msg, keyValues(s.values, keysAndValues))
+	}
+}
+
+// Error writes error record, nil error is not written to vars
+func (s *LogrSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	if isEnabled(s.logs, logs.ERROR_LEVEL) {
+		v := keyValues(s.values, keysAndValues)
+		if err != nil {
+			if v == nil {
+				v = logs.Vars{}
+			}
+			v[logs.KEY_ERROR] = err
+		}
+		logv(s.logs, logs.ERROR_LEVEL, msg, v)
+	}
+}
+
+// WithValues returns sink whose records have key-value pairs
+func (s *LogrSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	c := *s
+	c.values = append(s.values[:len(s.values):len(s.values)], keysAndValues...)
+
+	return &c
+}
+
+// WithName returns sink of named logger, names are joined by dot
+func (s *LogrSink) WithName(name string) logr.LogSink {
+	c := *s
+	c.logs = s.logs.Named(name)
+
+	return &c
+}
@@ -0,0 +1,29 @@
+//go:build !race
+
+package logs
+
+import (
+	"time"
+	"errors"
+	"testing"
+)
+
+// Race detector drops pooled values, so allocations are checked without it
+func TestTypedZeroAllocs(t *testing.T) {
+	err := errors.New("error")
+	tt := time.Now()
+
+	for _, l := range benchmarkLoggers(INFO_LEVEL) {
+		ls := newTestLogs(l)
+		for _, level := range []int{INFO_LEVEL, WARN_LEVEL} {
+			l.SetLevel(level)
+			n := testing.AllocsPerRun(100, func() {
+				ls.Info("message")
+				ls.Infow("message", String("string", "value"), Int64("int", 1), Float64("float", 0.5), Bool("bool", true), Duration("duration", time.Millisecond), Datetime("datetime", tt), Err(err))
+			})
+			if n != 0 {
+				t.Errorf("%s at %s level: %v allocations per record", l.FormatName(), levelNames[level], n)
+			}
+		}
+	}
+}
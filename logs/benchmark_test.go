@@ -0,0 +1,85 @@
+package logs
+
+import (
+	"time"
+	"errors"
+	"testing"
+)
+
+// benchmarkLoggers returns loggers of stream formats writing to io.Discard
+func benchmarkLoggers(level int) []logger {
+	return []logger{
+		discardText(&Formatter{Level: level}),
+		discardJSON(&Formatter{Level: level}),
+		discardFMT(&Formatter{Level: level}),
+		discardFluent(&Formatter{Level: level}),
+	}
+}
+
+// benchmarkFormats runs fn for every format, sequentially and in parallel
+func benchmarkFormats(b *testing.B, level int, isParallel bool, fn func(ls *Logs, i int)) {
+	for _, l := range benchmarkLoggers(level) {
+		ls := newTestLogs(l)
+
+		b.Run(l.FormatName(), func(b *testing.B) {
+			b.ReportAllocs()
+			if isParallel {
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						fn(ls, i)
+					}
+				})
+			} else {
+				for i := 0; i < b.N; i++ {
+					fn(ls, i)
+				}
+			}
+		})
+	}
+}
+
+// info 
+func info(ls *Logs, i int) {
+	ls.Info("message")
+}
+
+// infov 
+func infov(ls *Logs, i int) {
+	ls.Infov("message", Vars{"string": "value", "int": i, "float": 0.5, "bool": true})
+}
+
+// infow 
+func infow(ls *Logs, i int) {
+	ls.Infow("message", String("string", "value"), Int("int", i), Float64("float", 0.5), Bool("bool", true), Duration("duration", time.Millisecond), Err(errBenchmark))
+}
+
+// errBenchmark 
+var errBenchmark = errors.New("error")
+
+func BenchmarkInfo(b *testing.B) {
+	benchmarkFormats(b, INFO_LEVEL, false, info)
+}
+
+func BenchmarkInfoParallel(b *testing.B) {
+	benchmarkFormats(b, INFO_LEVEL, true, info)
+}
+
+func BenchmarkInfov(b *testing.B) {
+	benchmarkFormats(b, INFO_LEVEL, false, infov)
+}
+
+func BenchmarkInfovParallel(b *testing.B) {
+	benchmarkFormats(b, INFO_LEVEL, true, infov)
+}
+
+func BenchmarkInfow(b *testing.B) {
+	benchmarkFormats(b, INFO_LEVEL, false, infow)
+}
+
+func BenchmarkInfowParallel(b *testing.B) {
+	benchmarkFormats(b, INFO_LEVEL, true, infow)
+}
+
+func BenchmarkInfowDisabled(b *testing.B) {
+	benchmarkFormats(b, WARN_LEVEL, false, infow)
+}
@@ -0,0 +1,153 @@
+package logs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"bytes"
+	"strconv"
+)
+
+// 
+const (
+	DEDUP_WINDOW_DEFAULT = int(30000)
+	DEDUP_MESSAGE        = "Last message repeated %d times"
+	KEY_REPEAT_COUNT     = "repeat_count"
+)
+
+// DedupSettings collapses consecutive identical records, with the same
+// level, logger name, message, fields and vars, into the first one and a
+// later record with count of repeats. Window is in milliseconds, zero
+// value uses DEDUP_WINDOW_DEFAULT.
+type DedupSettings struct {
+	Window int `json:"window" yaml:"window" xml:"window" toml:"window"`
+}
+
+// recorder is backend writing entry without deduplication
+type recorder interface {
+	record(e *Entry)
+}
+
+// deduper keeps key of the last record of backend and counts its repeats
+type deduper struct {
+	mutex    sync.Mutex
+	window   int64
+	key      []byte
+	last     []byte
+	keys     buffer
+	level    int
+	name     string
+	until    int64
+	count    int
+	recorder recorder
+	timer    *time.Timer
+}
+
+// newDeduper returns nil when deduplication is disabled
+func newDeduper(s *DedupSettings) *deduper {
+	if s == nil {
+		return nil
+	}
+
+	window := s.Window
+	if window <= 0 {
+		window = DEDUP_WINDOW_DEFAULT
+	}
+
+	return &deduper{
+		window: int64(window) * int64(time.Millisecond),
+	}
+}
+
+// appendKey encodes everything of entry except time
+func (d *deduper) appendKey(b []byte, format *Formatter, e *Entry) []byte {
+	b = strconv.AppendInt(b, int64(e.Level), 10)
+	b = append(b, 0)
+	b = append(b, e.Name...)
+	b = append(b, 0)
+	b = append(b, e.Message...)
+
+	for i := 0; i < len(e.Fields); i++ {
+		if !e.Fields[i].isSkipped() {
+			b = append(b, 0)
+			b = append(b, e.Fields[i].Key...)
+			b = append(b, 0)
+			b = appendLogfmtValue(b, format, &e.Fields[i])
+		}
+	}
+	for _, key := range d.keys.sortKeys(e.Vars) {
+		b = append(b, 0)
+		b = append(b, key...)
+		b = append(b, 0)
+		b = appendLogfmtInterface(b, e.Vars[key])
+	}
+	for i := 0; i < len(d.keys.keys); i++ {
+		d.keys.keys[i] = EMPTY_STRING
+	}
+	d.keys.keys = d.keys.keys[:0]
+
+	return b
+}
+
+// isRepeated counts entry and tells if it has to be dropped. Panic and fatal
+// records are never dropped.
+func (d *deduper) isRepeated(r recorder, format *Formatter, e *Entry) bool {
+	if e.Level < ERROR_LEVEL {
+		return false
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := e.Time.UnixNano()
+	d.key = d.appendKey(d.key[:0], format, e)
+	if now < d.until && bytes.Equal(d.key, d.last) {
+		d.count++
+		if d.timer == nil {
+			d.recorder = r
+			d.timer = time.AfterFunc(time.Duration(d.until-now), d.expire)
+		}
+		return true
+	}
+
+	d.flush()
+	d.key, d.last = d.last, d.key
+	d.level = e.Level
+	d.name = e.Name
+	d.until = now + d.window
+
+	return false
+}
+
+// flush writes count of repeats of the last record, it is called under mutex
+func (d *deduper) flush() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.count > 0 {
+		e := newEntry(d.level, d.name, fmt.Sprintf(DEDUP_MESSAGE, d.count), nil)
+		e.Fields = append(e.Fields, Int(KEY_REPEAT_COUNT, d.count))
+		d.recorder.record(e)
+		e.free()
+		d.count = 0
+	}
+}
+
+// expire flushes repeats when window of the last record is over
+func (d *deduper) expire() {
+	d.mutex.Lock()
+	if time.Now().UnixNano() >= d.until {
+		d.flush()
+		d.until = 0
+	}
+	d.mutex.Unlock()
+}
+
+// close flushes pending repeats
+func (d *deduper) close() {
+	d.mutex.Lock()
+	d.flush()
+	d.until = 0
+	d.mutex.Unlock()
+}
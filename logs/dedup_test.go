@@ -0,0 +1,72 @@
+package logs
+
+import (
+	"sync"
+	"time"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// lockedBuffer is written by deduplication timer
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+// Write 
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+// String 
+func (b *lockedBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.String()
+}
+
+func TestDedupConsecutive(t *testing.T) {
+	w := &lockedBuffer{}
+	l := writerFMT(&Formatter{Level: INFO_LEVEL, Dedup: &DedupSettings{Window: 60000}}, w)
+	ls := newTestLogs(l)
+
+	for i := 0; i < 5; i++ {
+		ls.Warnw("repeated", Int("n", 1))
+	}
+	ls.Warnw("repeated", Int("n", 2))
+	ls.Warnv("repeated", Vars{"n": 3})
+	ls.Warnv("repeated", Vars{"n": 3})
+	l.Close()
+
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d records: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[1], "repeat_count=4") || !strings.Contains(lines[1], "level=warn") {
+		t.Errorf("unexpected repeated record %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "n=2") || !strings.Contains(lines[3], "n=3") || !strings.Contains(lines[4], "repeat_count=1") {
+		t.Errorf("unexpected records %q", lines)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	w := &lockedBuffer{}
+	ls := newTestLogs(writerJSON(&Formatter{Level: INFO_LEVEL, Dedup: &DedupSettings{Window: 10}}, w))
+
+	ls.Info("repeated")
+	ls.Info("repeated")
+	time.Sleep(100 * time.Millisecond)
+	if !strings.Contains(w.String(), `"repeat_count":1`) {
+		t.Errorf("repeats are not flushed after window: %q", w.String())
+	}
+	ls.Info("repeated")
+	if n := strings.Count(w.String(), `"msg":"repeated"`); n != 2 {
+		t.Errorf("got %d records after window, want 2", n)
+	}
+}
@@ -0,0 +1,238 @@
+package logs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Profiles of JSON format, ECS profile writes records by Elastic Common
+// Schema and GCP profile by structured logging of Cloud Logging
+const (
+	JSON_PROFILE_DEFAULT = int(0)
+	JSON_PROFILE_ECS     = int(1)
+	JSON_PROFILE_GCP     = int(2)
+)
+
+// ECS keys and version
+const (
+	ECS_VERSION                 = "8.11.0"
+	ECS_TIME_FORMAT             = "2006-01-02T15:04:05.000Z07:00"
+	ECS_KEY_TIMESTAMP           = "@timestamp"
+	ECS_KEY_LEVEL               = "log.level"
+	ECS_KEY_MESSAGE             = "message"
+	ECS_KEY_VERSION             = "ecs.version"
+	ECS_KEY_SERVICE_NAME        = "service.name"
+	ECS_KEY_SERVICE_ENVIRONMENT = "service.environment"
+	ECS_KEY_LOGGER              = "log.logger"
+	ECS_KEY_LABELS              = "labels"
+	ECS_KEY_TAGS                = "tags"
+	ECS_KEY_ERROR_TYPE          = "error.type"
+	ECS_KEY_ERROR_MESSAGE       = "error.message"
+	ECS_KEY_ERROR_STACK_TRACE   = "error.stack_trace"
+	ECS_KEY_ORIGIN_FILE_NAME    = "log.origin.file.name"
+	ECS_KEY_ORIGIN_FILE_LINE    = "log.origin.file.line"
+	ECS_KEY_ORIGIN_FUNCTION     = "log.origin.function"
+	ECS_KEY_TRACE_ID            = "trace.id"
+	ECS_KEY_SPAN_ID             = "span.id"
+	ECS_LABEL_SEPARATOR         = "="
+)
+
+// ecsKeysNames are names of fixed keys by ECS, fields with these keys are
+// handled by collision strategy
+var ecsKeysNames = KeysNames{
+	Level:       ECS_KEY_LEVEL,
+	Labels:      ECS_KEY_LABELS,
+	Message:     ECS_KEY_MESSAGE,
+	Time:        ECS_KEY_TIMESTAMP,
+	Timestamp:   ECS_KEY_TIMESTAMP,
+	Environment: ECS_KEY_SERVICE_ENVIRONMENT,
+	Tag:         ECS_KEY_SERVICE_NAME,
+	Logger:      ECS_KEY_LOGGER,
+}
+
+// defaultECSKeys sets ECS names of fixed keys which are not set yet
+func defaultECSKeys(f *Formatter) {
+	defaultProfileKeys(f, &ecsKeysNames)
+}
+
+// defaultProfileKeys sets names of fixed keys by profile which are not set
+// yet
+func defaultProfileKeys(f *Formatter, names *KeysNames) {
+	if f.Keys == nil {
+		f.Keys = &Keys{}
+	}
+	if f.Keys.Names == nil {
+		f.Keys.Names = &KeysNames{}
+	}
+	for _, name := range []struct {
+		value *string
+		name  string
+	}{
+		{&f.Keys.Names.Level, names.Level},
+		{&f.Keys.Names.Labels, names.Labels},
+		{&f.Keys.Names.Message, names.Message},
+		{&f.Keys.Names.Time, names.Time},
+		{&f.Keys.Names.Timestamp, names.Timestamp},
+		{&f.Keys.Names.Environment, names.Environment},
+		{&f.Keys.Names.Tag, names.Tag},
+		{&f.Keys.Names.Logger, names.Logger},
+	} {
+		if *name.value == EMPTY_STRING {
+			*name.value = name.name
+		}
+	}
+}
+
+// isECSError tells if field is written as error.* keys
+func isECSError(f *Field) bool {
+	return f.Key == KEY_ERROR && f.Type == FIELD_TYPE_ERROR
+}
+
+// ecsError returns first error under KEY_ERROR of fields or vars
+func ecsError(e *Entry) error {
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); isECSError(&f) {
+			return f.Interface.(error)
+		}
+	}
+	if v, ok := e.Vars[KEY_ERROR]; ok {
+		if f := valueField(KEY_ERROR, v, 0); isECSError(&f) {
+			return f.Interface.(error)
+		}
+	}
+
+	return nil
+}
+
+// appendECSEntry appends entry as ECS JSON object. Labels as "key=value"
+// go to labels object, other labels go to tags. Error under KEY_ERROR is
+// written as error.type, error.message and error.stack_trace when %+v of
+// error prints more than its message. Caller is written as log.origin and
+// trace context as trace.id and span.id.
+func appendECSEntry(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	b = append(b, '{')
+	if isKeysFirst(format, true) {
+		b = appendECSFixedKeys(b, format, e)
+		b = appendECSFields(b, format, e, keys)
+	} else {
+		b = appendECSFields(b, format, e, keys)
+		b = appendECSFixedKeys(b, format, e)
+	}
+
+	return append(b, '}')
+}
+
+// appendECSFixedKeys 
+func appendECSFixedKeys(b []byte, format *Formatter, e *Entry) []byte {
+	if !isOverwritten(format, e, format.Keys.Names.Time) {
+		b = appendJSONFixedKey(b, format.Keys.Names.Time)
+		b = append(b, '"')
+		b = e.Time.UTC().AppendFormat(b, ECS_TIME_FORMAT)
+		b = append(b, '"')
+	}
+	if e.Level < PRINT_LEVEL {
+		b = appendJSONFixed(b, format, e, format.Keys.Names.Level, levelNames[e.Level])
+	}
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Message, e.Message)
+	b = appendJSONFixedKey(b, ECS_KEY_VERSION)
+	b = appendJSONString(b, ECS_VERSION)
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Tag, format.Tag)
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Environment, format.Environment)
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Logger, e.Name)
+	if format.Labels.String != EMPTY_STRING && !isOverwritten(format, e, format.Keys.Names.Labels) {
+		b = appendECSLabels(b, format, format.Keys.Names.Labels)
+	}
+
+	if err := ecsError(e); err != nil {
+		s := err.Error()
+		b = appendJSONFixedKey(b, ECS_KEY_ERROR_TYPE)
+		b = appendJSONString(b, fmt.Sprintf("%T", err))
+		b = appendJSONFixedKey(b, ECS_KEY_ERROR_MESSAGE)
+		b = appendJSONString(b, s)
+		if stack := fmt.Sprintf("%+v", err); stack != s {
+			b = appendJSONFixedKey(b, ECS_KEY_ERROR_STACK_TRACE)
+			b = appendJSONString(b, strings.TrimPrefix(strings.TrimPrefix(stack, s), "\n"))
+		}
+	}
+
+	if e.Trace.IsValid() {
+		b = appendJSONFixedKey(b, ECS_KEY_TRACE_ID)
+		b = append(b, '"')
+		b = e.Trace.appendPart(b, 0)
+		b = append(b, '"')
+		b = appendJSONFixedKey(b, ECS_KEY_SPAN_ID)
+		b = append(b, '"')
+		b = e.Trace.appendPart(b, 1)
+		b = append(b, '"')
+	}
+
+	if frame := caller(); frame.File != EMPTY_STRING {
+		b = appendJSONFixedKey(b, ECS_KEY_ORIGIN_FILE_NAME)
+		b = appendJSONString(b, frame.File)
+		b = appendJSONFixedKey(b, ECS_KEY_ORIGIN_FILE_LINE)
+		b = strconv.AppendInt(b, int64(frame.Line), 10)
+		b = appendJSONFixedKey(b, ECS_KEY_ORIGIN_FUNCTION)
+		b = appendJSONString(b, frame.Function)
+	}
+
+	return b
+}
+
+// appendECSLabels appends labels object and tags array
+func appendECSLabels(b []byte, format *Formatter, k string) []byte {
+	labels := strings.Split(format.Labels.String, format.Labels.Separator)
+
+	isTagged := false
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != EMPTY_STRING && !strings.Contains(label, ECS_LABEL_SEPARATOR) {
+			if !isTagged {
+				b = appendJSONFixedKey(b, ECS_KEY_TAGS)
+				b = append(b, '[')
+				isTagged = true
+			} else {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, label)
+		}
+	}
+	if isTagged {
+		b = append(b, ']')
+	}
+
+	isLabeled := false
+	for _, label := range labels {
+		if key, value, ok := strings.Cut(strings.TrimSpace(label), ECS_LABEL_SEPARATOR); ok && key != EMPTY_STRING {
+			if !isLabeled {
+				b = appendJSONFixedKey(b, k)
+				b = append(b, '{')
+				isLabeled = true
+			}
+			b = appendJSONFixedKey(b, key)
+			b = appendJSONString(b, value)
+		}
+	}
+	if isLabeled {
+		b = append(b, '}')
+	}
+
+	return b
+}
+
+// appendECSFields appends fields and vars except error written by fixed keys
+func appendECSFields(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() && !isECSError(&f) && isKeptKey(format, f.Key) {
+			b = appendJSONKey(b, format, f.Key)
+			b = appendJSONValue(b, format, &f)
+		}
+	}
+	for _, key := range keys {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() && !isECSError(&f) && isKeptKey(format, key) {
+			b = appendJSONKey(b, format, key)
+			b = appendJSONValue(b, format, &f)
+		}
+	}
+
+	return b
+}
@@ -0,0 +1,951 @@
+package logs
+
+import (
+	"os"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+	"sync"
+	"strconv"
+	"encoding"
+	"unicode/utf8"
+	"encoding/json"
+)
+
+// Buffer sizes
+const (
+	BUFFER_SIZE     = int(1024)
+	BUFFER_MAX_SIZE = int(64 * 1024)
+)
+
+// 
+const (
+	JSON_NULL  = "null"
+	JSON_NAN   = `"NaN"`
+	JSON_INF_P = `"+Inf"`
+	JSON_INF_N = `"-Inf"`
+)
+
+// Error string messages
+const (
+	__ERROR_STR_KEY_COLLISION = "Field key collides with fixed key"
+)
+
+// hexDigits 
+const hexDigits = "0123456789abcdef"
+
+// buffer is reusable byte slice of one record with scratch for vars keys
+type buffer struct {
+	bytes []byte
+	keys  []string
+}
+
+// bufferPool 
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return &buffer{
+			bytes: make([]byte, 0, BUFFER_SIZE),
+		}
+	},
+}
+
+// newBuffer 
+func newBuffer() *buffer {
+	return bufferPool.Get().(*buffer)
+}
+
+// free returns buffer to pool, too big buffers are left to GC
+func (b *buffer) free() {
+	if cap(b.bytes) <= BUFFER_MAX_SIZE && cap(b.keys) <= BUFFER_SIZE {
+		b.bytes = b.bytes[:0]
+		for i := 0; i < len(b.keys); i++ {
+			b.keys[i] = EMPTY_STRING
+		}
+		b.keys = b.keys[:0]
+		bufferPool.Put(b)
+	}
+}
+
+// sortKeys fills keys scratch of buffer by sorted keys of vars
+func (b *buffer) sortKeys(v Vars) []string {
+	for key := range v {
+		b.keys = append(b.keys, key)
+	}
+	sort.Strings(b.keys)
+
+	return b.keys
+}
+
+// writeRecord ends record by new line like log.Logger does, writes it by one
+// call of writer and frees buffer. Panic and fatal levels keep behaviour of
+// log.Logger.
+func writeRecord(l int, stdout *log.Logger, stderr *log.Logger, b *buffer) {
+	n := len(b.bytes)
+	if n == 0 || b.bytes[n-1] != '\n' {
+		b.bytes = append(b.bytes, '\n')
+	}
+
+	w := stdout
+	if l <= ERROR_LEVEL {
+		w = stderr
+	}
+	w.Writer().Write(b.bytes)
+
+	switch l {
+	case PANIC_LEVEL:
+		panic(string(b.bytes[:n]))
+	case FATAL_LEVEL:
+		os.Exit(1)
+	}
+	b.free()
+}
+
+// sprintln formats like fmt.Sprintln, but trailing new line is trimmed,
+// because it is added by writer of record
+func sprintln(i ...interface{}) string {
+	s := fmt.Sprintln(i...)
+
+	return s[:len(s)-1]
+}
+
+// isReservedKey 
+func isReservedKey(format *Formatter, k string) bool {
+	switch k {
+	case format.Keys.Names.Level, format.Keys.Names.Labels, format.Keys.Names.Message, format.Keys.Names.Timestamp, format.Keys.Names.Time, format.Keys.Names.Environment, format.Keys.Names.Tag, format.Keys.Names.Logger:
+		return true
+	}
+
+	return false
+}
+
+// isPrefixedKey tells if reserved key of field is written under prefix
+func isPrefixedKey(format *Formatter, k string) bool {
+	switch format.Keys.Collision {
+	case KEYS_COLLISION_OVERWRITE, KEYS_COLLISION_ERROR:
+		return false
+	}
+
+	return isReservedKey(format, k)
+}
+
+// isKeptKey is false for reserved key of field when collision is error
+func isKeptKey(format *Formatter, k string) bool {
+	if format.Keys.Collision == KEYS_COLLISION_ERROR && isReservedKey(format, k) {
+		keyCollision(format, k)
+		return false
+	}
+
+	return true
+}
+
+// keyCollision reports dropped field, caller data is never changed
+func keyCollision(format *Formatter, k string) {
+	if format.Stderr.IsPrintable {
+		format.Stderr.Logger.Print(__ERROR_STR_KEY_COLLISION + ": " + k)
+	}
+}
+
+// isOverwritten tells if fixed key is replaced by field of entry
+func isOverwritten(format *Formatter, e *Entry, k string) bool {
+	if format.Keys.Collision != KEYS_COLLISION_OVERWRITE {
+		return false
+	}
+	for i := 0; i < len(e.Fields); i++ {
+		if e.Fields[i].Key == k && !e.Fields[i].isSkipped() {
+			return true
+		}
+	}
+	_, ok := e.Vars[k]
+
+	return ok
+}
+
+// appendTime 
+func appendTime(b []byte, format *Formatter, tt time.Time) []byte {
+	if format.Time.IsUTC {
+		tt = tt.UTC()
+	}
+
+	return tt.AppendFormat(b, format.Time.Format)
+}
+
+// appendTimeStamp 
+func appendTimeStamp(b []byte, format *Formatter, tt time.Time) []byte {
+	if format.Time.IsUTC {
+		tt = tt.UTC()
+	}
+
+	return strconv.AppendInt(b, timeStampLevel(format.Time.StampLevel, tt), 10)
+}
+
+// appendDuration is time.Duration.String without allocation
+func appendDuration(b []byte, d time.Duration) []byte {
+	var buf [32]byte
+
+	w := len(buf)
+	u := uint64(d)
+	neg := d < 0
+	if neg {
+		u = -u
+	}
+
+	if u < uint64(time.Second) {
+		var prec int
+
+		w--
+		buf[w] = 's'
+		w--
+		switch {
+		case u == 0:
+			return append(b, '0', 's')
+		case u < uint64(time.Microsecond):
+			prec = 0
+			buf[w] = 'n'
+		case u < uint64(time.Millisecond):
+			prec = 3
+			w--
+			copy(buf[w:], "\u00b5")
+		default:
+			prec = 6
+			buf[w] = 'm'
+		}
+		w, u = durationFrac(buf[:w], u, prec)
+		w = durationInt(buf[:w], u)
+	} else {
+		w--
+		buf[w] = 's'
+		w, u = durationFrac(buf[:w], u, 9)
+		w = durationInt(buf[:w], u%60)
+		u /= 60
+		if u > 0 {
+			w--
+			buf[w] = 'm'
+			w = durationInt(buf[:w], u%60)
+			u /= 60
+			if u > 0 {
+				w--
+				buf[w] = 'h'
+				w = durationInt(buf[:w], u)
+			}
+		}
+	}
+
+	if neg {
+		w--
+		buf[w] = '-'
+	}
+
+	return append(b, buf[w:]...)
+}
+
+// durationFrac writes fraction of v without trailing zeros
+func durationFrac(buf []byte, v uint64, prec int) (int, uint64) {
+	w := len(buf)
+	print := false
+
+	for i := 0; i < prec; i++ {
+		digit := v % 10
+		print = print || digit != 0
+		if print {
+			w--
+			buf[w] = byte(digit) + '0'
+		}
+		v /= 10
+	}
+	if print {
+		w--
+		buf[w] = '.'
+	}
+
+	return w, v
+}
+
+// durationInt 
+func durationInt(buf []byte, v uint64) int {
+	w := len(buf)
+
+	if v == 0 {
+		w--
+		buf[w] = '0'
+	} else {
+		for v > 0 {
+			w--
+			buf[w] = byte(v%10) + '0'
+			v /= 10
+		}
+	}
+
+	return w
+}
+
+// appendJSONString appends quoted and escaped string
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	b = appendJSONStringBody(b, s)
+
+	return append(b, '"')
+}
+
+// appendJSONStringBody escapes like encoding/json, but keeps HTML symbols
+func appendJSONStringBody(b []byte, s string) []byte {
+	start := 0
+
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+
+	return append(b, s[start:]...)
+}
+
+// appendJSONKey appends separator and key, reserved keys are prefixed
+func appendJSONKey(b []byte, format *Formatter, k string) []byte {
+	if len(b) > 0 && b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+	b = append(b, '"')
+	if isPrefixedKey(format, k) {
+		b = appendJSONStringBody(b, format.Keys.Prefix)
+		b = appendJSONStringBody(b, format.Keys.PrefixSeparator)
+	}
+	b = appendJSONStringBody(b, k)
+
+	return append(b, '"', ':')
+}
+
+// appendJSONFloat formats float like encoding/json, NaN and Inf are quoted
+func appendJSONFloat(b []byte, f float64, bits int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(b, JSON_NAN...)
+	case math.IsInf(f, 1):
+		return append(b, JSON_INF_P...)
+	case math.IsInf(f, -1):
+		return append(b, JSON_INF_N...)
+	}
+
+	verb := byte('f')
+	if abs := math.Abs(f); abs != 0 && (bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21)) {
+		verb = 'e'
+	}
+	b = strconv.AppendFloat(b, f, verb, -1, bits)
+	if verb == 'e' {
+		// clean up e-09 to e-9
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+
+	return b
+}
+
+// appendJSONValue 
+func appendJSONValue(b []byte, format *Formatter, f *Field) []byte {
+	switch f.Type {
+	case FIELD_TYPE_STRING:
+		b = appendJSONString(b, f.String)
+	case FIELD_TYPE_INT64:
+		b = strconv.AppendInt(b, f.Integer, 10)
+	case FIELD_TYPE_UINT64:
+		b = strconv.AppendUint(b, uint64(f.Integer), 10)
+	case FIELD_TYPE_FLOAT64:
+		b = appendJSONFloat(b, math.Float64frombits(uint64(f.Integer)), 64)
+	case FIELD_TYPE_BOOL:
+		b = strconv.AppendBool(b, f.Integer == 1)
+	case FIELD_TYPE_DURATION:
+		b = append(b, '"')
+		b = appendDuration(b, time.Duration(f.Integer))
+		b = append(b, '"')
+	case FIELD_TYPE_TIME:
+		b = appendJSONTime(b, format, f.time())
+	case FIELD_TYPE_ERROR:
+		b = appendJSONString(b, f.Interface.(error).Error())
+	case FIELD_TYPE_OBJECT:
+		b = appendJSONObject(b, format, f)
+	default:
+		b = appendJSONInterface(b, f.Interface)
+	}
+
+	return b
+}
+
+// appendJSONInterface encodes basic types directly, others by encoding/json.
+// Value which can not be marshaled is replaced by the error text.
+func appendJSONInterface(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		b = append(b, JSON_NULL...)
+	case string:
+		b = appendJSONString(b, x)
+	case bool:
+		b = strconv.AppendBool(b, x)
+	case int:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int8:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int16:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int32:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int64:
+		b = strconv.AppendInt(b, x, 10)
+	case uint:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint8:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint16:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint32:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint64:
+		b = strconv.AppendUint(b, x, 10)
+	case float32:
+		b = appendJSONFloat(b, float64(x), 32)
+	case float64:
+		b = appendJSONFloat(b, x, 64)
+	default:
+		out, err := json.Marshal(x)
+		if err != nil {
+			b = appendJSONString(b, err.Error())
+		} else {
+			b = append(b, out...)
+		}
+	}
+
+	return b
+}
+
+// appendJSONTime appends quoted time, layout is escaped only when needed
+func appendJSONTime(b []byte, format *Formatter, tt time.Time) []byte {
+	start := len(b)
+
+	b = append(b, '"')
+	b = appendTime(b, format, tt)
+	for i := start + 1; i < len(b); i++ {
+		if b[i] < 0x20 || b[i] == '"' || b[i] == '\\' {
+			return appendJSONString(b[:start], string(b[start+1:]))
+		}
+	}
+
+	return append(b, '"')
+}
+
+// isKeysFirst tells if fixed keys go before fields and vars
+func isKeysFirst(format *Formatter, isDefault bool) bool {
+	switch format.Keys.Position {
+	case KEYS_POSITION_FIRST:
+		return true
+	case KEYS_POSITION_LAST:
+		return false
+	}
+
+	return isDefault
+}
+
+// appendJSONEntry appends fixed keys, fields and vars of entry as JSON
+// object. Fields keep their order, vars are written in order of keys.
+func appendJSONEntry(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	b = append(b, '{')
+	if isKeysFirst(format, true) {
+		b = appendJSONFixedKeys(b, format, e)
+		b = appendJSONFields(b, format, e, keys)
+	} else {
+		b = appendJSONFields(b, format, e, keys)
+		b = appendJSONFixedKeys(b, format, e)
+	}
+
+	return append(b, '}')
+}
+
+// appendJSONFixedKeys 
+func appendJSONFixedKeys(b []byte, format *Formatter, e *Entry) []byte {
+	if e.Level < PRINT_LEVEL {
+		b = appendJSONFixed(b, format, e, format.Keys.Names.Level, levelNames[e.Level])
+	}
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Labels, format.Labels.String)
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Message, e.Message)
+	if format.Time.IsStamp {
+		if !isOverwritten(format, e, format.Keys.Names.Timestamp) {
+			b = appendJSONFixedKey(b, format.Keys.Names.Timestamp)
+			b = appendTimeStamp(b, format, e.Time)
+		}
+	} else if !isOverwritten(format, e, format.Keys.Names.Time) {
+		b = appendJSONFixedKey(b, format.Keys.Names.Time)
+		b = appendJSONTime(b, format, e.Time)
+	}
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Environment, format.Environment)
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Tag, format.Tag)
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Logger, e.Name)
+
+	return appendJSONTrace(b, format, e)
+}
+
+// appendJSONTrace appends trace context of entry as hex strings
+func appendJSONTrace(b []byte, format *Formatter, e *Entry) []byte {
+	if !e.Trace.IsValid() {
+		return b
+	}
+	for i, k := range traceKeys(format) {
+		if !isOverwritten(format, e, k) {
+			b = appendJSONFixedKey(b, k)
+			b = append(b, '"')
+			b = e.Trace.appendPart(b, i)
+			b = append(b, '"')
+		}
+	}
+
+	return b
+}
+
+// appendJSONFields 
+func appendJSONFields(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() && isKeptKey(format, f.Key) {
+			b = appendJSONKey(b, format, f.Key)
+			b = appendJSONValue(b, format, &f)
+		}
+	}
+	for _, key := range keys {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() && isKeptKey(format, key) {
+			b = appendJSONKey(b, format, key)
+			b = appendJSONValue(b, format, &f)
+		}
+	}
+
+	return b
+}
+
+// appendJSONObject appends object field as nested JSON object
+func appendJSONObject(b []byte, format *Formatter, f *Field) []byte {
+	b = append(b, '{')
+	fields := objectFields(nil, f)
+	for i := 0; i < len(fields); i++ {
+		if !fields[i].isSkipped() {
+			b = appendJSONFixedKey(b, fields[i].Key)
+			b = appendJSONValue(b, format, &fields[i])
+		}
+	}
+
+	return append(b, '}')
+}
+
+// appendJSONFixedKey 
+func appendJSONFixedKey(b []byte, k string) []byte {
+	if b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+	b = append(b, '"')
+	b = appendJSONStringBody(b, k)
+
+	return append(b, '"', ':')
+}
+
+// appendJSONFixed appends key with string value, empty value and key
+// overwritten by field are skipped
+func appendJSONFixed(b []byte, format *Formatter, e *Entry, k string, v string) []byte {
+	if v == EMPTY_STRING || isOverwritten(format, e, k) {
+		return b
+	}
+	b = appendJSONFixedKey(b, k)
+
+	return appendJSONString(b, v)
+}
+
+// isLogfmtKeyRune is false for runes dropped from logfmt keys
+func isLogfmtKeyRune(r rune) bool {
+	return r > ' ' && r != '=' && r != '"' && r != utf8.RuneError
+}
+
+// appendLogfmtKey appends separator and key without invalid runes, the
+// second value is false when nothing is left of the key
+func appendLogfmtKey(b []byte, format *Formatter, k string) ([]byte, bool) {
+	start := len(b)
+
+	if start > 0 {
+		b = append(b, ' ')
+	}
+	if format != nil && isPrefixedKey(format, k) {
+		b = appendLogfmtKeyBody(b, format.Keys.Prefix)
+		b = appendLogfmtKeyBody(b, format.Keys.PrefixSeparator)
+	}
+	end := len(b)
+	b = appendLogfmtKeyBody(b, k)
+	if len(b) == end {
+		return b[:start], false
+	}
+
+	return append(b, '='), true
+}
+
+// appendLogfmtKeyBody 
+func appendLogfmtKeyBody(b []byte, k string) []byte {
+	for i := 0; i < len(k); {
+		r, size := utf8.DecodeRuneInString(k[i:])
+		if isLogfmtKeyRune(r) {
+			b = append(b, k[i:i+size]...)
+		}
+		i += size
+	}
+
+	return b
+}
+
+// needsLogfmtQuote 
+func needsLogfmtQuote(s string) bool {
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError {
+			return true
+		}
+		i += size
+	}
+
+	return false
+}
+
+// appendLogfmtString quotes value like go-logfmt does
+func appendLogfmtString(b []byte, s string) []byte {
+	switch {
+	case s == JSON_NULL:
+		return append(b, `"null"`...)
+	case needsLogfmtQuote(s):
+		return appendJSONString(b, s)
+	}
+
+	return append(b, s...)
+}
+
+// appendLogfmtValue 
+func appendLogfmtValue(b []byte, format *Formatter, f *Field) []byte {
+	switch f.Type {
+	case FIELD_TYPE_STRING:
+		b = appendLogfmtString(b, f.String)
+	case FIELD_TYPE_INT64:
+		b = strconv.AppendInt(b, f.Integer, 10)
+	case FIELD_TYPE_UINT64:
+		b = strconv.AppendUint(b, uint64(f.Integer), 10)
+	case FIELD_TYPE_FLOAT64:
+		b = strconv.AppendFloat(b, math.Float64frombits(uint64(f.Integer)), 'g', -1, 64)
+	case FIELD_TYPE_BOOL:
+		b = strconv.AppendBool(b, f.Integer == 1)
+	case FIELD_TYPE_DURATION:
+		b = appendDuration(b, time.Duration(f.Integer))
+	case FIELD_TYPE_TIME:
+		b = appendLogfmtTime(b, format, f.time())
+	case FIELD_TYPE_ERROR:
+		b = appendLogfmtString(b, f.Interface.(error).Error())
+	default:
+		b = appendLogfmtInterface(b, f.Interface)
+	}
+
+	return b
+}
+
+// appendLogfmtInterface encodes value by rules of go-logfmt, basic types
+// are appended without allocation
+func appendLogfmtInterface(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		b = append(b, JSON_NULL...)
+	case string:
+		b = appendLogfmtString(b, x)
+	case []byte:
+		b = appendLogfmtString(b, string(x))
+	case bool:
+		b = strconv.AppendBool(b, x)
+	case int:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int8:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int16:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int32:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int64:
+		b = strconv.AppendInt(b, x, 10)
+	case uint:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint8:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint16:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint32:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint64:
+		b = strconv.AppendUint(b, x, 10)
+	case float32:
+		b = strconv.AppendFloat(b, float64(x), 'g', -1, 32)
+	case float64:
+		b = strconv.AppendFloat(b, x, 'g', -1, 64)
+	case time.Duration:
+		b = appendDuration(b, x)
+	case encoding.TextMarshaler:
+		out, err := x.MarshalText()
+		if err != nil {
+			b = appendLogfmtString(b, err.Error())
+		} else {
+			b = appendLogfmtString(b, string(out))
+		}
+	case error:
+		b = appendLogfmtString(b, x.Error())
+	case fmt.Stringer:
+		b = appendLogfmtString(b, x.String())
+	default:
+		if isCollection(x) {
+			b = appendLogfmtString(b, string(appendJSONInterface(nil, x)))
+		} else {
+			b = appendLogfmtString(b, fmt.Sprint(x))
+		}
+	}
+
+	return b
+}
+
+// appendLogfmtTime appends time, layouts with spaces are quoted
+func appendLogfmtTime(b []byte, format *Formatter, tt time.Time) []byte {
+	start := len(b)
+
+	b = appendTime(b, format, tt)
+	for i := start; i < len(b); i++ {
+		if b[i] <= ' ' || b[i] == '=' || b[i] == '"' {
+			return appendJSONString(b[:start], string(b[start:]))
+		}
+	}
+
+	return b
+}
+
+// appendLogfmtFixed appends key with string value, empty value and key
+// overwritten by field are skipped
+func appendLogfmtFixed(b []byte, format *Formatter, e *Entry, k string, v string) []byte {
+	if v == EMPTY_STRING || isOverwritten(format, e, k) {
+		return b
+	}
+	b, ok := appendLogfmtKey(b, nil, k)
+	if ok {
+		b = appendLogfmtString(b, v)
+	}
+
+	return b
+}
+
+// appendLogfmtEntry appends fields, vars and fixed keys of entry as logfmt
+// line. Fields keep their order, vars are written in order of keys.
+func appendLogfmtEntry(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	if isKeysFirst(format, false) {
+		b = appendLogfmtFixedKeys(b, format, e)
+		b = appendLogfmtFields(b, format, e, keys)
+	} else {
+		b = appendLogfmtFields(b, format, e, keys)
+		b = appendLogfmtFixedKeys(b, format, e)
+	}
+
+	return b
+}
+
+// appendLogfmtFields 
+func appendLogfmtFields(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() && isKeptKey(format, f.Key) {
+			b = appendLogfmtField(b, format, f.Key, &f)
+		}
+	}
+	for _, key := range keys {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() && isKeptKey(format, key) {
+			b = appendLogfmtField(b, format, key, &f)
+		}
+	}
+
+	return b
+}
+
+// appendLogfmtField appends resolved field, objects are flattened to dotted keys
+func appendLogfmtField(b []byte, format *Formatter, k string, f *Field) []byte {
+	var ok bool
+
+	if f.Type == FIELD_TYPE_OBJECT {
+		fields := objectFields(nil, f)
+		for i := 0; i < len(fields); i++ {
+			if !fields[i].isSkipped() {
+				b = appendLogfmtField(b, format, k+OBJECT_KEY_SEPARATOR+fields[i].Key, &fields[i])
+			}
+		}
+		return b
+	}
+	if b, ok = appendLogfmtKey(b, format, k); ok {
+		b = appendLogfmtValue(b, format, f)
+	}
+
+	return b
+}
+
+// appendLogfmtFixedKeys 
+func appendLogfmtFixedKeys(b []byte, format *Formatter, e *Entry) []byte {
+	var ok bool
+
+	if format.Time.IsStamp {
+		if !isOverwritten(format, e, format.Keys.Names.Timestamp) {
+			if b, ok = appendLogfmtKey(b, nil, format.Keys.Names.Timestamp); ok {
+				b = appendTimeStamp(b, format, e.Time)
+			}
+		}
+	} else if !isOverwritten(format, e, format.Keys.Names.Time) {
+		if b, ok = appendLogfmtKey(b, nil, format.Keys.Names.Time); ok {
+			b = appendLogfmtTime(b, format, e.Time)
+		}
+	}
+	if e.Level < PRINT_LEVEL {
+		b = appendLogfmtFixed(b, format, e, format.Keys.Names.Level, levelNames[e.Level])
+	}
+	b = appendLogfmtFixed(b, format, e, format.Keys.Names.Environment, format.Environment)
+	b = appendLogfmtFixed(b, format, e, format.Keys.Names.Tag, format.Tag)
+	b = appendLogfmtFixed(b, format, e, format.Keys.Names.Labels, format.Labels.String)
+	b = appendLogfmtFixed(b, format, e, format.Keys.Names.Logger, e.Name)
+	if e.Trace.IsValid() {
+		for i, k := range traceKeys(format) {
+			if isOverwritten(format, e, k) {
+				continue
+			}
+			if b, ok = appendLogfmtKey(b, nil, k); ok {
+				b = e.Trace.appendPart(b, i)
+			}
+		}
+	}
+	if isOverwritten(format, e, format.Keys.Names.Message) {
+		return b
+	}
+	if b, ok = appendLogfmtKey(b, nil, format.Keys.Names.Message); ok {
+		b = appendLogfmtString(b, e.Message)
+	}
+
+	return b
+}
+
+// appendTextWord appends space and word, empty word is skipped
+func appendTextWord(b []byte, s string) []byte {
+	if s != EMPTY_STRING {
+		b = append(b, ' ')
+		b = append(b, s...)
+	}
+
+	return b
+}
+
+// appendTextValue appends value like fmt %v does, basic types are appended
+// without allocation
+func appendTextValue(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case string:
+		b = append(b, x...)
+	case bool:
+		b = strconv.AppendBool(b, x)
+	case int:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int8:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int16:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int32:
+		b = strconv.AppendInt(b, int64(x), 10)
+	case int64:
+		b = strconv.AppendInt(b, x, 10)
+	case uint:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint8:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint16:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint32:
+		b = strconv.AppendUint(b, uint64(x), 10)
+	case uint64:
+		b = strconv.AppendUint(b, x, 10)
+	case float32:
+		b = strconv.AppendFloat(b, float64(x), 'g', -1, 32)
+	case float64:
+		b = strconv.AppendFloat(b, x, 'g', -1, 64)
+	case time.Duration:
+		b = appendDuration(b, x)
+	default:
+		if isCollection(x) {
+			b = appendJSONInterface(b, x)
+		} else {
+			b = fmt.Append(b, x)
+		}
+	}
+
+	return b
+}
+
+// appendTextField 
+func appendTextField(b []byte, format *Formatter, f *Field) []byte {
+	switch f.Type {
+	case FIELD_TYPE_STRING:
+		b = append(b, f.String...)
+	case FIELD_TYPE_INT64:
+		b = strconv.AppendInt(b, f.Integer, 10)
+	case FIELD_TYPE_UINT64:
+		b = strconv.AppendUint(b, uint64(f.Integer), 10)
+	case FIELD_TYPE_FLOAT64:
+		b = strconv.AppendFloat(b, math.Float64frombits(uint64(f.Integer)), 'g', -1, 64)
+	case FIELD_TYPE_BOOL:
+		b = strconv.AppendBool(b, f.Integer == 1)
+	case FIELD_TYPE_DURATION:
+		b = appendDuration(b, time.Duration(f.Integer))
+	case FIELD_TYPE_TIME:
+		b = appendTime(b, format, f.time())
+	case FIELD_TYPE_ERROR:
+		b = append(b, f.Interface.(error).Error()...)
+	default:
+		b = appendTextValue(b, f.Interface)
+	}
+
+	return b
+}
@@ -0,0 +1,192 @@
+package logs
+
+import (
+	"math"
+	"time"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"encoding/json"
+
+	"github.com/go-logfmt/logfmt"
+)
+
+func TestAppendDuration(t *testing.T) {
+	for _, d := range []time.Duration{0, 1, 999, time.Microsecond, 1500 * time.Microsecond, time.Second, -90 * time.Minute, 26*time.Hour + 3*time.Millisecond, math.MinInt64} {
+		if s := string(appendDuration(nil, d)); s != d.String() {
+			t.Errorf("got %q, want %q", s, d.String())
+		}
+	}
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range []string{"", "plain", "q\"b\\s", "tab\tnl\n\x00\x1f", "<html>&", "  ", "ünïcode", "bad\xffutf8"} {
+		var out string
+		if err := json.Unmarshal(appendJSONString(nil, s), &out); err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if want := strings.ToValidUTF8(s, "\ufffd"); out != want {
+			t.Errorf("got %q, want %q", out, want)
+		}
+	}
+}
+
+func TestAppendJSONFloat(t *testing.T) {
+	for _, f := range []float64{0, 1.5, -2, 1e-7, 1e21, 123456789.125} {
+		want, _ := json.Marshal(f)
+		if s := string(appendJSONFloat(nil, f, 64)); s != string(want) {
+			t.Errorf("got %s, want %s", s, want)
+		}
+	}
+	if s := string(appendJSONFloat(nil, math.Inf(-1), 64)); s != JSON_INF_N {
+		t.Errorf("unexpected infinity %s", s)
+	}
+}
+
+func TestJSONFields(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerJSON(&Formatter{Level: INFO_LEVEL, Tag: "tag"}, w))
+	tt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ls.Named("http").Infow("message",
+		String("string", "a \"b\""),
+		Int("int", -1),
+		Uint64("uint", math.MaxUint64),
+		Float64("float", 0.5),
+		Bool("bool", true),
+		Duration("duration", time.Second),
+		Datetime("datetime", tt),
+		Err(errors.New("error")),
+		NamedErr("skipped", nil),
+		Any("any", []int{1, 2}),
+		String("msg", "reserved"))
+
+	v := map[string]interface{}{}
+	if err := json.Unmarshal(w.Bytes(), &v); err != nil {
+		t.Fatalf("%s: %v", w.Bytes(), err)
+	}
+	want := map[string]interface{}{
+		"level":      "info",
+		"msg":        "message",
+		"tag":        "tag",
+		"logger":     "http",
+		"string":     "a \"b\"",
+		"int":        float64(-1),
+		"uint":       float64(math.MaxUint64),
+		"float":      0.5,
+		"bool":       true,
+		"duration":   "1s",
+		"datetime":   tt.Format(TIME_FORMAT_RFC3339_NANO),
+		"error":      "error",
+		"any":        []interface{}{float64(1), float64(2)},
+		"fields.msg": "reserved",
+	}
+	for key, value := range want {
+		got, _ := json.Marshal(v[key])
+		expected, _ := json.Marshal(value)
+		if string(got) != string(expected) {
+			t.Errorf("%s: got %s, want %s", key, got, expected)
+		}
+	}
+	if _, ok := v["skipped"]; ok {
+		t.Error("nil error is not skipped")
+	}
+}
+
+func TestFMTFields(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerFMT(&Formatter{Level: INFO_LEVEL}, w))
+
+	ls.Warnw("two words", String("k y", "v=1"), Int64("n", 7), Err(nil), String("level", "x"), String("null", "null"))
+
+	d := logfmt.NewDecoder(w)
+	v := map[string]string{}
+	for d.ScanRecord() {
+		for d.ScanKeyval() {
+			v[string(d.Key())] = string(d.Value())
+		}
+	}
+	if err := d.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"ky":           "v=1",
+		"n":            "7",
+		"fields.level": "x",
+		"null":         "null",
+		"level":        "warn",
+		"msg":          "two words",
+	}
+	for key, value := range want {
+		if v[key] != value {
+			t.Errorf("%s: got %q, want %q", key, v[key], value)
+		}
+	}
+	if _, ok := v["error"]; ok {
+		t.Error("nil error is not skipped")
+	}
+}
+
+func TestFieldsOrder(t *testing.T) {
+	for _, position := range []int{KEYS_POSITION_DEFAULT, KEYS_POSITION_FIRST, KEYS_POSITION_LAST} {
+		jsonw, fmtw := &bytes.Buffer{}, &bytes.Buffer{}
+		for _, l := range []logger{writerJSON(&Formatter{Level: INFO_LEVEL, Keys: &Keys{Position: position}}, jsonw), writerFMT(&Formatter{Level: INFO_LEVEL, Keys: &Keys{Position: position}}, fmtw)} {
+			ls := newTestLogs(l)
+			for i := 0; i < 10; i++ {
+				ls.Infov("message", Vars{"c": 3, "a": 1, "b": 2, "d": 4})
+				ls.Infow("message", String("z", "z"), Int("y", 1), String("x", "x"))
+			}
+		}
+
+		jsonLines := strings.Split(strings.TrimSpace(jsonw.String()), "\n")
+		fmtLines := strings.Split(strings.TrimSpace(fmtw.String()), "\n")
+		for i := 2; i < len(jsonLines); i++ {
+			if strings.SplitN(jsonLines[i], `"time"`, 2)[0] != strings.SplitN(jsonLines[i-2], `"time"`, 2)[0] {
+				t.Errorf("order of JSON keys is changed: %s", jsonLines[i])
+			}
+		}
+
+		var vars, fields string
+		switch position {
+		case KEYS_POSITION_FIRST:
+			vars, fields = `{"level":"info","msg":"message","time":`, " level=info msg=message z=z y=1 x=x"
+		case KEYS_POSITION_LAST:
+			vars, fields = `{"a":1,"b":2,"c":3,"d":4,"level":"info"`, "z=z y=1 x=x time="
+		default:
+			vars, fields = `{"level":"info","msg":"message","time":`, "z=z y=1 x=x time="
+		}
+		if !strings.HasPrefix(jsonLines[0], vars) || position != KEYS_POSITION_LAST && !strings.HasSuffix(jsonLines[0], `"a":1,"b":2,"c":3,"d":4}`) {
+			t.Errorf("%d: unexpected JSON %s", position, jsonLines[0])
+		}
+		if !strings.Contains(fmtLines[1], fields) {
+			t.Errorf("%d: unexpected logfmt %s", position, fmtLines[1])
+		}
+		if position != KEYS_POSITION_FIRST && !strings.HasPrefix(fmtLines[0], "a=1 b=2 c=3 d=4 time=") {
+			t.Errorf("%d: unexpected logfmt %s", position, fmtLines[0])
+		}
+	}
+}
+
+func TestKeysCollision(t *testing.T) {
+	v := Vars{"msg": "field", "tag": "field", "k": "v"}
+	for collision, want := range map[int]string{
+		KEYS_COLLISION_PREFIX:    `"tag":"tag","k":"v","fields.msg":"field","fields.tag":"field"}`,
+		KEYS_COLLISION_OVERWRITE: `"k":"v","msg":"field","tag":"field"}`,
+		KEYS_COLLISION_ERROR:     `"tag":"tag","k":"v"}`,
+	} {
+		w, errw := &bytes.Buffer{}, &bytes.Buffer{}
+		ls := newTestLogs(writerJSON(&Formatter{Level: INFO_LEVEL, Tag: "tag", Time: &Time{IsStamp: true}, Keys: &Keys{Collision: collision}, Stderr: &StdOE{Writer: errw, IsPrintable: true}}, w))
+
+		ls.Infov("message", v)
+		if !strings.HasSuffix(w.String(), want+"\n") || strings.Count(w.String(), `"msg":`) != 1 {
+			t.Errorf("%d: unexpected record %s", collision, w.Bytes())
+		}
+		if collision == KEYS_COLLISION_ERROR && strings.Count(errw.String(), __ERROR_STR_KEY_COLLISION) != 2 {
+			t.Errorf("collisions are not reported: %q", errw.String())
+		}
+	}
+	if len(v) != 3 || v["msg"] != "field" || v["tag"] != "field" {
+		t.Errorf("vars are changed: %v", v)
+	}
+}
@@ -0,0 +1,117 @@
+package logs
+
+import (
+	"sync"
+	"time"
+	"strings"
+	"runtime"
+)
+
+// ENTRY_FIELDS_MAX_CAP limits fields capacity of entry returned to pool
+const ENTRY_FIELDS_MAX_CAP = int(64)
+
+// Entry is one log record passed from Logs to logger
+type Entry struct {
+	Time    time.Time
+	Level   int
+	Name    string
+	Message string
+	Vars    Vars
+	Fields  []Field
+	Trace   TraceContext
+}
+
+// CALLER_DEPTH_MAX limits frames searched for caller of logger
+const CALLER_DEPTH_MAX = int(32)
+
+// CALLER_SLOG_PREFIX is prefix of functions of log/slog, records of slog
+// handler are written from them
+const CALLER_SLOG_PREFIX = "log/slog."
+
+// callerPrefix is prefix of functions of this package
+var callerPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	i := strings.LastIndexByte(name, '/') + 1
+
+	return name[:i+strings.IndexByte(name[i:], '.')+1]
+}()
+
+// callerPrefixes are prefixes of functions skipped by caller: this package,
+// its subpackages and logging APIs which records come from through adapters
+var callerPrefixes = []string{
+	callerPrefix,
+	strings.TrimSuffix(callerPrefix, DOT_STRING) + "/",
+	CALLER_SLOG_PREFIX,
+	"log.",
+	"github.com/go-logr/logr.",
+	"github.com/go-kit/log.",
+	"google.golang.org/grpc/grpclog.",
+	"google.golang.org/grpc/grpclog/",
+	"google.golang.org/grpc/internal/grpclog.",
+}
+
+// isCallerSkipped 
+func isCallerSkipped(function string) bool {
+	for _, prefix := range callerPrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// caller returns frame of code which logged record. Frames of this package
+// and of logging APIs are skipped, so depth of call does not matter, except
+// its tests.
+func caller() runtime.Frame {
+	var pcs [CALLER_DEPTH_MAX]uintptr
+
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		frame, isMore := frames.Next()
+		if !isCallerSkipped(frame.Function) || strings.HasSuffix(frame.File, "_test.go") {
+			return frame
+		}
+		if !isMore {
+			return runtime.Frame{}
+		}
+	}
+}
+
+// entryPool 
+var entryPool = sync.Pool{
+	New: func() interface{} {
+		return &Entry{}
+	},
+}
+
+// newEntry takes entry from pool, it is returned by free
+func newEntry(l int, n string, m string, v Vars) *Entry {
+	e := entryPool.Get().(*Entry)
+	e.Time = time.Now()
+	e.Level = l
+	e.Name = n
+	e.Message = m
+	e.Vars = v
+
+	return e
+}
+
+// free drops references of entry and returns it to pool
+func (e *Entry) free() {
+	if cap(e.Fields) > ENTRY_FIELDS_MAX_CAP {
+		e.Fields = nil
+	}
+	for i := 0; i < len(e.Fields); i++ {
+		e.Fields[i] = Field{}
+	}
+	e.Fields = e.Fields[:0]
+	e.Vars = nil
+	e.Name = EMPTY_STRING
+	e.Message = EMPTY_STRING
+	e.Trace = TraceContext{}
+
+	entryPool.Put(e)
+}
@@ -0,0 +1,160 @@
+package logs
+
+import (
+	"math"
+	"time"
+)
+
+// Field types as int
+const (
+	FIELD_TYPE_ANY      = int(0)
+	FIELD_TYPE_STRING   = int(1)
+	FIELD_TYPE_INT64    = int(2)
+	FIELD_TYPE_UINT64   = int(3)
+	FIELD_TYPE_FLOAT64  = int(4)
+	FIELD_TYPE_BOOL     = int(5)
+	FIELD_TYPE_DURATION = int(6)
+	FIELD_TYPE_TIME     = int(7)
+	FIELD_TYPE_ERROR    = int(8)
+	FIELD_TYPE_SKIP     = int(9)
+)
+
+// KEY_ERROR is default key of Err field
+const KEY_ERROR = "error"
+
+// Field is typed key value pair. Values are kept unboxed, so building
+// fields on the hot path does not allocate.
+type Field struct {
+	Key       string
+	Type      int
+	Integer   int64
+	String    string
+	Interface interface{}
+}
+
+// String 
+func String(k string, v string) Field {
+	return Field{Key: k, Type: FIELD_TYPE_STRING, String: v}
+}
+
+// Int 
+func Int(k string, v int) Field {
+	return Int64(k, int64(v))
+}
+
+// Int32 
+func Int32(k string, v int32) Field {
+	return Int64(k, int64(v))
+}
+
+// Int64 
+func Int64(k string, v int64) Field {
+	return Field{Key: k, Type: FIELD_TYPE_INT64, Integer: v}
+}
+
+// Uint 
+func Uint(k string, v uint) Field {
+	return Uint64(k, uint64(v))
+}
+
+// Uint32 
+func Uint32(k string, v uint32) Field {
+	return Uint64(k, uint64(v))
+}
+
+// Uint64 
+func Uint64(k string, v uint64) Field {
+	return Field{Key: k, Type: FIELD_TYPE_UINT64, Integer: int64(v)}
+}
+
+// Float32 
+func Float32(k string, v float32) Field {
+	return Float64(k, float64(v))
+}
+
+// Float64 
+func Float64(k string, v float64) Field {
+	return Field{Key: k, Type: FIELD_TYPE_FLOAT64, Integer: int64(math.Float64bits(v))}
+}
+
+// Bool 
+func Bool(k string, v bool) Field {
+	var i int64
+
+	if v {
+		i = 1
+	}
+
+	return Field{Key: k, Type: FIELD_TYPE_BOOL, Integer: i}
+}
+
+// Duration 
+func Duration(k string, v time.Duration) Field {
+	return Field{Key: k, Type: FIELD_TYPE_DURATION, Integer: int64(v)}
+}
+
+// Datetime keeps time as nanoseconds and location, it is rendered by
+// time format of Formatter
+func Datetime(k string, v time.Time) Field {
+	return Field{Key: k, Type: FIELD_TYPE_TIME, Integer: v.UnixNano(), Interface: v.Location()}
+}
+
+// Err uses KEY_ERROR as key, nil error gives field which is skipped
+func Err(e error) Field {
+	return NamedErr(KEY_ERROR, e)
+}
+
+// NamedErr 
+func NamedErr(k string, e error) Field {
+	if e == nil {
+		return Field{Key: k, Type: FIELD_TYPE_SKIP}
+	}
+
+	return Field{Key: k, Type: FIELD_TYPE_ERROR, Interface: e}
+}
+
+// Any keeps value as is, encoders fall back to reflection for it
+func Any(k string, v interface{}) Field {
+	return Field{Key: k, Type: FIELD_TYPE_ANY, Interface: v}
+}
+
+// Value returns field value as interface
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case FIELD_TYPE_STRING:
+		return f.String
+	case FIELD_TYPE_INT64:
+		return f.Integer
+	case FIELD_TYPE_UINT64:
+		return uint64(f.Integer)
+	case FIELD_TYPE_FLOAT64:
+		return math.Float64frombits(uint64(f.Integer))
+	case FIELD_TYPE_BOOL:
+		return f.Integer == 1
+	case FIELD_TYPE_DURATION:
+		return time.Duration(f.Integer)
+	case FIELD_TYPE_TIME:
+		return f.time()
+	case FIELD_TYPE_ERROR:
+		return f.Interface.(error).Error()
+	case FIELD_TYPE_SKIP:
+		return nil
+	default:
+		return f.Interface
+	}
+}
+
+// time 
+func (f Field) time() time.Time {
+	t := time.Unix(0, f.Integer)
+	if location, ok := f.Interface.(*time.Location); ok && location != nil {
+		t = t.In(location)
+	}
+
+	return t
+}
+
+// isSkipped is true for fields without value, e.g. Err(nil)
+func (f Field) isSkipped() bool {
+	return f.Type == FIELD_TYPE_SKIP
+}
@@ -4,12 +4,8 @@ import (
 	"io"
 	"fmt"
 	"log"
-	"time"
-	"bytes"
 	"errors"
 	"strings"
-
-	"github.com/go-logfmt/logfmt"
 )
 
 // FLUENT_NAME 
@@ -27,7 +23,7 @@ type FluentSettings struct {
 
 // Fluent 
 type Fluent struct {
-	format   *Formatter
+	format   *formatState
 	settings *FluentSettings
 	stdout   *log.Logger
 	stderr   *log.Logger
@@ -62,72 +58,39 @@ func NewFluent(s *FluentSettings, f ...*Formatter) (*Fluent, error) {
 	}
 
 	newOE(&stdout, &stderr, false)
-
-	return &Fluent{
-		format,
-		settings,
-		newSystemLogger(stdout),
-		newSystemLogger(stderr),
-	}, nil
-}
-
-// timeStamp 
-func (f *Fluent) timeStamp(tt time.Time) int64 {
-	if f.format.Time.IsUTC {
-		tt = tt.UTC()
+	err := redactCheck(format.Redact)
+
+	if err == nil {
+		return &Fluent{
+			newFormatState(format),
+			settings,
+			newSystemLogger(stdout),
+			newSystemLogger(stderr),
+		}, nil
+	} else {
+		if format.Stderr.IsPrintable {
+			format.Stderr.Logger.Print(err.Error())
+		}
+		return nil, err
 	}
-
-	return timeStampLevel(f.format.Time.StampLevel, tt)
 }
 
-// time 
-func (f *Fluent) time(tt time.Time) string {
-	if f.format.Time.IsUTC {
-		tt = tt.UTC()
-	}
-
-	return tt.Format(f.format.Time.Format)
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (f *Fluent) write(e *Entry) {
+	f.format.write(f, e)
 }
 
-// build 
-func (f *Fluent) build(l int, m string, v Vars) string {
-	buffer := &bytes.Buffer{}
-	logFmt := logfmt.NewEncoder(buffer)
-
-	if v != nil {
-		for key, value := range v {
-			switch key {
-			case f.format.Keys.Names.Level, f.format.Keys.Names.Labels, f.format.Keys.Names.Message, f.format.Keys.Names.Timestamp, f.format.Keys.Names.Time, f.format.Keys.Names.Environment, f.format.Keys.Names.Tag:
-				logFmt.EncodeKeyval(f.format.Keys.Prefix+f.format.Keys.PrefixSeparator+key, value)
-				delete(v, key)
-			default:
-				logFmt.EncodeKeyval(key, value)
-			}
-		}
-		v = nil
-	}
-
-	if f.format.Time.IsStamp {
-		logFmt.EncodeKeyval(f.format.Keys.Names.Timestamp, f.timeStamp(time.Now()))
-	} else {
-		logFmt.EncodeKeyval(f.format.Keys.Names.Time, f.time(time.Now()))
-	}
-	if l < PRINT_LEVEL {
-		logFmt.EncodeKeyval(f.format.Keys.Names.Level, levelNames[l])
-	}
-	if f.format.Environment != EMPTY_STRING {
-		logFmt.EncodeKeyval(f.format.Keys.Names.Environment, f.format.Environment)
-	}
-	if f.format.Tag != EMPTY_STRING {
-		logFmt.EncodeKeyval(f.format.Keys.Names.Tag, f.format.Tag)
-	}
-	if f.format.Labels.String != EMPTY_STRING {
-		logFmt.EncodeKeyval(f.format.Keys.Names.Labels, f.format.Labels.String)
-	}
-	logFmt.EncodeKeyval(f.format.Keys.Names.Message, m)
-	logFmt = nil
+// formatter returns current snapshot of Formatter
+func (f *Fluent) formatter() *Formatter {
+	return f.format.get()
+}
 
-	return buffer.String()
+// record encodes entry into pooled buffer and writes it by one call
+func (f *Fluent) record(e *Entry) {
+	buffer := newBuffer()
+	buffer.bytes = appendLogfmtEntry(buffer.bytes, f.format.get(), e, buffer.sortKeys(e.Vars))
+	writeRecord(e.Level, f.stdout, f.stderr, buffer)
 }
 
 // Format 
@@ -147,7 +110,7 @@ func (f *Fluent) Levels() []int {
 
 // Level 
 func (f *Fluent) Level() int {
-	return f.format.Level
+	return f.format.get().Level
 }
 
 // IsLevel 
@@ -160,7 +123,9 @@ func (f *Fluent) SetLevel(l int) error {
 	var err error
 
 	if f.IsLevel(l) {
-		f.format.Level = l
+		f.format.set(func(format *Formatter) {
+			format.Level = l
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL)
 		f.Errorv(err, Vars{
@@ -178,7 +143,7 @@ func (f *Fluent) LevelNames() []string {
 
 // LevelName 
 func (f *Fluent) LevelName() string {
-	return levelNames[f.format.Level]
+	return levelNames[f.format.get().Level]
 }
 
 // IsLevelName 
@@ -192,7 +157,9 @@ func (f *Fluent) SetLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if f.IsLevelName(l) {
-		f.format.Level = sliceIndex(levelNames, l)
+		f.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL_NAME)
 		f.Errorv(err, Vars{
@@ -205,73 +172,85 @@ func (f *Fluent) SetLevelName(l string) error {
 
 // Labels 
 func (f *Fluent) Labels() string {
-	return f.format.Labels.String
+	return f.format.get().Labels.String
 }
 
 // SetLabels 
 func (f *Fluent) SetLabels(l string) {
-	f.format.Labels.String = l
+	f.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
 }
 
 // LabelsSeparator 
 func (f *Fluent) LabelsSeparator() string {
-	return f.format.Labels.Separator
+	return f.format.get().Labels.Separator
 }
 
 // SetLabelsSeparator 
 func (f *Fluent) SetLabelsSeparator(spr string) {
-	f.format.Labels.Separator = spr
+	f.format.set(func(format *Formatter) {
+		format.Labels.Separator = spr
+	})
 }
 
 // LabelsToString 
 func (f *Fluent) LabelsToString(l []string) string {
-	return strings.Join(l, f.format.Labels.Separator)
+	return strings.Join(l, f.format.get().Labels.Separator)
 }
 
 // LabelsToSlice 
 func (f *Fluent) LabelsToSlice(l string) []string {
-	return strings.Split(l, f.format.Labels.Separator)
+	return strings.Split(l, f.format.get().Labels.Separator)
 }
 
 // Environment 
 func (f *Fluent) Environment() string {
-	return f.format.Environment
+	return f.format.get().Environment
 }
 
 // SetEnvironment 
 func (f *Fluent) SetEnvironment(e string) {
-	f.format.Environment = strings.TrimSpace(e)
+	f.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
 }
 
 // Tag 
 func (f *Fluent) Tag() string {
-	return f.format.Tag
+	return f.format.get().Tag
 }
 
 // SetTag 
 func (f *Fluent) SetTag(t string) {
-	f.format.Tag = strings.TrimSpace(t)
+	f.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(t)
+	})
 }
 
 // IsTimeUTC 
 func (f *Fluent) IsTimeUTC() bool {
-	return f.format.Time.IsUTC
+	return f.format.get().Time.IsUTC
 }
 
 // SetTimeUTC 
 func (f *Fluent) SetTimeUTC(u bool) {
-	f.format.Time.IsUTC = u
+	f.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
 }
 
 // IsTimeStamp 
 func (f *Fluent) IsTimeStamp() bool {
-	return f.format.Time.IsStamp
+	return f.format.get().Time.IsStamp
 }
 
 // SetTimeStamp 
 func (f *Fluent) SetTimeStamp(t bool) {
 	t = false
-	f.format.Time.IsStamp = t
+	f.format.set(func(format *Formatter) {
+		format.Time.IsStamp = t
+	})
 }
 
 // TimeStampLevels 
@@ -281,7 +260,7 @@ func (f *Fluent) TimeStampLevels() []int {
 
 // TimeStampLevel 
 func (f *Fluent) TimeStampLevel() int {
-	return f.format.Time.StampLevel
+	return f.format.get().Time.StampLevel
 }
 
 // IsTimeStampLevel 
@@ -294,7 +273,10 @@ func (f *Fluent) SetTimeStampLevel(l int) error {
 	var err error
 
 	if f.IsTimeStampLevel(l) {
-		f.format.Time.StampLevel = l
+		f.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
 		f.Errorv(err, Vars{
@@ -312,7 +294,7 @@ func (f *Fluent) TimeStampLevelNames() []string {
 
 // TimeStampLevelName 
 func (f *Fluent) TimeStampLevelName() string {
-	return timeStampLevelNames[f.format.Level]
+	return timeStampLevelNames[f.format.get().Time.StampLevel]
 }
 
 // IsTimeStampLevelName 
@@ -326,7 +308,10 @@ func (f *Fluent) SetTimeStampLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if f.IsTimeStampLevelName(l) {
-		f.format.Level = sliceIndex(timeStampLevelNames, l)
+		f.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
 		f.Errorv(err, Vars{
@@ -339,237 +324,235 @@ func (f *Fluent) SetTimeStampLevelName(l string) error {
 
 // TimeFormat 
 func (f *Fluent) TimeFormat() string {
-	return f.format.Time.Format
+	return f.format.get().Time.Format
 }
 
 // SetTimeFormat 
 func (f *Fluent) SetTimeFormat(s string) {
-	f.format.Time.Format = s
+	f.format.set(func(format *Formatter) {
+		format.Time.Format = s
+	})
 }
 
 // Panic 
 func (f *Fluent) Panic(e error) {
-	if f.format.Level >= PANIC_LEVEL {
-		f.stderr.Panic(f.build(PANIC_LEVEL, e.Error(), nil))
+	if f.format.get().Level >= PANIC_LEVEL {
+		f.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Panicv 
 func (f *Fluent) Panicv(e error, v Vars) {
-	if f.format.Level >= PANIC_LEVEL {
-		f.stderr.Panic(f.build(PANIC_LEVEL, e.Error(), v))
+	if f.format.get().Level >= PANIC_LEVEL {
+		f.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Panicf 
 func (f *Fluent) Panicf(e error, i ...interface{}) {
-	if f.format.Level >= PANIC_LEVEL {
-		f.stderr.Panic(f.build(PANIC_LEVEL, fmt.Sprintf(e.Error(), i...), nil))
+	if f.format.get().Level >= PANIC_LEVEL {
+		f.write(newEntry(PANIC_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Panicln 
 func (f *Fluent) Panicln(i ...interface{}) {
-	if f.format.Level >= PANIC_LEVEL {
-		f.stderr.Panic(f.build(PANIC_LEVEL, fmt.Sprintln(i...), nil))
+	if f.format.get().Level >= PANIC_LEVEL {
+		f.write(newEntry(PANIC_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Fatal 
 func (f *Fluent) Fatal(e error) {
-	if f.format.Level >= FATAL_LEVEL {
-		f.stderr.Fatal(f.build(FATAL_LEVEL, e.Error(), nil))
+	if f.format.get().Level >= FATAL_LEVEL {
+		f.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Fatalv 
 func (f *Fluent) Fatalv(e error, v Vars) {
-	if f.format.Level >= FATAL_LEVEL {
-		f.stderr.Fatal(f.build(FATAL_LEVEL, e.Error(), v))
+	if f.format.get().Level >= FATAL_LEVEL {
+		f.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Fatalf 
 func (f *Fluent) Fatalf(e error, i ...interface{}) {
-	if f.format.Level >= FATAL_LEVEL {
-		f.stderr.Fatal(f.build(FATAL_LEVEL, fmt.Sprintf(e.Error(), i...), nil))
+	if f.format.get().Level >= FATAL_LEVEL {
+		f.write(newEntry(FATAL_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Fatalln 
 func (f *Fluent) Fatalln(i ...interface{}) {
-	if f.format.Level >= FATAL_LEVEL {
-		f.stderr.Fatal(f.build(FATAL_LEVEL, fmt.Sprintln(i...), nil))
+	if f.format.get().Level >= FATAL_LEVEL {
+		f.write(newEntry(FATAL_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Error 
 func (f *Fluent) Error(e error) {
-	if f.format.Level >= ERROR_LEVEL {
-		f.stderr.Print(f.build(ERROR_LEVEL, e.Error(), nil))
+	if f.format.get().Level >= ERROR_LEVEL {
+		f.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Errorv 
 func (f *Fluent) Errorv(e error, v Vars) {
-	if f.format.Level >= ERROR_LEVEL {
-		f.stderr.Print(f.build(ERROR_LEVEL, e.Error(), v))
+	if f.format.get().Level >= ERROR_LEVEL {
+		f.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Errorf 
 func (f *Fluent) Errorf(e error, i ...interface{}) {
-	if f.format.Level >= ERROR_LEVEL {
-		f.stderr.Print(f.build(ERROR_LEVEL, fmt.Sprintf(e.Error(), i...), nil))
+	if f.format.get().Level >= ERROR_LEVEL {
+		f.write(newEntry(ERROR_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Errorln 
 func (f *Fluent) Errorln(i ...interface{}) {
-	if f.format.Level >= ERROR_LEVEL {
-		f.stderr.Print(f.build(ERROR_LEVEL, fmt.Sprintln(i...), nil))
+	if f.format.get().Level >= ERROR_LEVEL {
+		f.write(newEntry(ERROR_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Warn 
 func (f *Fluent) Warn(s string) {
-	if f.format.Level >= WARN_LEVEL {
-		f.stdout.Print(f.build(WARN_LEVEL, s, nil))
+	if f.format.get().Level >= WARN_LEVEL {
+		f.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Warnv 
 func (f *Fluent) Warnv(m string, v Vars) {
-	if f.format.Level >= WARN_LEVEL {
-		f.stdout.Print(f.build(WARN_LEVEL, m, v))
+	if f.format.get().Level >= WARN_LEVEL {
+		f.write(newEntry(WARN_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Warnf 
 func (f *Fluent) Warnf(m string, i ...interface{}) {
-	if f.format.Level >= WARN_LEVEL {
-		f.stdout.Print(f.build(WARN_LEVEL, fmt.Sprintf(m, i...), nil))
+	if f.format.get().Level >= WARN_LEVEL {
+		f.write(newEntry(WARN_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Warnln 
 func (f *Fluent) Warnln(i ...interface{}) {
-	if f.format.Level >= WARN_LEVEL {
-		f.stdout.Print(f.build(WARN_LEVEL, fmt.Sprintln(i...), nil))
+	if f.format.get().Level >= WARN_LEVEL {
+		f.write(newEntry(WARN_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Info 
 func (f *Fluent) Info(m string) {
-	if f.format.Level >= INFO_LEVEL {
-		f.stdout.Print(f.build(INFO_LEVEL, m, nil))
+	if f.format.get().Level >= INFO_LEVEL {
+		f.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, nil))
 	}
 }
 
 // Infov 
 func (f *Fluent) Infov(m string, v Vars) {
-	if f.format.Level >= INFO_LEVEL {
-		f.stdout.Print(f.build(INFO_LEVEL, m, v))
+	if f.format.get().Level >= INFO_LEVEL {
+		f.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Infof 
 func (f *Fluent) Infof(m string, i ...interface{}) {
-	if f.format.Level >= INFO_LEVEL {
-		f.stdout.Print(f.build(INFO_LEVEL, fmt.Sprintf(m, i...), nil))
+	if f.format.get().Level >= INFO_LEVEL {
+		f.write(newEntry(INFO_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Infoln 
 func (f *Fluent) Infoln(i ...interface{}) {
-	if f.format.Level >= INFO_LEVEL {
-		f.stdout.Print(f.build(INFO_LEVEL, fmt.Sprintln(i...), nil))
+	if f.format.get().Level >= INFO_LEVEL {
+		f.write(newEntry(INFO_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Debug 
 func (f *Fluent) Debug(m string) {
-	if f.format.Level >= DEBUG_LEVEL {
-		f.stdout.Print(f.build(DEBUG_LEVEL, m, nil))
+	if f.format.get().Level >= DEBUG_LEVEL {
+		f.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, nil))
 	}
 }
 
 // Debugv 
 func (f *Fluent) Debugv(m string, v Vars) {
-	if f.format.Level >= DEBUG_LEVEL {
-		f.stdout.Print(f.build(DEBUG_LEVEL, m, v))
+	if f.format.get().Level >= DEBUG_LEVEL {
+		f.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Debugf 
 func (f *Fluent) Debugf(m string, i ...interface{}) {
-	if f.format.Level >= DEBUG_LEVEL {
-		f.stdout.Print(f.build(DEBUG_LEVEL, fmt.Sprintf(m, i...), nil))
+	if f.format.get().Level >= DEBUG_LEVEL {
+		f.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Debugln 
 func (f *Fluent) Debugln(i ...interface{}) {
-	if f.format.Level >= DEBUG_LEVEL {
-		f.stdout.Print(f.build(DEBUG_LEVEL, fmt.Sprintln(i...), nil))
+	if f.format.get().Level >= DEBUG_LEVEL {
+		f.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Trace 
 func (f *Fluent) Trace(m string) {
-	if f.format.Level >= TRACE_LEVEL {
-		f.stdout.Print(f.build(TRACE_LEVEL, m, nil))
+	if f.format.get().Level >= TRACE_LEVEL {
+		f.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, nil))
 	}
 }
 
 // Tracev 
 func (f *Fluent) Tracev(m string, v Vars) {
-	if f.format.Level >= TRACE_LEVEL {
-		f.stdout.Print(f.build(TRACE_LEVEL, m, v))
+	if f.format.get().Level >= TRACE_LEVEL {
+		f.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Tracef 
 func (f *Fluent) Tracef(m string, i ...interface{}) {
-	if f.format.Level >= TRACE_LEVEL {
-		f.stdout.Print(f.build(TRACE_LEVEL, fmt.Sprintf(m, i...), nil))
+	if f.format.get().Level >= TRACE_LEVEL {
+		f.write(newEntry(TRACE_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Traceln 
 func (f *Fluent) Traceln(i ...interface{}) {
-	if f.format.Level >= TRACE_LEVEL {
-		f.stdout.Print(f.build(TRACE_LEVEL, fmt.Sprintln(i...), nil))
+	if f.format.get().Level >= TRACE_LEVEL {
+		f.write(newEntry(TRACE_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Print 
 func (f *Fluent) Print(m string) {
-	f.stdout.Print(f.build(PRINT_LEVEL, m, nil))
+	f.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, nil))
 }
 
 // Printv 
 func (f *Fluent) Printv(m string, v Vars) {
-	f.stdout.Print(f.build(PRINT_LEVEL, m, v))
+	f.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, v))
 }
 
 // Printf 
 func (f *Fluent) Printf(m string, i ...interface{}) {
-	f.stdout.Print(f.build(PRINT_LEVEL, fmt.Sprintf(m, i...), nil))
+	f.write(newEntry(PRINT_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 }
 
 // Println 
 func (f *Fluent) Println(i ...interface{}) {
-	f.stdout.Print(f.build(PRINT_LEVEL, fmt.Sprintln(i...), nil))
+	f.write(newEntry(PRINT_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 }
 
-// Close 
+// Close keeps state, because logger can still be used by concurrent callers
 func (f *Fluent) Close() error {
-	if f != nil {
-		f.format = nil
-		f.settings = nil
-		f = nil
-	}
+	f.format.flush()
 
 	return nil
 }
@@ -1,2 +1,34 @@
 package logs
 
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFluentWrite(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerFluent(&Formatter{Level: INFO_LEVEL, Tag: "api", Keys: &Keys{Position: KEYS_POSITION_FIRST}}, w))
+
+	ls.Warnw("slow", Int("ms", 250), String("msg", "field"), Any("ids", []int{1, 2}))
+
+	messages := parseLogfmt(t, w)
+	if len(messages) != 1 {
+		t.Fatalf("unexpected records %q", w.String())
+	}
+	m := messages[0]
+	for k, v := range map[string]string{
+		"level":      "warn",
+		"msg":        "slow",
+		"tag":        "api",
+		"ms":         "250",
+		"fields.msg": "field",
+		"ids":        "[1,2]",
+	} {
+		if m.Values[k] != v {
+			t.Errorf("%s is %q in %s", k, m.Values[k], m.Raw)
+		}
+	}
+	if m.Keys[0] != "time" || m.Keys[len(m.Keys) - 1] != "ids" {
+		t.Errorf("unexpected order of keys %v", m.Keys)
+	}
+}
@@ -1,2 +1,62 @@
 package logs
 
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"bctrader/logs/logstest/receivers"
+)
+
+// parseLogfmt decodes lines of logfmt records
+func parseLogfmt(t *testing.T, w *bytes.Buffer) []receivers.LogfmtMessage {
+	t.Helper()
+	var messages []receivers.LogfmtMessage
+
+	for _, line := range strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n") {
+		m, err := receivers.ParseLogfmt([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, m)
+	}
+
+	return messages
+}
+
+func TestFMTWrite(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerFMT(&Formatter{Level: INFO_LEVEL, Tag: "api", Environment: "prod", Labels: &Labels{"region=eu", LABELS_SEPARATOR}}, w))
+
+	ls.Named("http").Infov("request \"done\"\nok", Vars{"status": 200, "path": "/a b", "tag": "field", "took": 1.5})
+	ls.Errorw(errors.New("failed"), String("k", "v"))
+	ls.Debug("skipped")
+
+	messages := parseLogfmt(t, w)
+	if len(messages) != 2 {
+		t.Fatalf("unexpected records %q", w.String())
+	}
+	for k, v := range map[string]string{
+		"level":      "info",
+		"msg":        "request \"done\"\nok",
+		"logger":     "http",
+		"env":        "prod",
+		"tag":        "api",
+		"labels":     "region=eu",
+		"status":     "200",
+		"path":       "/a b",
+		"fields.tag": "field",
+		"took":       "1.5",
+	} {
+		if messages[0].Values[k] != v {
+			t.Errorf("%s is %q in %s", k, messages[0].Values[k], messages[0].Raw)
+		}
+	}
+	if keys := strings.Join(messages[0].Keys, ","); !strings.HasPrefix(keys, "path,status,fields.tag,took,time,level") {
+		t.Errorf("unexpected order of keys %s", keys)
+	}
+	if m := messages[1]; m.Values["level"] != "error" || m.Values["msg"] != "failed" || m.Values["k"] != "v" {
+		t.Errorf("unexpected record %s", m.Raw)
+	}
+}
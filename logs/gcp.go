@@ -0,0 +1,134 @@
+package logs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GCP keys of structured logging of Cloud Logging
+const (
+	GCP_KEY_SEVERITY        = "severity"
+	GCP_KEY_MESSAGE         = "message"
+	GCP_KEY_TIME            = "time"
+	GCP_KEY_LABELS          = "logging.googleapis.com/labels"
+	GCP_KEY_SOURCE_LOCATION = "logging.googleapis.com/sourceLocation"
+	GCP_KEY_TRACE           = "logging.googleapis.com/trace"
+	GCP_KEY_SPAN_ID         = "logging.googleapis.com/spanId"
+	GCP_KEY_TRACE_SAMPLED   = "logging.googleapis.com/trace_sampled"
+	GCP_TRACE_PROJECTS      = "projects/"
+	GCP_TRACE_TRACES        = "/traces/"
+	GCP_LABEL_SEPARATOR     = ECS_LABEL_SEPARATOR
+)
+
+// gcpSeverities are severities of levels, print level has default severity
+var gcpSeverities = []string{"ALERT", "CRITICAL", "ERROR", "WARNING", "INFO", "DEBUG", "DEBUG", "DEFAULT"}
+
+// gcpKeysNames are names of fixed keys by Cloud Logging, other fixed keys
+// are labels
+var gcpKeysNames = KeysNames{
+	Level:     GCP_KEY_SEVERITY,
+	Message:   GCP_KEY_MESSAGE,
+	Time:      GCP_KEY_TIME,
+	Timestamp: GCP_KEY_TIME,
+}
+
+// defaultGCPKeys sets Cloud Logging names of fixed keys which are not set yet
+func defaultGCPKeys(f *Formatter) {
+	defaultProfileKeys(f, &gcpKeysNames)
+}
+
+// appendGCPEntry appends entry as JSON object of structured logging of
+// Cloud Logging. Environment, tag, logger and "key=value" labels go to
+// labels, caller is source location and trace context is trace of project.
+func appendGCPEntry(b []byte, format *Formatter, e *Entry, keys []string, project string) []byte {
+	b = append(b, '{')
+	if isKeysFirst(format, true) {
+		b = appendGCPFixedKeys(b, format, e, project)
+		b = appendJSONFields(b, format, e, keys)
+	} else {
+		b = appendJSONFields(b, format, e, keys)
+		b = appendGCPFixedKeys(b, format, e, project)
+	}
+
+	return append(b, '}')
+}
+
+// appendGCPFixedKeys 
+func appendGCPFixedKeys(b []byte, format *Formatter, e *Entry, project string) []byte {
+	if !isOverwritten(format, e, format.Keys.Names.Time) {
+		b = appendJSONFixedKey(b, format.Keys.Names.Time)
+		b = append(b, '"')
+		b = e.Time.UTC().AppendFormat(b, TIME_FORMAT_RFC3339_NANO)
+		b = append(b, '"')
+	}
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Level, gcpSeverities[e.Level])
+	b = appendJSONFixed(b, format, e, format.Keys.Names.Message, e.Message)
+	b = appendGCPLabels(b, format, e)
+
+	if frame := caller(); frame.File != EMPTY_STRING {
+		b = appendJSONFixedKey(b, GCP_KEY_SOURCE_LOCATION)
+		b = append(b, `{"file":`...)
+		b = appendJSONString(b, frame.File)
+		b = append(b, `,"line":"`...)
+		b = strconv.AppendInt(b, int64(frame.Line), 10)
+		b = append(b, `","function":`...)
+		b = appendJSONString(b, frame.Function)
+		b = append(b, '}')
+	}
+
+	if e.Trace.IsValid() {
+		b = appendJSONFixedKey(b, GCP_KEY_TRACE)
+		b = append(b, '"')
+		if project != EMPTY_STRING {
+			b = append(b, GCP_TRACE_PROJECTS...)
+			b = appendJSONStringBody(b, project)
+			b = append(b, GCP_TRACE_TRACES...)
+		}
+		b = e.Trace.appendPart(b, 0)
+		b = append(b, '"')
+		b = appendJSONFixedKey(b, GCP_KEY_SPAN_ID)
+		b = append(b, '"')
+		b = e.Trace.appendPart(b, 1)
+		b = append(b, '"')
+		b = appendJSONFixedKey(b, GCP_KEY_TRACE_SAMPLED)
+		b = strconv.AppendBool(b, e.Trace.IsSampled())
+	}
+
+	return b
+}
+
+// appendGCPLabels appends labels object, it is skipped when it is empty
+func appendGCPLabels(b []byte, format *Formatter, e *Entry) []byte {
+	start := len(b)
+	b = appendJSONFixedKey(b, GCP_KEY_LABELS)
+	b = append(b, '{')
+	open := len(b)
+
+	for _, label := range []struct {
+		key   string
+		value string
+	}{
+		{format.Keys.Names.Environment, format.Environment},
+		{format.Keys.Names.Tag, format.Tag},
+		{format.Keys.Names.Logger, e.Name},
+	} {
+		if label.value != EMPTY_STRING {
+			b = appendJSONFixedKey(b, label.key)
+			b = appendJSONString(b, label.value)
+		}
+	}
+	if format.Labels.String != EMPTY_STRING {
+		for _, label := range strings.Split(format.Labels.String, format.Labels.Separator) {
+			if key, value, ok := strings.Cut(strings.TrimSpace(label), GCP_LABEL_SEPARATOR); ok && key != EMPTY_STRING {
+				b = appendJSONFixedKey(b, key)
+				b = appendJSONString(b, value)
+			}
+		}
+	}
+
+	if len(b) == open {
+		return b[:start]
+	}
+
+	return append(b, '}')
+}
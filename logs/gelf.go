@@ -6,7 +6,6 @@ import (
 	"time"
 	"errors"
 	"strings"
-	"encoding/json"
 
 	"gopkg.in/go-logs/gelf.v3/gelf"
 )
@@ -74,7 +73,7 @@ type GELFSettings struct {
 
 // GELF 
 type GELF struct {
-	format   *Formatter
+	format   *formatState
 	settings *GELFSettings
 	writer   gelf.Writer
 }
@@ -234,6 +233,7 @@ func NewGELF(s *GELFSettings, f ...*Formatter) (*GELF, error) {
 	var (
 		format *Formatter
 		settings *GELFSettings
+		writer gelf.Writer
 	)
 
 	if s != nil {
@@ -256,11 +256,14 @@ func NewGELF(s *GELFSettings, f ...*Formatter) (*GELF, error) {
 		format.Keys.PrefixSeparator = GELF_KEYS_PREFIX_SEPARATOR
 	}
 
-	writer, err := gelfWriter(settings)
+	err := redactCheck(format.Redact)
 
+	if err == nil {
+		writer, err = gelfWriter(settings)
+	}
 	if err == nil {
 		return &GELF{
-			format,
+			newFormatState(format),
 			settings,
 			writer,
 		}, nil
@@ -274,7 +277,7 @@ func NewGELF(s *GELFSettings, f ...*Formatter) (*GELF, error) {
 
 // timeStampLevel 
 func (g *GELF) timeStampLevel(tm int64) float64 {
-	switch g.format.Time.StampLevel {
+	switch g.format.get().Time.StampLevel {
 	case TIME_STAMP_LEVEL_MILLI:
 		return float64(tm) / float64(time.Microsecond)
 	case TIME_STAMP_LEVEL_MICRO:
@@ -288,64 +291,177 @@ func (g *GELF) timeStampLevel(tm int64) float64 {
 
 // timeStamp 
 func (g *GELF) timeStamp(tt time.Time) float64 {
-	if g.format.Time.IsUTC {
+	if g.format.get().Time.IsUTC {
 		tt = tt.UTC()
 	}
 
-	return g.timeStampLevel(timeStampLevel(g.format.Time.StampLevel, tt))
+	return g.timeStampLevel(timeStampLevel(g.format.get().Time.StampLevel, tt))
 }
 
-// build 
-func (g *GELF) build(l int, s string, v Vars) {
-	if v == nil {
-		v = Vars{}
-	} else {
-		for key, value := range v {
-			switch key {
-			case g.format.Keys.Names.Labels, g.format.Keys.Names.Environment, g.format.Keys.Names.Tag:
-				v[g.format.Keys.PrefixSeparator+g.format.Keys.Prefix+g.format.Keys.PrefixSeparator+key] = value
-				delete(v, key)
-			}
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (g *GELF) write(e *Entry) {
+	g.format.write(g, e)
+}
+
+// formatter returns current snapshot of Formatter
+func (g *GELF) formatter() *Formatter {
+	return g.format.get()
+}
+
+// isReservedKey tells if key is one of fixed additional fields
+func (g *GELF) isReservedKey(format *Formatter, k string) bool {
+	switch k {
+	case format.Keys.Names.Labels, format.Keys.Names.Environment, format.Keys.Names.Tag, format.Keys.Names.Logger:
+		return true
+	}
+
+	return false
+}
+
+// isOverwritten tells if fixed additional field is replaced by field of entry
+func (g *GELF) isOverwritten(format *Formatter, e *Entry, k string) bool {
+	return g.isReservedKey(format, k) && isOverwritten(format, e, k)
+}
+
+// isKeptKey is false for reserved key of field when collision is error
+func (g *GELF) isKeptKey(format *Formatter, k string) bool {
+	if format.Keys.Collision == KEYS_COLLISION_ERROR && g.isReservedKey(format, k) {
+		keyCollision(format, k)
+		return false
+	}
+
+	return true
+}
+
+// appendKey appends key of additional field, keys of fixed fields are
+// prefixed or take place of fixed field when it is overwritten
+func (g *GELF) appendKey(b []byte, format *Formatter, k string) []byte {
+	if b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+	b = append(b, '"')
+	if g.isReservedKey(format, k) {
+		b = appendJSONStringBody(b, format.Keys.PrefixSeparator)
+		if format.Keys.Collision != KEYS_COLLISION_OVERWRITE {
+			b = appendJSONStringBody(b, format.Keys.Prefix)
+			b = appendJSONStringBody(b, format.Keys.PrefixSeparator)
 		}
 	}
+	b = appendJSONStringBody(b, k)
+
+	return append(b, '"', ':')
+}
 
-	if g.format.Environment != EMPTY_STRING {
-		v[g.format.Keys.PrefixSeparator+g.format.Keys.Names.Environment] = g.format.Environment
+// appendFixed appends fixed field, empty value and field overwritten by
+// field of entry are skipped
+func (g *GELF) appendFixed(b []byte, format *Formatter, e *Entry, k string, v string) []byte {
+	if v == EMPTY_STRING || g.isOverwritten(format, e, k) {
+		return b
 	}
-	if g.format.Labels.String != EMPTY_STRING {
-		v[g.format.Keys.PrefixSeparator+g.format.Keys.Names.Labels] = g.format.Labels.String
+	if b[len(b)-1] != '{' {
+		b = append(b, ',')
 	}
-	if g.format.Tag != EMPTY_STRING {
-		v[g.format.Keys.PrefixSeparator+g.format.Keys.Names.Tag] = g.format.Tag
+	b = append(b, '"')
+	b = appendJSONStringBody(b, format.Keys.PrefixSeparator)
+	b = appendJSONStringBody(b, k)
+	b = append(b, '"', ':')
+
+	return appendJSONString(b, v)
+}
+
+// appendFixedKeys appends fixed fields, trace context is written as
+// _trace_id, _span_id and _trace_flags
+func (g *GELF) appendFixedKeys(b []byte, format *Formatter, e *Entry) []byte {
+	b = g.appendFixed(b, format, e, format.Keys.Names.Environment, format.Environment)
+	b = g.appendFixed(b, format, e, format.Keys.Names.Labels, format.Labels.String)
+	b = g.appendFixed(b, format, e, format.Keys.Names.Tag, format.Tag)
+	b = g.appendFixed(b, format, e, format.Keys.Names.Logger, e.Name)
+	for _, f := range traceFields(format, e) {
+		b = g.appendFixed(b, format, e, f.Key, f.String)
 	}
 
-	rawExtra, err := json.Marshal(&v)
-	if err == nil {
-		msg := &gelf.Message{
-			Version:  GELF_PROTOCOL_VERSION,
-			Host:     g.settings.Hostname,
-			Short:    s,
-			TimeUnix: g.timeStamp(time.Now()),
-			Level:    gelfLevels[l],
-			RawExtra: rawExtra,
+	return b
+}
+
+// appendFields 
+func (g *GELF) appendFields(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() && g.isKeptKey(format, f.Key) {
+			b = g.appendField(b, format, f.Key, &f)
 		}
-		if g.settings.Facility != EMPTY_STRING {
-			msg.Facility = g.settings.Facility
+	}
+	for _, key := range keys {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() && g.isKeptKey(format, key) {
+			b = g.appendField(b, format, key, &f)
 		}
+	}
+
+	return b
+}
 
-		err = g.writer.WriteMessage(msg)
-		msg = nil
+// appendField appends resolved field, objects are flattened to dotted keys
+func (g *GELF) appendField(b []byte, format *Formatter, k string, f *Field) []byte {
+	if f.Type == FIELD_TYPE_OBJECT {
+		fields := objectFields(nil, f)
+		for i := 0; i < len(fields); i++ {
+			if !fields[i].isSkipped() {
+				b = g.appendField(b, format, k+OBJECT_KEY_SEPARATOR+fields[i].Key, &fields[i])
+			}
+		}
+		return b
 	}
-	rawExtra = nil
+	b = g.appendKey(b, format, k)
 
-	if err != nil && g.format.Stderr.IsPrintable {
+	return appendJSONValue(b, format, f)
+}
+
+// appendExtra appends additional fields of entry as JSON object. Fields
+// keep their order, vars are written in order of keys.
+func (g *GELF) appendExtra(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	b = append(b, '{')
+	if isKeysFirst(format, true) {
+		b = g.appendFixedKeys(b, format, e)
+		b = g.appendFields(b, format, e, keys)
+	} else {
+		b = g.appendFields(b, format, e, keys)
+		b = g.appendFixedKeys(b, format, e)
+	}
+
+	return append(b, '}')
+}
+
+// record encodes additional fields into pooled buffer and sends message
+func (g *GELF) record(e *Entry) {
+	l, s := e.Level, e.Message
+	format := g.format.get()
+	buffer := newBuffer()
+	buffer.bytes = g.appendExtra(buffer.bytes, format, e, buffer.sortKeys(e.Vars))
+
+	msg := &gelf.Message{
+		Version:  GELF_PROTOCOL_VERSION,
+		Host:     g.settings.Hostname,
+		Short:    s,
+		TimeUnix: g.timeStamp(time.Now()),
+		Level:    gelfLevels[l],
+		RawExtra: buffer.bytes,
+	}
+	if g.settings.Facility != EMPTY_STRING {
+		msg.Facility = g.settings.Facility
+	}
+
+	err := g.writer.WriteMessage(msg)
+	msg = nil
+	buffer.free()
+
+	if err != nil && format.Stderr.IsPrintable {
 		switch l {
 		case PANIC_LEVEL:
-			g.format.Stderr.Logger.Panic(err.Error())
+			format.Stderr.Logger.Panic(err.Error())
 		case FATAL_LEVEL:
-			g.format.Stderr.Logger.Fatal(err.Error())
+			format.Stderr.Logger.Fatal(err.Error())
 		default:
-			g.format.Stderr.Logger.Print(err.Error())
+			format.Stderr.Logger.Print(err.Error())
 		}
 	}
 }
@@ -367,7 +483,7 @@ func (g *GELF) Levels() []int {
 
 // Level 
 func (g *GELF) Level() int {
-	return g.format.Level
+	return g.format.get().Level
 }
 
 // IsLevel 
@@ -380,7 +496,9 @@ func (g *GELF) SetLevel(l int) error {
 	var err error
 
 	if g.IsLevel(l) {
-		g.format.Level = l
+		g.format.set(func(format *Formatter) {
+			format.Level = l
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL)
 		g.Errorv(err, Vars{
@@ -398,7 +516,7 @@ func (g *GELF) LevelNames() []string {
 
 // LevelName 
 func (g *GELF) LevelName() string {
-	return levelNames[g.format.Level]
+	return levelNames[g.format.get().Level]
 }
 
 // IsLevelName 
@@ -412,7 +530,9 @@ func (g *GELF) SetLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if g.IsLevelName(l) {
-		g.format.Level = sliceIndex(levelNames, l)
+		g.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL_NAME)
 		g.Errorv(err, Vars{
@@ -425,72 +545,84 @@ func (g *GELF) SetLevelName(l string) error {
 
 // Labels 
 func (g *GELF) Labels() string {
-	return g.format.Labels.String
+	return g.format.get().Labels.String
 }
 
 // SetLabels 
 func (g *GELF) SetLabels(l string) {
-	g.format.Labels.String = l
+	g.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
 }
 
 // LabelsSeparator 
 func (g *GELF) LabelsSeparator() string {
-	return g.format.Labels.Separator
+	return g.format.get().Labels.Separator
 }
 
 // SetLabelsSeparator 
 func (g *GELF) SetLabelsSeparator(s string) {
-	g.format.Labels.Separator = s
+	g.format.set(func(format *Formatter) {
+		format.Labels.Separator = s
+	})
 }
 
 // LabelsToString 
 func (g *GELF) LabelsToString(l []string) string {
-	return strings.Join(l, g.format.Labels.Separator)
+	return strings.Join(l, g.format.get().Labels.Separator)
 }
 
 // LabelsToSlice 
 func (g *GELF) LabelsToSlice(l string) []string {
-	return strings.Split(l, g.format.Labels.Separator)
+	return strings.Split(l, g.format.get().Labels.Separator)
 }
 
 // Environment 
 func (g *GELF) Environment() string {
-	return g.format.Environment
+	return g.format.get().Environment
 }
 
 // SetEnvironment 
 func (g *GELF) SetEnvironment(e string) {
-	g.format.Environment = strings.TrimSpace(e)
+	g.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
 }
 
 // Tag 
 func (g *GELF) Tag() string {
-	return g.format.Tag
+	return g.format.get().Tag
 }
 
 // SetTag 
 func (g *GELF) SetTag(t string) {
-	g.format.Tag = strings.TrimSpace(t)
+	g.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(t)
+	})
 }
 
 // IsTimeUTC 
 func (g *GELF) IsTimeUTC() bool {
-	return g.format.Time.IsUTC
+	return g.format.get().Time.IsUTC
 }
 
 // SetTimeUTC 
 func (g *GELF) SetTimeUTC(u bool) {
-	g.format.Time.IsUTC = u
+	g.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
 }
 
 // IsTimeStamp 
 func (g *GELF) IsTimeStamp() bool {
-	return g.format.Time.IsStamp
+	return g.format.get().Time.IsStamp
 }
 
 // SetTimeStamp 
 func (g *GELF) SetTimeStamp(t bool) {
-	g.format.Time.IsStamp = t
+	g.format.set(func(format *Formatter) {
+		format.Time.IsStamp = t
+	})
 }
 
 // TimeStampLevels 
@@ -500,7 +632,7 @@ func (g *GELF) TimeStampLevels() []int {
 
 // TimeStampLevel 
 func (g *GELF) TimeStampLevel() int {
-	return g.format.Time.StampLevel
+	return g.format.get().Time.StampLevel
 }
 
 // IsTimeStampLevel 
@@ -513,7 +645,10 @@ func (g *GELF) SetTimeStampLevel(l int) error {
 	var err error
 
 	if g.IsTimeStampLevel(l) {
-		g.format.Time.StampLevel = l
+		g.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
 		g.Errorv(err, Vars{
@@ -531,7 +666,7 @@ func (g *GELF) TimeStampLevelNames() []string {
 
 // TimeStampLevelName 
 func (g *GELF) TimeStampLevelName() string {
-	return timeStampLevelNames[g.format.Level]
+	return timeStampLevelNames[g.format.get().Time.StampLevel]
 }
 
 // IsTimeStampLevelName 
@@ -545,7 +680,10 @@ func (g *GELF) SetTimeStampLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if g.IsTimeStampLevelName(l) {
-		g.format.Level = sliceIndex(timeStampLevelNames, l)
+		g.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
 		g.Errorv(err, Vars{
@@ -558,246 +696,243 @@ func (g *GELF) SetTimeStampLevelName(l string) error {
 
 // TimeFormat 
 func (g *GELF) TimeFormat() string {
-	return g.format.Time.Format
+	return g.format.get().Time.Format
 }
 
 // SetTimeFormat 
 func (g *GELF) SetTimeFormat(f string) {
-	g.format.Time.Format = f
+	g.format.set(func(format *Formatter) {
+		format.Time.Format = f
+	})
 }
 
 // Panic 
 func (g *GELF) Panic(e error) {
-	if g.format.Level >= PANIC_LEVEL {
-		g.build(PANIC_LEVEL, e.Error(), nil)
+	if g.format.get().Level >= PANIC_LEVEL {
+		g.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Panicv 
 func (g *GELF) Panicv(e error, v Vars) {
-	if g.format.Level >= PANIC_LEVEL {
-		g.build(PANIC_LEVEL, e.Error(), v)
+	if g.format.get().Level >= PANIC_LEVEL {
+		g.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Panicf 
 func (g *GELF) Panicf(e error, i ...interface{}) {
-	if g.format.Level >= PANIC_LEVEL {
-		g.build(PANIC_LEVEL, fmt.Sprintf(e.Error(), i...), nil)
+	if g.format.get().Level >= PANIC_LEVEL {
+		g.write(newEntry(PANIC_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Panicln 
 func (g *GELF) Panicln(i ...interface{}) {
-	if g.format.Level >= PANIC_LEVEL {
-		g.build(PANIC_LEVEL, fmt.Sprintln(i...), nil)
+	if g.format.get().Level >= PANIC_LEVEL {
+		g.write(newEntry(PANIC_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Fatal 
 func (g *GELF) Fatal(e error) {
-	if g.format.Level >= FATAL_LEVEL {
-		g.build(FATAL_LEVEL, e.Error(), nil)
+	if g.format.get().Level >= FATAL_LEVEL {
+		g.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Fatalv 
 func (g *GELF) Fatalv(e error, v Vars) {
-	if g.format.Level >= FATAL_LEVEL {
-		g.build(FATAL_LEVEL, e.Error(), v)
+	if g.format.get().Level >= FATAL_LEVEL {
+		g.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Fatalf 
 func (g *GELF) Fatalf(e error, i ...interface{}) {
-	if g.format.Level >= FATAL_LEVEL {
-		g.build(FATAL_LEVEL, fmt.Sprintf(e.Error(), i...), nil)
+	if g.format.get().Level >= FATAL_LEVEL {
+		g.write(newEntry(FATAL_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Fatalln 
 func (g *GELF) Fatalln(i ...interface{}) {
-	if g.format.Level >= FATAL_LEVEL {
-		g.build(FATAL_LEVEL, fmt.Sprintln(i...), nil)
+	if g.format.get().Level >= FATAL_LEVEL {
+		g.write(newEntry(FATAL_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Error 
 func (g *GELF) Error(e error) {
-	if g.format.Level >= ERROR_LEVEL {
-		g.build(ERROR_LEVEL, e.Error(), nil)
+	if g.format.get().Level >= ERROR_LEVEL {
+		g.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Errorv 
 func (g *GELF) Errorv(e error, v Vars) {
-	if g.format.Level >= ERROR_LEVEL {
-		g.build(ERROR_LEVEL, e.Error(), v)
+	if g.format.get().Level >= ERROR_LEVEL {
+		g.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Errorf 
 func (g *GELF) Errorf(e error, i ...interface{}) {
-	if g.format.Level >= ERROR_LEVEL {
-		g.build(ERROR_LEVEL, fmt.Sprintf(e.Error(), i...), nil)
+	if g.format.get().Level >= ERROR_LEVEL {
+		g.write(newEntry(ERROR_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Errorln 
 func (g *GELF) Errorln(i ...interface{}) {
-	if g.format.Level >= ERROR_LEVEL {
-		g.build(ERROR_LEVEL, fmt.Sprintln(i...), nil)
+	if g.format.get().Level >= ERROR_LEVEL {
+		g.write(newEntry(ERROR_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Warn 
 func (g *GELF) Warn(s string) {
-	if g.format.Level >= WARN_LEVEL {
-		g.build(WARN_LEVEL, s, nil)
+	if g.format.get().Level >= WARN_LEVEL {
+		g.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Warnv 
 func (g *GELF) Warnv(s string, v Vars) {
-	if g.format.Level >= WARN_LEVEL {
-		g.build(WARN_LEVEL, s, v)
+	if g.format.get().Level >= WARN_LEVEL {
+		g.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Warnf 
 func (g *GELF) Warnf(s string, i ...interface{}) {
-	if g.format.Level >= WARN_LEVEL {
-		g.build(WARN_LEVEL, fmt.Sprintf(s, i...), nil)
+	if g.format.get().Level >= WARN_LEVEL {
+		g.write(newEntry(WARN_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Warnln 
 func (g *GELF) Warnln(i ...interface{}) {
-	if g.format.Level >= WARN_LEVEL {
-		g.build(WARN_LEVEL, fmt.Sprintln(i...), nil)
+	if g.format.get().Level >= WARN_LEVEL {
+		g.write(newEntry(WARN_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Info 
 func (g *GELF) Info(s string) {
-	if g.format.Level >= INFO_LEVEL {
-		g.build(INFO_LEVEL, s, nil)
+	if g.format.get().Level >= INFO_LEVEL {
+		g.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Infov 
 func (g *GELF) Infov(s string, v Vars) {
-	if g.format.Level >= INFO_LEVEL {
-		g.build(INFO_LEVEL, s, v)
+	if g.format.get().Level >= INFO_LEVEL {
+		g.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Infof 
 func (g *GELF) Infof(s string, i ...interface{}) {
-	if g.format.Level >= INFO_LEVEL {
-		g.build(INFO_LEVEL, fmt.Sprintf(s, i...), nil)
+	if g.format.get().Level >= INFO_LEVEL {
+		g.write(newEntry(INFO_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Infoln 
 func (g *GELF) Infoln(i ...interface{}) {
-	if g.format.Level >= INFO_LEVEL {
-		g.build(INFO_LEVEL, fmt.Sprintln(i...), nil)
+	if g.format.get().Level >= INFO_LEVEL {
+		g.write(newEntry(INFO_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Debug 
 func (g *GELF) Debug(s string) {
-	if g.format.Level >= DEBUG_LEVEL {
-		g.build(DEBUG_LEVEL, s, nil)
+	if g.format.get().Level >= DEBUG_LEVEL {
+		g.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Debugv 
 func (g *GELF) Debugv(s string, v Vars) {
-	if g.format.Level >= DEBUG_LEVEL {
-		g.build(DEBUG_LEVEL, s, v)
+	if g.format.get().Level >= DEBUG_LEVEL {
+		g.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Debugf 
 func (g *GELF) Debugf(s string, i ...interface{}) {
-	if g.format.Level >= DEBUG_LEVEL {
-		g.build(DEBUG_LEVEL, fmt.Sprintf(s, i...), nil)
+	if g.format.get().Level >= DEBUG_LEVEL {
+		g.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Debugln 
 func (g *GELF) Debugln(i ...interface{}) {
-	if g.format.Level >= DEBUG_LEVEL {
-		g.build(DEBUG_LEVEL, fmt.Sprintln(i...), nil)
+	if g.format.get().Level >= DEBUG_LEVEL {
+		g.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Trace 
 func (g *GELF) Trace(s string) {
-	if g.format.Level >= TRACE_LEVEL {
-		g.build(TRACE_LEVEL, s, nil)
+	if g.format.get().Level >= TRACE_LEVEL {
+		g.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Tracev 
 func (g *GELF) Tracev(s string, v Vars) {
-	if g.format.Level >= TRACE_LEVEL {
-		g.build(TRACE_LEVEL, s, v)
+	if g.format.get().Level >= TRACE_LEVEL {
+		g.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Tracef 
 func (g *GELF) Tracef(s string, i ...interface{}) {
-	if g.format.Level >= TRACE_LEVEL {
-		g.build(TRACE_LEVEL, fmt.Sprintf(s, i...), nil)
+	if g.format.get().Level >= TRACE_LEVEL {
+		g.write(newEntry(TRACE_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Traceln 
 func (g *GELF) Traceln(i ...interface{}) {
-	if g.format.Level >= TRACE_LEVEL {
-		g.build(TRACE_LEVEL, fmt.Sprintln(i...), nil)
+	if g.format.get().Level >= TRACE_LEVEL {
+		g.write(newEntry(TRACE_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Print 
 func (g *GELF) Print(s string) {
-	g.build(PRINT_LEVEL, s, nil)
+	g.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, nil))
 }
 
 // Printv 
 func (g *GELF) Printv(s string, v Vars) {
-	g.build(PRINT_LEVEL, s, v)
+	g.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, v))
 }
 
 // Printf 
 func (g *GELF) Printf(s string, i ...interface{}) {
-	g.build(PRINT_LEVEL, fmt.Sprintf(s, i...), nil)
+	g.write(newEntry(PRINT_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 }
 
 // Println 
 func (g *GELF) Println(i ...interface{}) {
-	g.build(PRINT_LEVEL, fmt.Sprintln(i...), nil)
+	g.write(newEntry(PRINT_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 }
 
-// Close 
+// Close closes writer, state is kept for callers still holding logger
 func (g *GELF) Close() error {
 	var err error
 
-	if g != nil {
-		if g.writer != nil {
-			err = g.writer.Close()
-			if err != nil && g.format.Stderr.IsPrintable {
-				g.format.Stderr.Logger.Print(err.Error())
-				err = nil
-			}
-			g.writer = nil
+	if g != nil && g.writer != nil {
+		g.format.flush()
+		err = g.writer.Close()
+		if err != nil && g.format.get().Stderr.IsPrintable {
+			g.format.get().Stderr.Logger.Print(err.Error())
+			err = nil
 		}
-		g.format = nil
-		g.settings = nil
-		g = nil
 	}
 
 	return err
@@ -1,2 +1,24 @@
 package logs
 
+import (
+	"testing"
+	"encoding/json"
+)
+
+func TestGELFExtra(t *testing.T) {
+	format := &Formatter{Tag: "tag"}
+	defaultFormatter(format, false, true)
+	format.Keys.Prefix = GELF_KEYS_PREFIX
+	format.Keys.PrefixSeparator = GELF_KEYS_PREFIX_SEPARATOR
+	e := newEntry(INFO_LEVEL, "http", "message", Vars{"b": 2, "a": 1, "tag": "reserved"})
+	e.Fields = append(e.Fields, String("z", "z"), Int("y", 1))
+	defer e.free()
+
+	extra := (&GELF{}).appendExtra(nil, format, e, []string{"a", "b", "tag"})
+	if !json.Valid(extra) {
+		t.Fatalf("invalid JSON %s", extra)
+	}
+	if s := string(extra); s != `{"_tag":"tag","_logger":"http","z":"z","y":1,"a":1,"b":2,"_fields_tag":"reserved"}` {
+		t.Errorf("unexpected extra %s", s)
+	}
+}
@@ -0,0 +1,546 @@
+package logs
+
+import (
+	"fmt"
+	"errors"
+	"strings"
+)
+
+// HOOK_NAME 
+const HOOK_NAME = "hook"
+
+// Error string messages
+const (
+	__ERROR_STR_HOOK_FUNC = "Hook function must be defined"
+)
+
+// HookSettings 
+type HookSettings struct {
+	Func func(e *Entry)
+}
+
+// Hook passes every entry to function, e.g. to observer of tests. Entry is
+// reused after call, so function copies what it keeps. Panic and fatal
+// entries do not end process.
+type Hook struct {
+	format   *formatState
+	settings *HookSettings
+}
+
+// NewHook 
+func NewHook(s *HookSettings, f ...*Formatter) (*Hook, error) {
+	var (
+		format *Formatter
+		settings *HookSettings
+	)
+
+	if s != nil {
+		settings = s
+	} else {
+		settings = &HookSettings{}
+	}
+
+	if len(f) == 0 {
+		format = &Formatter{}
+	} else {
+		format = f[0]
+	}
+	defaultFormatter(format, false, true)
+
+	err := redactCheck(format.Redact)
+
+	if err == nil && settings.Func == nil {
+		err = errors.New(__ERROR_STR_HOOK_FUNC)
+	}
+	if err == nil {
+		return &Hook{
+			format:   newFormatState(format),
+			settings: settings,
+		}, nil
+	} else {
+		if format.Stderr.IsPrintable {
+			format.Stderr.Logger.Print(err.Error())
+		}
+		return nil, err
+	}
+}
+
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (h *Hook) write(e *Entry) {
+	h.format.write(h, e)
+}
+
+// formatter returns current snapshot of Formatter
+func (h *Hook) formatter() *Formatter {
+	return h.format.get()
+}
+
+// record 
+func (h *Hook) record(e *Entry) {
+	h.settings.Func(e)
+}
+
+// Format 
+func (h *Hook) Format() int {
+	return HOOK_FORMAT
+}
+
+// FormatName 
+func (h *Hook) FormatName() string {
+	return HOOK_NAME
+}
+
+// Levels 
+func (h *Hook) Levels() []int {
+	return Levels()
+}
+
+// Level 
+func (h *Hook) Level() int {
+	return h.format.get().Level
+}
+
+// IsLevel 
+func (h *Hook) IsLevel(l int) bool {
+	return IsLevel(l)
+}
+
+// SetLevel 
+func (h *Hook) SetLevel(l int) error {
+	var err error
+
+	if h.IsLevel(l) {
+		h.format.set(func(format *Formatter) {
+			format.Level = l
+		})
+	} else {
+		err = errors.New(__ERROR_STR_LEVEL)
+		h.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: h.FormatName()})
+	}
+
+	return err
+}
+
+// LevelNames 
+func (h *Hook) LevelNames() []string {
+	return LevelNames()
+}
+
+// LevelName 
+func (h *Hook) LevelName() string {
+	return levelNames[h.format.get().Level]
+}
+
+// IsLevelName 
+func (h *Hook) IsLevelName(l string) bool {
+	return IsLevelName(l)
+}
+
+// SetLevelName 
+func (h *Hook) SetLevelName(l string) error {
+	var err error
+
+	l = strings.ToLower(strings.TrimSpace(l))
+	if h.IsLevelName(l) {
+		h.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
+	} else {
+		err = errors.New(__ERROR_STR_LEVEL_NAME)
+		h.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: h.FormatName()})
+	}
+
+	return err
+}
+
+// Labels 
+func (h *Hook) Labels() string {
+	return h.format.get().Labels.String
+}
+
+// SetLabels 
+func (h *Hook) SetLabels(l string) {
+	h.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
+}
+
+// LabelsSeparator 
+func (h *Hook) LabelsSeparator() string {
+	return h.format.get().Labels.Separator
+}
+
+// SetLabelsSeparator 
+func (h *Hook) SetLabelsSeparator(spr string) {
+	h.format.set(func(format *Formatter) {
+		format.Labels.Separator = spr
+	})
+}
+
+// LabelsToString 
+func (h *Hook) LabelsToString(l []string) string {
+	return strings.Join(l, h.format.get().Labels.Separator)
+}
+
+// LabelsToSlice 
+func (h *Hook) LabelsToSlice(l string) []string {
+	return strings.Split(l, h.format.get().Labels.Separator)
+}
+
+// Environment 
+func (h *Hook) Environment() string {
+	return h.format.get().Environment
+}
+
+// SetEnvironment 
+func (h *Hook) SetEnvironment(e string) {
+	h.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
+}
+
+// Tag 
+func (h *Hook) Tag() string {
+	return h.format.get().Tag
+}
+
+// SetTag 
+func (h *Hook) SetTag(t string) {
+	h.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(t)
+	})
+}
+
+// IsTimeUTC 
+func (h *Hook) IsTimeUTC() bool {
+	return h.format.get().Time.IsUTC
+}
+
+// SetTimeUTC 
+func (h *Hook) SetTimeUTC(u bool) {
+	h.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
+}
+
+// IsTimeStamp 
+func (h *Hook) IsTimeStamp() bool {
+	return h.format.get().Time.IsStamp
+}
+
+// SetTimeStamp 
+func (h *Hook) SetTimeStamp(t bool) {
+	t = false
+	h.format.set(func(format *Formatter) {
+		format.Time.IsStamp = t
+	})
+}
+
+// TimeStampLevels 
+func (h *Hook) TimeStampLevels() []int {
+	return TimeStampLevels()
+}
+
+// TimeStampLevel 
+func (h *Hook) TimeStampLevel() int {
+	return h.format.get().Time.StampLevel
+}
+
+// IsTimeStampLevel 
+func (h *Hook) IsTimeStampLevel(l int) bool {
+	return IsTimeStampLevel(l)
+}
+
+// SetTimeStampLevel 
+func (h *Hook) SetTimeStampLevel(l int) error {
+	var err error
+
+	if h.IsTimeStampLevel(l) {
+		h.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
+	} else {
+		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
+		h.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: h.FormatName()})
+	}
+
+	return err
+}
+
+// TimeStampLevelNames 
+func (h *Hook) TimeStampLevelNames() []string {
+	return TimeStampLevelNames()
+}
+
+// TimeStampLevelName 
+func (h *Hook) TimeStampLevelName() string {
+	return timeStampLevelNames[h.format.get().Time.StampLevel]
+}
+
+// IsTimeStampLevelName 
+func (h *Hook) IsTimeStampLevelName(l string) bool {
+	return IsTimeStampLevelName(l)
+}
+
+// SetTimeStampLevelName 
+func (h *Hook) SetTimeStampLevelName(l string) error {
+	var err error
+
+	l = strings.ToLower(strings.TrimSpace(l))
+	if h.IsTimeStampLevelName(l) {
+		h.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
+	} else {
+		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
+		h.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: h.FormatName()})
+	}
+
+	return err
+}
+
+// TimeFormat 
+func (h *Hook) TimeFormat() string {
+	return h.format.get().Time.Format
+}
+
+// SetTimeFormat 
+func (h *Hook) SetTimeFormat(f string) {
+	h.format.set(func(format *Formatter) {
+		format.Time.Format = f
+	})
+}
+
+// Panic 
+func (h *Hook) Panic(e error) {
+	if h.format.get().Level >= PANIC_LEVEL {
+		h.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Panicv 
+func (h *Hook) Panicv(e error, v Vars) {
+	if h.format.get().Level >= PANIC_LEVEL {
+		h.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Panicf 
+func (h *Hook) Panicf(e error, i ...interface{}) {
+	if h.format.get().Level >= PANIC_LEVEL {
+		h.write(newEntry(PANIC_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Panicln 
+func (h *Hook) Panicln(i ...interface{}) {
+	if h.format.get().Level >= PANIC_LEVEL {
+		h.write(newEntry(PANIC_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Fatal 
+func (h *Hook) Fatal(e error) {
+	if h.format.get().Level >= FATAL_LEVEL {
+		h.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Fatalv 
+func (h *Hook) Fatalv(e error, v Vars) {
+	if h.format.get().Level >= FATAL_LEVEL {
+		h.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Fatalf 
+func (h *Hook) Fatalf(e error, i ...interface{}) {
+	if h.format.get().Level >= FATAL_LEVEL {
+		h.write(newEntry(FATAL_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Fatalln 
+func (h *Hook) Fatalln(i ...interface{}) {
+	if h.format.get().Level >= FATAL_LEVEL {
+		h.write(newEntry(FATAL_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Error 
+func (h *Hook) Error(e error) {
+	if h.format.get().Level >= ERROR_LEVEL {
+		h.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Errorv 
+func (h *Hook) Errorv(e error, v Vars) {
+	if h.format.get().Level >= ERROR_LEVEL {
+		h.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Errorf 
+func (h *Hook) Errorf(e error, i ...interface{}) {
+	if h.format.get().Level >= ERROR_LEVEL {
+		h.write(newEntry(ERROR_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Errorln 
+func (h *Hook) Errorln(i ...interface{}) {
+	if h.format.get().Level >= ERROR_LEVEL {
+		h.write(newEntry(ERROR_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Warn 
+func (h *Hook) Warn(m string) {
+	if h.format.get().Level >= WARN_LEVEL {
+		h.write(newEntry(WARN_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Warnv 
+func (h *Hook) Warnv(m string, v Vars) {
+	if h.format.get().Level >= WARN_LEVEL {
+		h.write(newEntry(WARN_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Warnf 
+func (h *Hook) Warnf(m string, i ...interface{}) {
+	if h.format.get().Level >= WARN_LEVEL {
+		h.write(newEntry(WARN_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Warnln 
+func (h *Hook) Warnln(i ...interface{}) {
+	if h.format.get().Level >= WARN_LEVEL {
+		h.write(newEntry(WARN_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Info 
+func (h *Hook) Info(m string) {
+	if h.format.get().Level >= INFO_LEVEL {
+		h.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Infov 
+func (h *Hook) Infov(m string, v Vars) {
+	if h.format.get().Level >= INFO_LEVEL {
+		h.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Infof 
+func (h *Hook) Infof(m string, i ...interface{}) {
+	if h.format.get().Level >= INFO_LEVEL {
+		h.write(newEntry(INFO_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Infoln 
+func (h *Hook) Infoln(i ...interface{}) {
+	if h.format.get().Level >= INFO_LEVEL {
+		h.write(newEntry(INFO_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Debug 
+func (h *Hook) Debug(m string) {
+	if h.format.get().Level >= DEBUG_LEVEL {
+		h.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Debugv 
+func (h *Hook) Debugv(m string, v Vars) {
+	if h.format.get().Level >= DEBUG_LEVEL {
+		h.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Debugf 
+func (h *Hook) Debugf(m string, i ...interface{}) {
+	if h.format.get().Level >= DEBUG_LEVEL {
+		h.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Debugln 
+func (h *Hook) Debugln(i ...interface{}) {
+	if h.format.get().Level >= DEBUG_LEVEL {
+		h.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Trace 
+func (h *Hook) Trace(m string) {
+	if h.format.get().Level >= TRACE_LEVEL {
+		h.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Tracev 
+func (h *Hook) Tracev(m string, v Vars) {
+	if h.format.get().Level >= TRACE_LEVEL {
+		h.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Tracef 
+func (h *Hook) Tracef(m string, i ...interface{}) {
+	if h.format.get().Level >= TRACE_LEVEL {
+		h.write(newEntry(TRACE_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Traceln 
+func (h *Hook) Traceln(i ...interface{}) {
+	if h.format.get().Level >= TRACE_LEVEL {
+		h.write(newEntry(TRACE_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Print 
+func (h *Hook) Print(m string) {
+	h.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, nil))
+}
+
+// Printv 
+func (h *Hook) Printv(m string, v Vars) {
+	h.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, v))
+}
+
+// Printf 
+func (h *Hook) Printf(m string, i ...interface{}) {
+	h.write(newEntry(PRINT_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+}
+
+// Println 
+func (h *Hook) Println(i ...interface{}) {
+	h.write(newEntry(PRINT_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+}
+
+// Close writes pending repeats of deduplication
+func (h *Hook) Close() error {
+	h.format.flush()
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 	"errors"
+	"strconv"
 	"crypto/tls"
 
 	"github.com/mitchellh/colorstring"
@@ -25,6 +26,9 @@ const (
 	SPLUNK_FORMAT   = int(8)
 	ENTRIES_FORMAT  = int(9)
 	JOURNALD_FORMAT = int(10)
+	OTLP_FORMAT     = int(11)
+	SLOG_FORMAT     = int(12)
+	HOOK_FORMAT     = int(13)
 )
 
 // Log levels as int
@@ -63,6 +67,36 @@ const (
 // 
 const LABELS_SEPARATOR = ","
 
+// Positions of fixed keys relative to fields and vars, default is native
+// position of format
+const (
+	KEYS_POSITION_DEFAULT = int(0)
+	KEYS_POSITION_FIRST   = int(1)
+	KEYS_POSITION_LAST    = int(2)
+)
+
+// Strategies of collision of field key with fixed key, prefixed field is
+// written under Keys.Prefix, overwriting field replaces fixed key, error
+// drops field and reports it to stderr
+const (
+	KEYS_COLLISION_PREFIX    = int(0)
+	KEYS_COLLISION_OVERWRITE = int(1)
+	KEYS_COLLISION_ERROR     = int(2)
+)
+
+// Policies of new lines in messages and string values. Default escapes them
+// by text format, escaped lines are joined by "\n", indented lines are
+// continued by tab, split lines are written as separate records with the same
+// fields. Other formats escape strings by their encoding, so only split
+// changes their output.
+const (
+	MULTILINE_DEFAULT = int(0)
+	MULTILINE_ESCAPE  = int(1)
+	MULTILINE_INDENT  = int(2)
+	MULTILINE_SPLIT   = int(3)
+	MULTILINE_KEEP    = int(4)
+)
+
 // Default constants of time formats
 const (
 	TIME_FORMAT_SIMPLE        = "2006-01-02 15:04:05"
@@ -169,6 +203,10 @@ type KeysNames struct {
 	Timestamp   string `json:"timestamp" yaml:"timestamp" xml:"timestamp" toml:"timestamp"`
 	Environment string `json:"environment" yaml:"environment" xml:"environment" toml:"environment"`
 	Tag         string `json:"tag" yaml:"tag" xml:"tag" toml:"tag"`
+	Logger      string `json:"logger" yaml:"logger" xml:"logger" toml:"logger"`
+	TraceID     string `json:"trace_id" yaml:"trace_id" xml:"trace_id" toml:"trace_id"`
+	SpanID      string `json:"span_id" yaml:"span_id" xml:"span_id" toml:"span_id"`
+	TraceFlags  string `json:"trace_flags" yaml:"trace_flags" xml:"trace_flags" toml:"trace_flags"`
 }
 
 // Keys 
@@ -176,6 +214,8 @@ type Keys struct {
 	Names           *KeysNames `json:"names" yaml:"names" xml:"names" toml:"names"`
 	Prefix          string     `json:"prefix" yaml:"prefix" xml:"prefix" toml:"prefix"`
 	PrefixSeparator string     `json:"prefix_separator" yaml:"prefix_separator" xml:"prefix_separator" toml:"prefix_separator"`
+	Position        int        `json:"position" yaml:"position" xml:"position" toml:"position"`
+	Collision       int        `json:"collision" yaml:"collision" xml:"collision" toml:"collision"`
 }
 
 // Labels
@@ -202,14 +242,19 @@ type StdOE struct {
 
 // Formatter 
 type Formatter struct {
-	Stdout      *StdOE  `json:"stdout" yaml:"stdout" xml:"stdout" toml:"stdout"`
-	Stderr      *StdOE  `json:"stderr" yaml:"stderr" xml:"stderr" toml:"stderr"`
-	Level       int     `json:"level" yaml:"level" xml:"level" toml:"level"`
-	Labels      *Labels `json:"labels" yaml:"labels" xml:"labels" toml:"labels"`
-	Time        *Time   `json:"time" yaml:"time" xml:"time" toml:"time"`
-	Environment string  `json:"environment" yaml:"environment" xml:"environment" toml:"environment"`
-	Tag         string  `json:"tag" yaml:"tag" xml:"tag" toml:"tag"`
-	Keys        *Keys   `json:"keys" yaml:"keys" xml:"keys" toml:"keys"`
+	Stdout      *StdOE            `json:"stdout" yaml:"stdout" xml:"stdout" toml:"stdout"`
+	Stderr      *StdOE            `json:"stderr" yaml:"stderr" xml:"stderr" toml:"stderr"`
+	Level       int               `json:"level" yaml:"level" xml:"level" toml:"level"`
+	Labels      *Labels           `json:"labels" yaml:"labels" xml:"labels" toml:"labels"`
+	Time        *Time             `json:"time" yaml:"time" xml:"time" toml:"time"`
+	Environment string            `json:"environment" yaml:"environment" xml:"environment" toml:"environment"`
+	Tag         string            `json:"tag" yaml:"tag" xml:"tag" toml:"tag"`
+	Keys        *Keys             `json:"keys" yaml:"keys" xml:"keys" toml:"keys"`
+	NamedLevels string            `json:"named_levels" yaml:"named_levels" xml:"named_levels" toml:"named_levels"`
+	Multiline   int               `json:"multiline" yaml:"multiline" xml:"multiline" toml:"multiline"`
+	Sampling    *SamplingSettings `json:"sampling" yaml:"sampling" xml:"sampling" toml:"sampling"`
+	Dedup       *DedupSettings    `json:"dedup" yaml:"dedup" xml:"dedup" toml:"dedup"`
+	Redact      *RedactSettings   `json:"redact" yaml:"redact" xml:"redact" toml:"redact"`
 }
 
 // Vars 
@@ -288,67 +333,67 @@ func TCPTLSConfig(t *TCPTLS) (*tls.Config, error) {
 
 // StrInt 
 func StrInt(v int) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.Itoa(v)
 }
 
 // StrUInt 
 func StrUInt(v uint) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatUint(uint64(v), 10)
 }
 
 // StrInt8 
 func StrInt8(v int8) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatInt(int64(v), 10)
 }
 
 // StrInt16 
 func StrInt16(v int16) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatInt(int64(v), 10)
 }
 
 // StrInt32 
 func StrInt32(v int32) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatInt(int64(v), 10)
 }
 
 // StrUInt8 
 func StrUInt8(v uint8) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatUint(uint64(v), 10)
 }
 
 // StrUInt16 
 func StrUInt16(v uint16) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatUint(uint64(v), 10)
 }
 
 // StrUInt32 
 func StrUInt32(v uint32) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatUint(uint64(v), 10)
 }
 
 // StrInt64 
 func StrInt64(v int64) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatInt(v, 10)
 }
 
 // StrUInt64 
 func StrUInt64(v uint64) string {
-	return fmt.Sprintf(STR_D, v)
+	return strconv.FormatUint(v, 10)
 }
 
 // StrFloat32 
 func StrFloat32(v float32) string {
-	return fmt.Sprintf(STR_F, v)
+	return strconv.FormatFloat(float64(v), 'f', 6, 32)
 }
 
 // StrFloat64 
 func StrFloat64(v float64) string {
-	return fmt.Sprintf(STR_F, v)
+	return strconv.FormatFloat(v, 'f', 6, 64)
 }
 
 // StrBool 
 func StrBool(v bool) string {
-	return fmt.Sprintf(STR_T, v)
+	return strconv.FormatBool(v)
 }
 
 // StrV 
@@ -390,6 +435,11 @@ func SetFormat(f int) error {
 	return self.SetFormat(f)
 }
 
+// SetBackend replaces logger of package functions by backend, e.g. by *Slog
+func SetBackend(b Backend) error {
+	return self.SetBackend(b)
+}
+
 // FormatNames 
 func FormatNames() []string {
 	return formatNames
@@ -445,6 +495,31 @@ func SetLevelName(l string) error {
 	return self.SetLevelName(l)
 }
 
+// Named returns child logger with records marked by name
+func Named(n string) *Logs {
+	return self.Named(n)
+}
+
+// NamedLevels 
+func NamedLevels() string {
+	return self.NamedLevels()
+}
+
+// SetNamedLevels sets levels by name prefix as "storage=debug,http=warn"
+func SetNamedLevels(s string) error {
+	return self.SetNamedLevels(s)
+}
+
+// Sampling 
+func Sampling() *SamplingSettings {
+	return self.Sampling()
+}
+
+// SetSampling 
+func SetSampling(s *SamplingSettings) error {
+	return self.SetSampling(s)
+}
+
 // LabelsString 
 func LabelsString() string {
 	return self.Labels()
@@ -585,6 +660,11 @@ func Panicln(i ...interface{}) {
 	self.Panicln(i...)
 }
 
+// Panicw 
+func Panicw(e error, f ...Field) {
+	self.Panicw(e, f...)
+}
+
 // Fatal 
 func Fatal(err error) {
 	self.Fatal(err)
@@ -605,6 +685,11 @@ func Fatalln(i ...interface{}) {
 	self.Fatalln(i...)
 }
 
+// Fatalw 
+func Fatalw(e error, f ...Field) {
+	self.Fatalw(e, f...)
+}
+
 // Error 
 func Error(err error) {
 	self.Error(err)
@@ -625,6 +710,11 @@ func Errorln(i ...interface{}) {
 	self.Errorln(i...)
 }
 
+// Errorw 
+func Errorw(e error, f ...Field) {
+	self.Errorw(e, f...)
+}
+
 // Warn 
 func Warn(s string) {
 	self.Warn(s)
@@ -645,6 +735,11 @@ func Warnln(i ...interface{}) {
 	self.Warnln(i...)
 }
 
+// Warnw 
+func Warnw(s string, f ...Field) {
+	self.Warnw(s, f...)
+}
+
 // Info 
 func Info(s string) {
 	self.Info(s)
@@ -665,6 +760,11 @@ func Infoln(i ...interface{}) {
 	self.Infoln(i...)
 }
 
+// Infow 
+func Infow(s string, f ...Field) {
+	self.Infow(s, f...)
+}
+
 // Debug 
 func Debug(s string) {
 	self.Debug(s)
@@ -685,6 +785,11 @@ func Debugln(i ...interface{}) {
 	self.Debugln(i...)
 }
 
+// Debugw 
+func Debugw(s string, f ...Field) {
+	self.Debugw(s, f...)
+}
+
 // Trace 
 func Trace(s string) {
 	self.Trace(s)
@@ -705,6 +810,11 @@ func Traceln(i ...interface{}) {
 	self.Traceln(i...)
 }
 
+// Tracew 
+func Tracew(s string, f ...Field) {
+	self.Tracew(s, f...)
+}
+
 // Print 
 func Print(s string) {
 	self.Print(s)
@@ -725,6 +835,27 @@ func Println(i ...interface{}) {
 	self.Println(i...)
 }
 
+// Printw 
+func Printw(s string, f ...Field) {
+	self.Printw(s, f...)
+}
+
+// Writer returns writer whose lines are records of level
+func Writer(l int, s ...*WriterSettings) io.WriteCloser {
+	return self.Writer(l, s...)
+}
+
+// StdLogger returns log.Logger whose lines are records of level
+func StdLogger(l int, s ...*WriterSettings) *log.Logger {
+	return self.StdLogger(l, s...)
+}
+
+// RedirectStdLog writes output of log package by logger until returned
+// function is called
+func RedirectStdLog() func() {
+	return self.RedirectStdLog()
+}
+
 // Close 
 func Close() {
 	self.Close()
@@ -1,2 +1,29 @@
 package logs
 
+import (
+	"testing"
+)
+
+func TestFormatNames(t *testing.T) {
+	if len(Formats()) != len(FormatNames()) {
+		t.Fatalf("%d formats have %d names", len(Formats()), len(FormatNames()))
+	}
+	for i, f := range Formats() {
+		if f != i {
+			t.Errorf("format %d is at index %d", f, i)
+		}
+	}
+	for _, l := range []logger{discardText(&Formatter{}), discardJSON(&Formatter{}), writerFMT(&Formatter{}, nil), writerFluent(&Formatter{}, nil)} {
+		if FormatNames()[l.Format()] != l.FormatName() {
+			t.Errorf("unexpected name %s of format %d", l.FormatName(), l.Format())
+		}
+	}
+	for _, name := range []string{" text", OTLP_NAME, SLOG_NAME, HOOK_NAME} {
+		if IsFormatName(name) {
+			t.Errorf("format name %q is accepted", name)
+		}
+	}
+	if !IsFormatName("JSON") {
+		t.Error("format names are case sensitive")
+	}
+}
@@ -4,10 +4,8 @@ import (
 	"io"
 	"log"
 	"fmt"
-	"time"
 	"errors"
 	"strings"
-	"encoding/json"
 )
 
 // JSON_NAME 
@@ -41,16 +39,20 @@ type JSONKeys struct {
 	Tag         string `json:"tag" yaml:"tag" xml:"tag" toml:"tag"`
 }
 
-// JSONSettings 
+// JSONSettings selects profile of records, ECS and GCP profiles set names
+// of fixed keys which are not set by Formatter. Project is Google Cloud
+// project of traces of GCP profile.
 type JSONSettings struct {
 	KeysPrefix          string    `json:"keys_prefix" yaml:"keys_prefix" xml:"keys_prefix" toml:"keys_prefix"`
 	KeysPrefixSeparator string    `json:"keys_prefix_separator" yaml:"keys_prefix_separator" xml:"keys_prefix_separator" toml:"keys_prefix_separator"`
 	Keys                *JSONKeys `json:"keys" yaml:"keys" xml:"keys" toml:"keys"`
+	Profile             int       `json:"profile" yaml:"profile" xml:"profile" toml:"profile"`
+	Project             string    `json:"project" yaml:"project" xml:"project" toml:"project"`
 }
 
 // JSON 
 type JSON struct {
-	format   *Formatter
+	format   *formatState
 	settings *JSONSettings
 	stdout   *log.Logger
 	stderr   *log.Logger
@@ -76,6 +78,12 @@ func NewJSON(s *JSONSettings, f ...*Formatter) (*JSON, error) {
 	} else {
 		format = f[0]
 	}
+	switch settings.Profile {
+	case JSON_PROFILE_ECS:
+		defaultECSKeys(format)
+	case JSON_PROFILE_GCP:
+		defaultGCPKeys(format)
+	}
 	defaultFormatter(format, false, true)
 	if format.Keys.Prefix == EMPTY_STRING {
 		format.Keys.Prefix = JSON_KEYS_PREFIX
@@ -85,73 +93,47 @@ func NewJSON(s *JSONSettings, f ...*Formatter) (*JSON, error) {
 	}
 
 	newOE(&stdout, &stderr, false)
-
-	return &JSON{
-		format,
-		settings,
-		newSystemLogger(stdout),
-		newSystemLogger(stderr),
-	}, nil
-}
-
-// time 
-func (j *JSON) time(tt time.Time) string {
-	if j.format.Time.IsUTC {
-		tt = tt.UTC()
+	err := redactCheck(format.Redact)
+
+	if err == nil {
+		return &JSON{
+			newFormatState(format),
+			settings,
+			newSystemLogger(stdout),
+			newSystemLogger(stderr),
+		}, nil
+	} else {
+		if format.Stderr.IsPrintable {
+			format.Stderr.Logger.Print(err.Error())
+		}
+		return nil, err
 	}
-
-	return tt.Format(j.format.Time.Format)
 }
 
-// timeStamp 
-func (j *JSON) timeStamp(tt time.Time) int64 {
-	if j.format.Time.IsUTC {
-		tt = tt.UTC()
-	}
-
-	return timeStampLevel(j.format.Time.StampLevel, tt)
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (j *JSON) write(e *Entry) {
+	j.format.write(j, e)
 }
 
-// build 
-func (j *JSON) build(l int, m string, v Vars) string {
-	if v == nil {
-		v = Vars{}
-	} else {
-		for key, value := range v {
-			switch key {
-			case j.format.Keys.Names.Level, j.format.Keys.Names.Labels, j.format.Keys.Names.Message, j.format.Keys.Names.Timestamp, j.format.Keys.Names.Time, j.format.Keys.Names.Environment, j.format.Keys.Names.Tag:
-				v[j.format.Keys.Prefix+j.format.Keys.PrefixSeparator+key] = value
-				delete(v, key)
-			}
-		}
-	}
-
-	if l < PRINT_LEVEL {
-		v[j.format.Keys.Names.Level] = levelNames[l]
-	}
-	if j.format.Labels.String != EMPTY_STRING {
-		v[j.format.Keys.Names.Labels] = j.format.Labels.String
-	}
-	v[j.settings.Keys.Message] = m
-	if j.format.Time.IsStamp {
-		v[j.format.Keys.Names.Timestamp] = j.timeStamp(time.Now())
-	} else {
-		v[j.format.Keys.Names.Time] = j.time(time.Now())
-	}
-	if j.format.Environment != EMPTY_STRING {
-		v[j.format.Keys.Names.Environment] = j.format.Environment
-	}
-	if j.format.Tag != EMPTY_STRING {
-		v[j.format.Keys.Names.Tag] = j.format.Tag
-	}
+// formatter returns current snapshot of Formatter
+func (j *JSON) formatter() *Formatter {
+	return j.format.get()
+}
 
-	out, err := json.Marshal(&v)
-	if err != nil {
-		j.Error(err)
+// record encodes entry by profile into pooled buffer and writes it by one
+// call
+func (j *JSON) record(e *Entry) {
+	buffer := newBuffer()
+	switch j.settings.Profile {
+	case JSON_PROFILE_ECS:
+		buffer.bytes = appendECSEntry(buffer.bytes, j.format.get(), e, buffer.sortKeys(e.Vars))
+	case JSON_PROFILE_GCP:
+		buffer.bytes = appendGCPEntry(buffer.bytes, j.format.get(), e, buffer.sortKeys(e.Vars), j.settings.Project)
+	default:
+		buffer.bytes = appendJSONEntry(buffer.bytes, j.format.get(), e, buffer.sortKeys(e.Vars))
 	}
-	v = nil
-
-	return string(out)
+	writeRecord(e.Level, j.stdout, j.stderr, buffer)
 }
 
 // Format 
@@ -171,7 +153,7 @@ func (j *JSON) Levels() []int {
 
 // Level 
 func (j *JSON) Level() int {
-	return j.format.Level
+	return j.format.get().Level
 }
 
 // IsLevel 
@@ -184,7 +166,9 @@ func (j *JSON) SetLevel(l int) error {
 	var err error
 
 	if j.IsLevel(l) {
-		j.format.Level = l
+		j.format.set(func(format *Formatter) {
+			format.Level = l
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL)
 		j.Errorv(err, Vars{
@@ -202,7 +186,7 @@ func (j *JSON) LevelNames() []string {
 
 // LevelName 
 func (j *JSON) LevelName() string {
-	return levelNames[j.format.Level]
+	return levelNames[j.format.get().Level]
 }
 
 // IsLevelName 
@@ -216,7 +200,9 @@ func (j *JSON) SetLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if j.IsLevelName(l) {
-		j.format.Level = sliceIndex(levelNames, l)
+		j.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL_NAME)
 		j.Errorv(err, Vars{
@@ -229,72 +215,84 @@ func (j *JSON) SetLevelName(l string) error {
 
 // Labels 
 func (j *JSON) Labels() string {
-	return j.format.Labels.String
+	return j.format.get().Labels.String
 }
 
 // SetLabels 
 func (j *JSON) SetLabels(l string) {
-	j.format.Labels.String = l
+	j.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
 }
 
 // LabelsSeparator 
 func (j *JSON) LabelsSeparator() string {
-	return j.format.Labels.Separator
+	return j.format.get().Labels.Separator
 }
 
 // SetLabelsSeparator 
 func (j *JSON) SetLabelsSeparator(s string) {
-	j.format.Labels.Separator = s
+	j.format.set(func(format *Formatter) {
+		format.Labels.Separator = s
+	})
 }
 
 // LabelsToString 
 func (j *JSON) LabelsToString(l []string) string {
-	return strings.Join(l, j.format.Labels.Separator)
+	return strings.Join(l, j.format.get().Labels.Separator)
 }
 
 // LabelsToSlice 
 func (j *JSON) LabelsToSlice(l string) []string {
-	return strings.Split(l, j.format.Labels.Separator)
+	return strings.Split(l, j.format.get().Labels.Separator)
 }
 
 // Environment 
 func (j *JSON) Environment() string {
-	return j.format.Environment
+	return j.format.get().Environment
 }
 
 // SetEnvironment 
 func (j *JSON) SetEnvironment(e string) {
-	j.format.Environment = strings.TrimSpace(e)
+	j.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
 }
 
 // Tag 
 func (j *JSON) Tag() string {
-	return j.format.Tag
+	return j.format.get().Tag
 }
 
 // SetTag 
 func (j *JSON) SetTag(t string) {
-	j.format.Tag = strings.TrimSpace(t)
+	j.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(t)
+	})
 }
 
 // IsTimeUTC 
 func (j *JSON) IsTimeUTC() bool {
-	return j.format.Time.IsUTC
+	return j.format.get().Time.IsUTC
 }
 
 // SetTimeUTC 
 func (j *JSON) SetTimeUTC(u bool) {
-	j.format.Time.IsUTC = u
+	j.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
 }
 
 // IsTimeStamp 
 func (j *JSON) IsTimeStamp() bool {
-	return j.format.Time.IsStamp
+	return j.format.get().Time.IsStamp
 }
 
 // SetTimeStamp 
 func (j *JSON) SetTimeStamp(t bool) {
-	j.format.Time.IsStamp = t
+	j.format.set(func(format *Formatter) {
+		format.Time.IsStamp = t
+	})
 }
 
 // TimeStampLevels 
@@ -304,7 +302,7 @@ func (j *JSON) TimeStampLevels() []int {
 
 // TimeStampLevel 
 func (j *JSON) TimeStampLevel() int {
-	return j.format.Time.StampLevel
+	return j.format.get().Time.StampLevel
 }
 
 // IsTimeStampLevel 
@@ -317,7 +315,10 @@ func (j *JSON) SetTimeStampLevel(l int) error {
 	var err error
 
 	if j.IsTimeStampLevel(l) {
-		j.format.Time.StampLevel = l
+		j.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
 		j.Errorv(err, Vars{
@@ -335,7 +336,7 @@ func (j *JSON) TimeStampLevelNames() []string {
 
 // TimeStampLevelName 
 func (j *JSON) TimeStampLevelName() string {
-	return timeStampLevelNames[j.format.Level]
+	return timeStampLevelNames[j.format.get().Time.StampLevel]
 }
 
 // IsTimeStampLevelName 
@@ -349,7 +350,10 @@ func (j *JSON) SetTimeStampLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if j.IsTimeStampLevelName(l) {
-	    j.format.Level = sliceIndex(timeStampLevelNames, l)
+		j.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
 		j.Errorv(err, Vars{
@@ -362,239 +366,235 @@ func (j *JSON) SetTimeStampLevelName(l string) error {
 
 // TimeFormat 
 func (j *JSON) TimeFormat() string {
-	return j.format.Time.Format
+	return j.format.get().Time.Format
 }
 
 // SetTimeFormat 
 func (j *JSON) SetTimeFormat(f string) {
-	j.format.Time.Format = f
+	j.format.set(func(format *Formatter) {
+		format.Time.Format = f
+	})
 }
 
 // Panic 
 func (j *JSON) Panic(e error) {
-	if j.format.Level >= PANIC_LEVEL {
-		j.stderr.Panic(j.build(PANIC_LEVEL, e.Error(), nil))
+	if j.format.get().Level >= PANIC_LEVEL {
+		j.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Panicv 
 func (j *JSON) Panicv(e error, v Vars) {
-	if j.format.Level >= PANIC_LEVEL {
-		j.stderr.Panic(j.build(PANIC_LEVEL, e.Error(), v))
+	if j.format.get().Level >= PANIC_LEVEL {
+		j.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Panicf 
 func (j *JSON) Panicf(e error, i ...interface{}) {
-	if j.format.Level >= PANIC_LEVEL {
-		j.stderr.Panic(j.build(PANIC_LEVEL, fmt.Sprintf(e.Error(), i...), nil))
+	if j.format.get().Level >= PANIC_LEVEL {
+		j.write(newEntry(PANIC_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Panicln 
 func (j *JSON) Panicln(i ...interface{}) {
-	if j.format.Level >= PANIC_LEVEL {
-		j.stderr.Panic(j.build(PANIC_LEVEL, fmt.Sprintln(i...), nil))
+	if j.format.get().Level >= PANIC_LEVEL {
+		j.write(newEntry(PANIC_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Fatal 
 func (j *JSON) Fatal(e error) {
-	if j.format.Level >= FATAL_LEVEL {
-		j.stderr.Fatal(j.build(FATAL_LEVEL, e.Error(), nil))
+	if j.format.get().Level >= FATAL_LEVEL {
+		j.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Fatalv 
 func (j *JSON) Fatalv(e error, v Vars) {
-	if j.format.Level >= FATAL_LEVEL {
-		j.stderr.Fatal(j.build(FATAL_LEVEL, e.Error(), v))
+	if j.format.get().Level >= FATAL_LEVEL {
+		j.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Fatalf 
 func (j *JSON) Fatalf(e error, i ...interface{}) {
-	if j.format.Level >= FATAL_LEVEL {
-		j.stderr.Fatal(j.build(FATAL_LEVEL, fmt.Sprintf(e.Error(), i...), nil))
+	if j.format.get().Level >= FATAL_LEVEL {
+		j.write(newEntry(FATAL_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Fatalln 
 func (j *JSON) Fatalln(i ...interface{}) {
-	if j.format.Level >= FATAL_LEVEL {
-		j.stderr.Fatal(j.build(FATAL_LEVEL, fmt.Sprintln(i...), nil))
+	if j.format.get().Level >= FATAL_LEVEL {
+		j.write(newEntry(FATAL_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Error 
 func (j *JSON) Error(e error) {
-	if j.format.Level >= ERROR_LEVEL {
-		j.stderr.Print(j.build(ERROR_LEVEL, e.Error(), nil))
+	if j.format.get().Level >= ERROR_LEVEL {
+		j.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Errorv 
 func (j *JSON) Errorv(e error, v Vars) {
-	if j.format.Level >= ERROR_LEVEL {
-		j.stderr.Print(j.build(ERROR_LEVEL, e.Error(), v))
+	if j.format.get().Level >= ERROR_LEVEL {
+		j.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Errorf 
 func (j *JSON) Errorf(e error, i ...interface{}) {
-	if j.format.Level >= ERROR_LEVEL {
-		j.stderr.Print(j.build(ERROR_LEVEL, fmt.Sprintf(e.Error(), i...), nil))
+	if j.format.get().Level >= ERROR_LEVEL {
+		j.write(newEntry(ERROR_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Errorln 
 func (j *JSON) Errorln(i ...interface{}) {
-	if j.format.Level >= ERROR_LEVEL {
-		j.stderr.Print(j.build(ERROR_LEVEL, fmt.Sprintln(i...), nil))
+	if j.format.get().Level >= ERROR_LEVEL {
+		j.write(newEntry(ERROR_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Warn 
 func (j *JSON) Warn(s string) {
-	if j.format.Level >= WARN_LEVEL {
-		j.stdout.Print(j.build(WARN_LEVEL, s, nil))
+	if j.format.get().Level >= WARN_LEVEL {
+		j.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Warnv 
 func (j *JSON) Warnv(s string, v Vars) {
-	if j.format.Level >= WARN_LEVEL {
-		j.stdout.Print(j.build(WARN_LEVEL, s, v))
+	if j.format.get().Level >= WARN_LEVEL {
+		j.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Warnf 
 func (j *JSON) Warnf(s string, i ...interface{}) {
-	if j.format.Level >= WARN_LEVEL {
-		j.stdout.Print(j.build(WARN_LEVEL, fmt.Sprintf(s, i...), nil))
+	if j.format.get().Level >= WARN_LEVEL {
+		j.write(newEntry(WARN_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Warnln 
 func (j *JSON) Warnln(i ...interface{}) {
-	if j.format.Level >= WARN_LEVEL {
-		j.stdout.Print(j.build(WARN_LEVEL, fmt.Sprintln(i...), nil))
+	if j.format.get().Level >= WARN_LEVEL {
+		j.write(newEntry(WARN_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Info 
 func (j *JSON) Info(s string) {
-	if j.format.Level >= INFO_LEVEL {
-		j.stdout.Print(j.build(INFO_LEVEL, s, nil))
+	if j.format.get().Level >= INFO_LEVEL {
+		j.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Infov 
 func (j *JSON) Infov(s string, v Vars) {
-	if j.format.Level >= INFO_LEVEL {
-		j.stdout.Print(j.build(INFO_LEVEL, s, v))
+	if j.format.get().Level >= INFO_LEVEL {
+		j.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Infof 
 func (j *JSON) Infof(s string, i ...interface{}) {
-	if j.format.Level >= INFO_LEVEL {
-		j.stdout.Print(j.build(INFO_LEVEL, fmt.Sprintf(s, i...), nil))
+	if j.format.get().Level >= INFO_LEVEL {
+		j.write(newEntry(INFO_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Infoln 
 func (j *JSON) Infoln(i ...interface{}) {
-	if j.format.Level >= INFO_LEVEL {
-		j.stdout.Print(j.build(INFO_LEVEL, fmt.Sprintln(i...), nil))
+	if j.format.get().Level >= INFO_LEVEL {
+		j.write(newEntry(INFO_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Debug 
 func (j *JSON) Debug(s string) {
-	if j.format.Level >= DEBUG_LEVEL {
-		j.stdout.Print(j.build(DEBUG_LEVEL, s, nil))
+	if j.format.get().Level >= DEBUG_LEVEL {
+		j.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Debugv 
 func (j *JSON) Debugv(s string, v Vars) {
-	if j.format.Level >= DEBUG_LEVEL {
-		j.stdout.Print(j.build(DEBUG_LEVEL, s, v))
+	if j.format.get().Level >= DEBUG_LEVEL {
+		j.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Debugf 
 func (j *JSON) Debugf(s string, i ...interface{}) {
-	if j.format.Level >= DEBUG_LEVEL {
-		j.stdout.Print(j.build(DEBUG_LEVEL, fmt.Sprintf(s, i...), nil))
+	if j.format.get().Level >= DEBUG_LEVEL {
+		j.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Debugln 
 func (j *JSON) Debugln(i ...interface{}) {
-	if j.format.Level >= DEBUG_LEVEL {
-		j.stdout.Print(j.build(DEBUG_LEVEL, fmt.Sprintln(i...), nil))
+	if j.format.get().Level >= DEBUG_LEVEL {
+		j.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Trace 
 func (j *JSON) Trace(s string) {
-	if j.format.Level >= TRACE_LEVEL {
-		j.stdout.Print(j.build(TRACE_LEVEL, s, nil))
+	if j.format.get().Level >= TRACE_LEVEL {
+		j.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Tracev 
 func (j *JSON) Tracev(s string, v Vars) {
-	if j.format.Level >= TRACE_LEVEL {
-		j.stdout.Print(j.build(TRACE_LEVEL, s, v))
+	if j.format.get().Level >= TRACE_LEVEL {
+		j.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Tracef 
 func (j *JSON) Tracef(s string, i ...interface{}) {
-	if j.format.Level >= TRACE_LEVEL {
-		j.stdout.Print(j.build(TRACE_LEVEL, fmt.Sprintf(s, i...), nil))
+	if j.format.get().Level >= TRACE_LEVEL {
+		j.write(newEntry(TRACE_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 	}
 }
 
 // Traceln 
 func (j *JSON) Traceln(i ...interface{}) {
-	if j.format.Level >= TRACE_LEVEL {
-		j.stdout.Print(j.build(TRACE_LEVEL, fmt.Sprintln(i...), nil))
+	if j.format.get().Level >= TRACE_LEVEL {
+		j.write(newEntry(TRACE_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Print 
 func (j *JSON) Print(s string) {
-	j.stdout.Print(j.build(PRINT_LEVEL, s, nil))
+	j.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, nil))
 }
 
 // Printv 
 func (j *JSON) Printv(s string, v Vars) {
-	j.stdout.Print(j.build(PRINT_LEVEL, s, v))
+	j.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, v))
 }
 
 // Printf 
 func (j *JSON) Printf(s string, i ...interface{}) {
-	j.stdout.Print(j.build(PRINT_LEVEL, fmt.Sprintf(s, i...), nil))
+	j.write(newEntry(PRINT_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
 }
 
 // Println 
 func (j *JSON) Println(i ...interface{}) {
-	j.stdout.Print(j.build(PRINT_LEVEL, fmt.Sprintln(i...), nil))
+	j.write(newEntry(PRINT_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 }
 
-// Close 
+// Close keeps state, because logger can still be used by concurrent callers
 func (j *JSON) Close() error {
-	if j != nil {
-		j.format = nil
-		j.settings = nil
-		j.stdout = nil
-		j.stderr = nil
-		j = nil
-	}
+	j.format.flush()
 
 	return nil
 }
@@ -1,2 +1,86 @@
 package logs
 
+import (
+	"io"
+	"fmt"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"encoding/json"
+)
+
+func TestJSONECS(t *testing.T) {
+	w := &bytes.Buffer{}
+	f := &Formatter{Level: INFO_LEVEL, Tag: "api", Environment: "prod", Labels: &Labels{"region=eu,canary", LABELS_SEPARATOR}}
+	defaultECSKeys(f)
+	l := writerJSON(f, w)
+	l.settings.Profile = JSON_PROFILE_ECS
+
+	newTestLogs(l).Named("http").Warnw("failed", Err(fmt.Errorf("read: %w", io.EOF)), String("message", "field"), Int("n", 1))
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("%v: %s", err, w.Bytes())
+	}
+	for k, v := range map[string]interface{}{
+		"log.level":           "warn",
+		"message":             "failed",
+		"ecs.version":         ECS_VERSION,
+		"service.name":        "api",
+		"service.environment": "prod",
+		"log.logger":          "http",
+		"error.type":          "*fmt.wrapError",
+		"error.message":       "read: EOF",
+		"fields.message":      "field",
+		"n":                   float64(1),
+	} {
+		if m[k] != v {
+			t.Errorf("%s is %v in %s", k, m[k], w.Bytes())
+		}
+	}
+	if s, _ := m["@timestamp"].(string); !strings.HasSuffix(s, "Z") {
+		t.Errorf("unexpected timestamp %q", s)
+	}
+	if s, _ := m["log.origin.file.name"].(string); !strings.HasSuffix(s, "json_test.go") {
+		t.Errorf("unexpected origin %q", s)
+	}
+	if fmt.Sprint(m["labels"], m["tags"]) != "map[region:eu] [canary]" || m["error"] != nil {
+		t.Errorf("unexpected labels and error in %s", w.Bytes())
+	}
+}
+
+func TestJSONGCP(t *testing.T) {
+	w := &bytes.Buffer{}
+	f := &Formatter{Level: INFO_LEVEL, Tag: "api", Labels: &Labels{"region=eu,canary", LABELS_SEPARATOR}}
+	defaultGCPKeys(f)
+	l := writerJSON(f, w)
+	l.settings.Profile = JSON_PROFILE_GCP
+	l.settings.Project = "shop"
+
+	ctx := ContextWithTraceparent(context.Background(), testTraceparent)
+	newTestLogs(l).WithContext(ctx).Warnv("slow", Vars{"ms": 250})
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("%v: %s", err, w.Bytes())
+	}
+	for k, v := range map[string]interface{}{
+		"severity":                             "WARNING",
+		"message":                              "slow",
+		"ms":                                   float64(250),
+		"logging.googleapis.com/trace":         "projects/shop/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+		"logging.googleapis.com/spanId":        "00f067aa0ba902b7",
+		"logging.googleapis.com/trace_sampled": true,
+	} {
+		if m[k] != v {
+			t.Errorf("%s is %v in %s", k, m[k], w.Bytes())
+		}
+	}
+	if fmt.Sprint(m["logging.googleapis.com/labels"]) != "map[region:eu tag:api]" {
+		t.Errorf("unexpected labels in %s", w.Bytes())
+	}
+	if location, _ := m["logging.googleapis.com/sourceLocation"].(map[string]interface{}); !strings.HasSuffix(fmt.Sprint(location["file"]), "json_test.go") {
+		t.Errorf("unexpected source location in %s", w.Bytes())
+	}
+}
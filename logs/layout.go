@@ -0,0 +1,312 @@
+package logs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mitchellh/colorstring"
+)
+
+// Segments of text layout
+const (
+	TEXT_LAYOUT_LITERAL     = int(0)
+	TEXT_LAYOUT_TIME        = int(1)
+	TEXT_LAYOUT_LEVEL       = int(2)
+	TEXT_LAYOUT_ENVIRONMENT = int(3)
+	TEXT_LAYOUT_LABELS      = int(4)
+	TEXT_LAYOUT_TAG         = int(5)
+	TEXT_LAYOUT_LOGGER      = int(6)
+	TEXT_LAYOUT_CALLER      = int(7)
+	TEXT_LAYOUT_MESSAGE     = int(8)
+	TEXT_LAYOUT_FIELDS      = int(9)
+)
+
+// Modifiers of text layout segments
+const (
+	TEXT_LAYOUT_RIGHT    = ">"
+	TEXT_LAYOUT_SIZE     = ":"
+	TEXT_LAYOUT_MAX      = "."
+	TEXT_LAYOUT_MODIFIER = "|"
+	TEXT_LAYOUT_COLOR    = "level"
+)
+
+// Error string messages
+const (
+	__ERROR_STR_TEXT_LAYOUT = "Invalid text layout"
+)
+
+// textLayoutNames are names of segments in braces
+var textLayoutNames = map[string]int{
+	"time":   TEXT_LAYOUT_TIME,
+	"level":  TEXT_LAYOUT_LEVEL,
+	"env":    TEXT_LAYOUT_ENVIRONMENT,
+	"labels": TEXT_LAYOUT_LABELS,
+	"tag":    TEXT_LAYOUT_TAG,
+	"logger": TEXT_LAYOUT_LOGGER,
+	"caller": TEXT_LAYOUT_CALLER,
+	"msg":    TEXT_LAYOUT_MESSAGE,
+	"fields": TEXT_LAYOUT_FIELDS,
+}
+
+// textLayoutLevels are names of levels by modifier of level segment, print
+// level has no name
+var textLayoutLevels = map[string][]string{
+	"upper": {"PANIC", "FATAL", "ERROR", "WARN", "INFO", "DEBUG", "TRACE", EMPTY_STRING},
+	"lower": {"panic", "fatal", "error", "warn", "info", "debug", "trace", EMPTY_STRING},
+	"short": {"PNC", "FTL", "ERR", "WRN", "INF", "DBG", "TRC", EMPTY_STRING},
+	"char":  {"P", "F", "E", "W", "I", "D", "T", EMPTY_STRING},
+}
+
+// textSegment is literal or value of entry with its padding, truncation and
+// color
+type textSegment struct {
+	kind         int
+	literal      string
+	width        int
+	max          int
+	isRight      bool
+	levels       []string
+	color        string
+	isLevelColor bool
+}
+
+// newTextLayout parses layout as "{time} {level:5|short} [{tag}] {msg}".
+// Segment is written as {name:width.max|modifier|modifier}, width pads value
+// by spaces on the right or on the left with ">" before it, max truncates
+// value. Modifiers are level names "upper", "lower", "short", "char" and
+// colors of colorstring, "level" colors segment by level of record. Braces
+// are escaped by doubling. Empty layout keeps default line.
+func newTextLayout(s string) ([]textSegment, error) {
+	var layout []textSegment
+
+	for len(s) > 0 {
+		i := strings.IndexAny(s, "{}")
+		if i < 0 {
+			layout = appendTextLiteral(layout, s)
+			break
+		}
+		if i+1 < len(s) && s[i+1] == s[i] {
+			layout = appendTextLiteral(layout, s[:i+1])
+			s = s[i+2:]
+			continue
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if s[i] == '}' || j < 0 {
+			return nil, errors.New(__ERROR_STR_TEXT_LAYOUT + ": " + s[i:])
+		}
+		layout = appendTextLiteral(layout, s[:i])
+
+		segment, err := newTextSegment(s[i+1 : i+j])
+		if err != nil {
+			return nil, err
+		}
+		layout = append(layout, segment)
+		s = s[i+j+1:]
+	}
+
+	return layout, nil
+}
+
+// appendTextLiteral appends literal, it is joined with previous one
+func appendTextLiteral(layout []textSegment, s string) []textSegment {
+	if s == EMPTY_STRING {
+		return layout
+	}
+	if n := len(layout); n > 0 && layout[n-1].kind == TEXT_LAYOUT_LITERAL {
+		layout[n-1].literal += s
+		return layout
+	}
+
+	return append(layout, textSegment{kind: TEXT_LAYOUT_LITERAL, literal: s})
+}
+
+// newTextSegment parses segment without braces
+func newTextSegment(s string) (textSegment, error) {
+	var (
+		segment textSegment
+		ok      bool
+		err     error
+	)
+
+	modifiers := strings.Split(s, TEXT_LAYOUT_MODIFIER)
+	name, size, isSized := strings.Cut(modifiers[0], TEXT_LAYOUT_SIZE)
+	if segment.kind, ok = textLayoutNames[name]; !ok {
+		return segment, errors.New(__ERROR_STR_TEXT_LAYOUT + ": " + s)
+	}
+	if segment.kind == TEXT_LAYOUT_LEVEL {
+		segment.levels = textLayoutLevels["upper"]
+	}
+
+	if isSized {
+		if strings.HasPrefix(size, TEXT_LAYOUT_RIGHT) {
+			segment.isRight = true
+			size = size[len(TEXT_LAYOUT_RIGHT):]
+		}
+		width, max, isMax := strings.Cut(size, TEXT_LAYOUT_MAX)
+		if width != EMPTY_STRING {
+			if segment.width, err = strconv.Atoi(width); err != nil || segment.width < 0 {
+				return segment, errors.New(__ERROR_STR_TEXT_LAYOUT + ": " + s)
+			}
+		}
+		if isMax {
+			if segment.max, err = strconv.Atoi(max); err != nil || segment.max <= 0 {
+				return segment, errors.New(__ERROR_STR_TEXT_LAYOUT + ": " + s)
+			}
+		}
+	}
+
+	for _, modifier := range modifiers[1:] {
+		if levels, ok := textLayoutLevels[modifier]; ok && segment.kind == TEXT_LAYOUT_LEVEL {
+			segment.levels = levels
+		} else if modifier == TEXT_LAYOUT_COLOR {
+			segment.isLevelColor = true
+		} else if code, ok := colorstring.DefaultColors[modifier]; ok {
+			segment.color = "\033[" + code + "m"
+		} else {
+			return segment, errors.New(__ERROR_STR_TEXT_LAYOUT + ": " + s)
+		}
+	}
+
+	return segment, nil
+}
+
+// fit truncates and pads value of segment written from start, escape codes
+// of colors are not counted
+func (s *textSegment) fit(b []byte, start int) []byte {
+	n := 0
+	for i := start; i < len(b); {
+		size := textRuneSize(b[i:])
+		if b[i] != '\033' {
+			if n == s.max && s.max > 0 {
+				b = b[:i]
+				break
+			}
+			n++
+		}
+		i += size
+	}
+
+	if pad := s.width - n; pad > 0 {
+		for i := 0; i < pad; i++ {
+			b = append(b, ' ')
+		}
+		if s.isRight {
+			copy(b[start+pad:], b[start:len(b)-pad])
+			for i := start; i < start+pad; i++ {
+				b[i] = ' '
+			}
+		}
+	}
+
+	return b
+}
+
+// textRuneSize returns size of rune or whole escape sequence of color
+func textRuneSize(b []byte) int {
+	if b[0] == '\033' && len(b) > 1 && b[1] == '[' {
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(b)
+	}
+	_, size := utf8.DecodeRune(b)
+
+	return size
+}
+
+// appendCaller appends file with its directory and line
+func appendCaller(b []byte, file string, line int) []byte {
+	if file == EMPTY_STRING {
+		return b
+	}
+	if i := strings.LastIndexByte(file, '/'); i > 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	b = append(b, file...)
+	b = append(b, ':')
+
+	return strconv.AppendInt(b, int64(line), 10)
+}
+
+// appendLayout appends entry by layout of settings
+func (t *Text) appendLayout(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	for i := 0; i < len(t.layout); i++ {
+		segment := &t.layout[i]
+		if segment.kind == TEXT_LAYOUT_LITERAL {
+			b = append(b, segment.literal...)
+			continue
+		}
+
+		isColored := t.isColorize
+		switch {
+		case !isColored:
+		case segment.color != EMPTY_STRING:
+			b = append(b, segment.color...)
+		case segment.isLevelColor || segment.kind == TEXT_LAYOUT_LEVEL:
+			b = t.color(b, e.Level)
+		default:
+			isColored = segment.kind == TEXT_LAYOUT_MESSAGE || segment.kind == TEXT_LAYOUT_FIELDS
+		}
+
+		start := len(b)
+		b = t.appendSegment(b, format, e, keys, segment)
+		b = segment.fit(b, start)
+		if isColored {
+			b = t.reset(b)
+		}
+	}
+
+	return b
+}
+
+// appendSegment appends value of segment
+func (t *Text) appendSegment(b []byte, format *Formatter, e *Entry, keys []string, segment *textSegment) []byte {
+	switch segment.kind {
+	case TEXT_LAYOUT_TIME:
+		if format.Time.IsStamp {
+			b = appendTimeStamp(b, format, e.Time)
+		} else {
+			b = appendTime(b, format, e.Time)
+		}
+	case TEXT_LAYOUT_LEVEL:
+		if e.Level < len(segment.levels) {
+			b = append(b, segment.levels[e.Level]...)
+		}
+	case TEXT_LAYOUT_ENVIRONMENT:
+		b = append(b, format.Environment...)
+	case TEXT_LAYOUT_LABELS:
+		b = append(b, format.Labels.String...)
+	case TEXT_LAYOUT_TAG:
+		b = append(b, format.Tag...)
+	case TEXT_LAYOUT_LOGGER:
+		b = append(b, e.Name...)
+	case TEXT_LAYOUT_CALLER:
+		frame := caller()
+		b = appendCaller(b, frame.File, frame.Line)
+	case TEXT_LAYOUT_MESSAGE:
+		b = t.appendString(b, format, e.Message)
+	case TEXT_LAYOUT_FIELDS:
+		separator := EMPTY_STRING
+		for i := 0; i < len(e.Fields); i++ {
+			if f := e.Fields[i].resolve(); !f.isSkipped() {
+				b, separator = t.appendField(b, format, e.Level, separator, f.Key, &f)
+			}
+		}
+		for _, key := range keys {
+			if f := valueField(key, e.Vars[key], 0); !f.isSkipped() {
+				b, separator = t.appendField(b, format, e.Level, separator, key, &f)
+			}
+		}
+		for _, f := range traceFields(format, e) {
+			b, separator = t.appendField(b, format, e.Level, separator, f.Key, &f)
+		}
+	}
+
+	return b
+}
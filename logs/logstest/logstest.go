@@ -0,0 +1,252 @@
+// Package logstest records entries of logger for assertions of tests and
+// writes records of logger by log of test.
+package logstest
+
+import (
+	"fmt"
+	"sync"
+	"strconv"
+	"strings"
+	"testing"
+
+	"bctrader/logs"
+)
+
+// Observer keeps copies of entries written by logger, filters return
+// observers with matching entries
+type Observer struct {
+	mutex   *sync.Mutex
+	entries []logs.Entry
+}
+
+// New returns logger whose entries of level and above are recorded by
+// observer. Panic and fatal entries are recorded without ending test.
+func New(l int) (*logs.Logs, *Observer) {
+	o := &Observer{mutex: &sync.Mutex{}}
+
+	ls, err := logs.New(&logs.Formatter{Level: l})
+	if err == nil {
+		var hook *logs.Hook
+		hook, err = logs.NewHook(&logs.HookSettings{Func: o.add}, &logs.Formatter{Level: l})
+		if err == nil {
+			err = ls.SetBackend(hook)
+		}
+	}
+	if err != nil {
+		// Settings are constant, so it is a bug
+		panic(err)
+	}
+
+	return ls, o
+}
+
+// add copies entry, it is reused by logger
+func (o *Observer) add(e *logs.Entry) {
+	c := *e
+	c.Fields = append([]logs.Field(nil), e.Fields...)
+	if e.Vars != nil {
+		c.Vars = make(logs.Vars, len(e.Vars))
+		for k, v := range e.Vars {
+			c.Vars[k] = v
+		}
+	}
+
+	o.mutex.Lock()
+	o.entries = append(o.entries, c)
+	o.mutex.Unlock()
+}
+
+// Len 
+func (o *Observer) Len() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return len(o.entries)
+}
+
+// All returns recorded entries in order of writing
+func (o *Observer) All() []logs.Entry {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return append([]logs.Entry(nil), o.entries...)
+}
+
+// TakeAll returns recorded entries and forgets them
+func (o *Observer) TakeAll() []logs.Entry {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	entries := o.entries
+	o.entries = nil
+
+	return entries
+}
+
+// Filter returns observer of entries matched by function
+func (o *Observer) Filter(fn func(e *logs.Entry) bool) *Observer {
+	filtered := &Observer{mutex: &sync.Mutex{}}
+
+	o.mutex.Lock()
+	for i := 0; i < len(o.entries); i++ {
+		if fn(&o.entries[i]) {
+			filtered.entries = append(filtered.entries, o.entries[i])
+		}
+	}
+	o.mutex.Unlock()
+
+	return filtered
+}
+
+// FilterLevel 
+func (o *Observer) FilterLevel(l int) *Observer {
+	return o.Filter(func(e *logs.Entry) bool {
+		return e.Level == l
+	})
+}
+
+// FilterName returns observer of entries of named logger
+func (o *Observer) FilterName(n string) *Observer {
+	return o.Filter(func(e *logs.Entry) bool {
+		return e.Name == n
+	})
+}
+
+// FilterMessage 
+func (o *Observer) FilterMessage(m string) *Observer {
+	return o.Filter(func(e *logs.Entry) bool {
+		return e.Message == m
+	})
+}
+
+// FilterMessageSnippet returns observer of entries whose message contains
+// snippet
+func (o *Observer) FilterMessageSnippet(s string) *Observer {
+	return o.Filter(func(e *logs.Entry) bool {
+		return strings.Contains(e.Message, s)
+	})
+}
+
+// FilterField returns observer of entries which have field or var with key
+// and value of field, values are compared as fmt formats them
+func (o *Observer) FilterField(f logs.Field) *Observer {
+	return o.Filter(func(e *logs.Entry) bool {
+		return hasField(e, f.Key, fmt.Sprint(f.Value()))
+	})
+}
+
+// FilterFieldKey returns observer of entries which have field or var with key
+func (o *Observer) FilterFieldKey(k string) *Observer {
+	return o.Filter(func(e *logs.Entry) bool {
+		for i := 0; i < len(e.Fields); i++ {
+			if e.Fields[i].Key == k {
+				return true
+			}
+		}
+		_, ok := e.Vars[k]
+
+		return ok
+	})
+}
+
+// AssertLogged reports error of test when no entry has level, message and
+// fields, it tells if entry is found
+func (o *Observer) AssertLogged(t testing.TB, l int, m string, f ...logs.Field) bool {
+	t.Helper()
+
+	if o.logged(l, m, f).Len() == 0 {
+		t.Errorf("no %s entry %q%s is logged, entries:\n%s", levelName(l), m, fieldsString(f), o)
+		return false
+	}
+
+	return true
+}
+
+// RequireLogged stops test when no entry has level, message and fields
+func (o *Observer) RequireLogged(t testing.TB, l int, m string, f ...logs.Field) {
+	t.Helper()
+
+	if !o.AssertLogged(t, l, m, f...) {
+		t.FailNow()
+	}
+}
+
+// AssertNotLogged reports error of test when entry has level, message and
+// fields, it tells if entry is not found
+func (o *Observer) AssertNotLogged(t testing.TB, l int, m string, f ...logs.Field) bool {
+	t.Helper()
+
+	if found := o.logged(l, m, f); found.Len() > 0 {
+		t.Errorf("%s entry %q%s is logged, entries:\n%s", levelName(l), m, fieldsString(f), found)
+		return false
+	}
+
+	return true
+}
+
+// String returns entries line by line as level, name, message and fields
+func (o *Observer) String() string {
+	var b strings.Builder
+
+	for _, e := range o.All() {
+		b.WriteString(levelName(e.Level))
+		if e.Name != logs.EMPTY_STRING {
+			b.WriteString(" " + e.Name)
+		}
+		fmt.Fprintf(&b, " %q%s", e.Message, fieldsString(e.Fields))
+		for k, v := range e.Vars {
+			fmt.Fprintf(&b, " %s=%v", k, v)
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// logged returns observer of entries with level, message and fields
+func (o *Observer) logged(l int, m string, fields []logs.Field) *Observer {
+	return o.Filter(func(e *logs.Entry) bool {
+		if e.Level != l || e.Message != m {
+			return false
+		}
+		for _, f := range fields {
+			if !hasField(e, f.Key, fmt.Sprint(f.Value())) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// hasField is true when field or var of entry has key and formatted value
+func hasField(e *logs.Entry, k string, value string) bool {
+	for i := 0; i < len(e.Fields); i++ {
+		if e.Fields[i].Key == k && fmt.Sprint(e.Fields[i].Value()) == value {
+			return true
+		}
+	}
+	v, ok := e.Vars[k]
+
+	return ok && fmt.Sprint(v) == value
+}
+
+// levelName 
+func levelName(l int) string {
+	if names := logs.LevelNames(); l >= 0 && l < len(names) {
+		return names[l]
+	}
+
+	return strconv.Itoa(l)
+}
+
+// fieldsString 
+func fieldsString(fields []logs.Field) string {
+	var b strings.Builder
+
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value())
+	}
+
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package logstest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"bctrader/logs"
+)
+
+// fakeTB records failures and logs of test helpers
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	logs     []string
+	cleanups []func()
+}
+
+// Helper 
+func (t *fakeTB) Helper() {
+}
+
+// Errorf 
+func (t *fakeTB) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, format)
+}
+
+// Log 
+func (t *fakeTB) Log(args ...interface{}) {
+	for _, arg := range args {
+		t.logs = append(t.logs, arg.(string))
+	}
+}
+
+// Cleanup 
+func (t *fakeTB) Cleanup(fn func()) {
+	t.cleanups = append(t.cleanups, fn)
+}
+
+func TestObserver(t *testing.T) {
+	ls, o := New(logs.INFO_LEVEL)
+
+	ls.Named("http").Infow("request", logs.Int("status", 200), logs.String("path", "/"))
+	ls.Warnv("slow", logs.Vars{"ms": 1500})
+	ls.Errorw(errors.New("failed"), logs.Err(errors.New("timeout")))
+	ls.Fatal(errors.New("not exit"))
+	ls.Debug("skipped")
+
+	if o.Len() != 4 {
+		t.Fatalf("unexpected entries:\n%s", o)
+	}
+	if n := o.FilterLevel(logs.WARN_LEVEL).Len(); n != 1 {
+		t.Errorf("filter by level: %d", n)
+	}
+	if n := o.FilterName("http").FilterMessage("request").FilterField(logs.Int("status", 200)).Len(); n != 1 {
+		t.Errorf("filter by name, message and field: %d", n)
+	}
+	if n := o.FilterField(logs.Int("ms", 1500)).Len() + o.FilterFieldKey(logs.KEY_ERROR).Len() + o.FilterMessageSnippet("ai").Len(); n != 3 {
+		t.Errorf("filter by var, key and snippet: %d", n)
+	}
+
+	o.RequireLogged(t, logs.INFO_LEVEL, "request", logs.String("path", "/"))
+	o.AssertLogged(t, logs.ERROR_LEVEL, "failed", logs.String(logs.KEY_ERROR, "timeout"))
+	o.AssertNotLogged(t, logs.DEBUG_LEVEL, "skipped")
+
+	fake := &fakeTB{}
+	if o.AssertLogged(fake, logs.INFO_LEVEL, "request", logs.Int("status", 500)) || !o.AssertNotLogged(fake, logs.INFO_LEVEL, "missing") || len(fake.errors) != 1 {
+		t.Errorf("unexpected assertion errors %q", fake.errors)
+	}
+
+	if entries := o.TakeAll(); len(entries) != 4 || o.Len() != 0 {
+		t.Errorf("entries are not taken")
+	}
+}
+
+func TestNewTB(t *testing.T) {
+	fake := &fakeTB{}
+	ls := NewTB(fake)
+	ls.Debugv("connected", logs.Vars{"addr": "127.0.0.1"})
+
+	if len(fake.logs) != 1 || !strings.Contains(fake.logs[0], "connected") || !strings.Contains(fake.logs[0], "addr=127.0.0.1") ||
+		!strings.HasPrefix(fake.logs[0], "logstest/logstest_test.go:") {
+		t.Errorf("unexpected test logs %q", fake.logs)
+	}
+	for _, fn := range fake.cleanups {
+		fn()
+	}
+	ls.Info("after test")
+	if len(fake.logs) != 1 {
+		t.Errorf("record after test is written %q", fake.logs)
+	}
+
+	NewTB(t).Info("written by t.Log")
+}
@@ -0,0 +1,223 @@
+package receivers
+
+import (
+	"io"
+	"net"
+	"time"
+	"bufio"
+	"bytes"
+	"errors"
+	"compress/gzip"
+)
+
+// Options of Fluent Forward
+const (
+	FLUENT_OPTION_CHUNK       = "chunk"
+	FLUENT_OPTION_COMPRESSED  = "compressed"
+	FLUENT_OPTION_ACK         = "ack"
+	FLUENT_COMPRESSED_GZIP    = "gzip"
+)
+
+// Error string messages
+const (
+	__ERROR_STR_FLUENT_EVENT   = "Invalid Fluent Forward event"
+	__ERROR_STR_FLUENT_ENTRIES = "Invalid Fluent Forward entries"
+	__ERROR_STR_FLUENT_TIME    = "Invalid Fluent Forward time"
+	__ERROR_STR_FLUENT_RECORD  = "Invalid Fluent Forward record"
+)
+
+// FluentMessage is decoded event of Fluent Forward, values of record are
+// decoded as msgpack types: int64, uint64, float64, string, []byte, bool,
+// []interface{}, map[string]interface{} and nil
+type FluentMessage struct {
+	Tag    string
+	Time   time.Time
+	Record map[string]interface{}
+}
+
+// FluentReceiver receives events of Fluent Forward protocol by TCP in
+// Message, Forward, PackedForward and CompressedPackedForward modes, chunk
+// of option is acknowledged
+type FluentReceiver struct {
+	*Messages[FluentMessage]
+	server *server
+}
+
+// NewFluentReceiver starts receiver of Fluent Forward
+func NewFluentReceiver() (*FluentReceiver, error) {
+	r := &FluentReceiver{
+		Messages: newMessages[FluentMessage](),
+	}
+
+	s, err := listenStream(nil, r.stream)
+	if err != nil {
+		return nil, err
+	}
+	r.server = s
+
+	return r, nil
+}
+
+// Addr returns address which receiver listens on
+func (r *FluentReceiver) Addr() string {
+	return r.server.addr()
+}
+
+// Close stops receiver
+func (r *FluentReceiver) Close() error {
+	return r.server.close()
+}
+
+// stream decodes events of connection until it is closed, connection is
+// dropped when event is not valid msgpack
+func (r *FluentReceiver) stream(c net.Conn) {
+	decoder := &msgpackDecoder{bufio.NewReader(c)}
+	for {
+		v, err := decoder.decode()
+		if err != nil {
+			if err != io.EOF {
+				r.fail(err)
+			}
+			return
+		}
+
+		messages, option, err := fluentEvent(v)
+		if err != nil {
+			r.fail(err)
+			continue
+		}
+		for _, m := range messages {
+			r.add(m)
+		}
+		if chunk, ok := option[FLUENT_OPTION_CHUNK].(string); ok {
+			b := append([]byte{0x81}, msgpackAppendString(nil, FLUENT_OPTION_ACK)...)
+			if _, err := c.Write(msgpackAppendString(b, chunk)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// fluentEvent decodes event of any mode and returns its option
+func fluentEvent(v interface{}) ([]FluentMessage, map[string]interface{}, error) {
+	event, ok := v.([]interface{})
+	if !ok || len(event) < 2 {
+		return nil, nil, errors.New(__ERROR_STR_FLUENT_EVENT)
+	}
+	tag, ok := event[0].(string)
+	if !ok {
+		return nil, nil, errors.New(__ERROR_STR_FLUENT_EVENT + ": tag is not string")
+	}
+
+	var (
+		messages []FluentMessage
+		err error
+	)
+
+	i := 2
+	switch entries := event[1].(type) {
+	case []interface{}:
+		messages, err = fluentEntries(tag, entries)
+	case string:
+		messages, err = fluentPacked(tag, []byte(entries), fluentOption(event, i))
+	case []byte:
+		messages, err = fluentPacked(tag, entries, fluentOption(event, i))
+	default:
+		if len(event) < 3 {
+			return nil, nil, errors.New(__ERROR_STR_FLUENT_EVENT + ": record is missing")
+		}
+		i = 3
+		var m FluentMessage
+		m, err = fluentMessage(tag, event[1], event[2])
+		messages = []FluentMessage{m}
+	}
+	option := fluentOption(event, i)
+	if err != nil {
+		return nil, option, err
+	}
+
+	return messages, option, nil
+}
+
+// fluentOption returns option of event by its index, event may have none
+func fluentOption(event []interface{}, i int) map[string]interface{} {
+	if len(event) > i {
+		option, _ := event[i].(map[string]interface{})
+		return option
+	}
+
+	return nil
+}
+
+// fluentEntries decodes entries of Forward mode
+func fluentEntries(tag string, entries []interface{}) ([]FluentMessage, error) {
+	messages := make([]FluentMessage, 0, len(entries))
+	for _, v := range entries {
+		entry, ok := v.([]interface{})
+		if !ok || len(entry) != 2 {
+			return nil, errors.New(__ERROR_STR_FLUENT_ENTRIES)
+		}
+		m, err := fluentMessage(tag, entry[0], entry[1])
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+
+	return messages, nil
+}
+
+// fluentPacked decodes stream of entries of PackedForward mode, which is
+// gzip compressed by option
+func fluentPacked(tag string, b []byte, option map[string]interface{}) ([]FluentMessage, error) {
+	var reader io.Reader = bytes.NewReader(b)
+
+	if option[FLUENT_OPTION_COMPRESSED] == FLUENT_COMPRESSED_GZIP {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.New(__ERROR_STR_FLUENT_ENTRIES + ": " + err.Error())
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var entries []interface{}
+	decoder := &msgpackDecoder{bufio.NewReader(reader)}
+	for {
+		v, err := decoder.decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(__ERROR_STR_FLUENT_ENTRIES + ": " + err.Error())
+		}
+		entries = append(entries, v)
+	}
+
+	return fluentEntries(tag, entries)
+}
+
+// fluentMessage decodes time as seconds or EventTime and record
+func fluentMessage(tag string, t interface{}, record interface{}) (FluentMessage, error) {
+	m := FluentMessage{Tag: tag}
+
+	switch v := t.(type) {
+	case time.Time:
+		m.Time = v
+	case int64:
+		m.Time = time.Unix(v, 0)
+	case uint64:
+		m.Time = time.Unix(int64(v), 0)
+	case float64:
+		m.Time = time.Unix(0, int64(v * float64(time.Second)))
+	default:
+		return m, errors.New(__ERROR_STR_FLUENT_TIME)
+	}
+
+	var ok bool
+	if m.Record, ok = record.(map[string]interface{}); !ok {
+		return m, errors.New(__ERROR_STR_FLUENT_RECORD)
+	}
+
+	return m, nil
+}
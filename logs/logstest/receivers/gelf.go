@@ -0,0 +1,213 @@
+package receivers
+
+import (
+	"io"
+	"net"
+	"sync"
+	"bytes"
+	"bufio"
+	"errors"
+	"strings"
+	"encoding/json"
+	"compress/gzip"
+	"compress/zlib"
+)
+
+// GELF chunking
+const (
+	GELF_CHUNK_MAGIC_0     = byte(0x1e)
+	GELF_CHUNK_MAGIC_1     = byte(0x0f)
+	GELF_CHUNK_HEADER_SIZE = int(12)
+	GELF_CHUNK_COUNT_MAX   = int(128)
+)
+
+// Error string messages
+const (
+	__ERROR_STR_GELF_CHUNK = "Invalid GELF chunk"
+	__ERROR_STR_GELF_JSON  = "Invalid GELF message"
+)
+
+// GELFMessage is decoded GELF message, additional fields are kept in Extra
+// with their underscore
+type GELFMessage struct {
+	Version      string                 `json:"version"`
+	Host         string                 `json:"host"`
+	ShortMessage string                 `json:"short_message"`
+	FullMessage  string                 `json:"full_message"`
+	Timestamp    float64                `json:"timestamp"`
+	Level        int                    `json:"level"`
+	Extra        map[string]interface{} `json:"-"`
+	Raw          []byte                 `json:"-"`
+}
+
+// GELFReceiver receives GELF messages by UDP or TCP
+type GELFReceiver struct {
+	*Messages[GELFMessage]
+	server *server
+	mutex  *sync.Mutex
+	chunks map[[8]byte][][]byte
+}
+
+// NewGELFUDPReceiver starts receiver of GELF datagrams, datagrams may be
+// chunked, compressed by gzip or zlib
+func NewGELFUDPReceiver() (*GELFReceiver, error) {
+	r := &GELFReceiver{
+		Messages: newMessages[GELFMessage](),
+		mutex:    &sync.Mutex{},
+		chunks:   map[[8]byte][][]byte{},
+	}
+
+	s, err := listenPacket(r.datagram)
+	if err != nil {
+		return nil, err
+	}
+	r.server = s
+
+	return r, nil
+}
+
+// NewGELFTCPReceiver starts receiver of GELF messages delimited by null byte
+func NewGELFTCPReceiver() (*GELFReceiver, error) {
+	r := &GELFReceiver{
+		Messages: newMessages[GELFMessage](),
+	}
+
+	s, err := listenStream(nil, r.stream)
+	if err != nil {
+		return nil, err
+	}
+	r.server = s
+
+	return r, nil
+}
+
+// Addr returns address which receiver listens on
+func (r *GELFReceiver) Addr() string {
+	return r.server.addr()
+}
+
+// Close stops receiver
+func (r *GELFReceiver) Close() error {
+	return r.server.close()
+}
+
+// datagram decodes datagram or keeps chunk until message is complete
+func (r *GELFReceiver) datagram(b []byte) {
+	if len(b) < 2 || b[0] != GELF_CHUNK_MAGIC_0 || b[1] != GELF_CHUNK_MAGIC_1 {
+		r.message(b)
+		return
+	}
+
+	if len(b) < GELF_CHUNK_HEADER_SIZE {
+		r.fail(errors.New(__ERROR_STR_GELF_CHUNK + ": header is short"))
+		return
+	}
+	var id [8]byte
+	copy(id[:], b[2:10])
+	seq, count := int(b[10]), int(b[11])
+	if count == 0 || count > GELF_CHUNK_COUNT_MAX || seq >= count {
+		r.fail(errors.New(__ERROR_STR_GELF_CHUNK + ": sequence is out of range"))
+		return
+	}
+
+	r.mutex.Lock()
+	chunks, ok := r.chunks[id]
+	if !ok {
+		chunks = make([][]byte, count)
+		r.chunks[id] = chunks
+	}
+	if len(chunks) != count {
+		delete(r.chunks, id)
+		r.mutex.Unlock()
+		r.fail(errors.New(__ERROR_STR_GELF_CHUNK + ": count is changed"))
+		return
+	}
+	chunks[seq] = b[GELF_CHUNK_HEADER_SIZE:]
+	for _, chunk := range chunks {
+		if chunk == nil {
+			r.mutex.Unlock()
+			return
+		}
+	}
+	delete(r.chunks, id)
+	r.mutex.Unlock()
+
+	r.message(bytes.Join(chunks, nil))
+}
+
+// stream decodes messages of connection delimited by null byte
+func (r *GELFReceiver) stream(c net.Conn) {
+	reader := bufio.NewReader(c)
+	for {
+		b, err := reader.ReadBytes(0)
+		if len(b) > 0 && b[len(b) - 1] == 0 {
+			b = b[:len(b) - 1]
+		}
+		if len(b) > 0 {
+			r.message(b)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// message decompresses and decodes message
+func (r *GELFReceiver) message(b []byte) {
+	var (
+		reader io.ReadCloser
+		err error
+	)
+
+	switch {
+	case len(b) > 1 && b[0] == 0x1f && b[1] == 0x8b:
+		reader, err = gzip.NewReader(bytes.NewReader(b))
+	case len(b) > 0 && b[0] == 0x78:
+		reader, err = zlib.NewReader(bytes.NewReader(b))
+	}
+	if err != nil {
+		r.fail(err)
+		return
+	}
+	if reader != nil {
+		b, err = io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			r.fail(err)
+			return
+		}
+	}
+
+	m, err := ParseGELF(b)
+	if err != nil {
+		r.fail(err)
+		return
+	}
+	r.add(m)
+}
+
+// ParseGELF decodes uncompressed GELF message
+func ParseGELF(b []byte) (GELFMessage, error) {
+	var (
+		m GELFMessage
+		fields map[string]interface{}
+	)
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, errors.New(__ERROR_STR_GELF_JSON + ": " + err.Error())
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return m, errors.New(__ERROR_STR_GELF_JSON + ": " + err.Error())
+	}
+	for k, v := range fields {
+		if strings.HasPrefix(k, "_") {
+			if m.Extra == nil {
+				m.Extra = map[string]interface{}{}
+			}
+			m.Extra[k] = v
+		}
+	}
+	m.Raw = b
+
+	return m, nil
+}
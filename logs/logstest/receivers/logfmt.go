@@ -0,0 +1,72 @@
+package receivers
+
+import (
+	"errors"
+	"encoding/json"
+)
+
+// Error string messages
+const (
+	__ERROR_STR_LOGFMT = "Invalid logfmt record"
+)
+
+// LogfmtMessage is decoded logfmt record, keys are in order of record and
+// key without value has empty value
+type LogfmtMessage struct {
+	Keys   []string
+	Values map[string]string
+	Raw    []byte
+}
+
+// ParseLogfmt decodes one logfmt record, quoted values are JSON strings
+func ParseLogfmt(b []byte) (LogfmtMessage, error) {
+	m := LogfmtMessage{Values: map[string]string{}, Raw: b}
+
+	for i := 0; i < len(b); {
+		if b[i] == ' ' || b[i] == '\n' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(b) && b[i] != '=' && b[i] != ' ' && b[i] != '\n' {
+			if b[i] == '"' {
+				return m, errors.New(__ERROR_STR_LOGFMT + ": " + string(b))
+			}
+			i++
+		}
+		key, value := string(b[start:i]), ""
+		if i < len(b) && b[i] == '=' {
+			i++
+			start = i
+			if i < len(b) && b[i] == '"' {
+				for i++; i < len(b) && b[i] != '"'; i++ {
+					if b[i] == '\\' {
+						i++
+					}
+				}
+				if i >= len(b) {
+					return m, errors.New(__ERROR_STR_LOGFMT + ": " + string(b))
+				}
+				i++
+				if err := json.Unmarshal(b[start:i], &value); err != nil {
+					return m, errors.New(__ERROR_STR_LOGFMT + ": " + err.Error())
+				}
+			} else {
+				for i < len(b) && b[i] != ' ' && b[i] != '\n' {
+					i++
+				}
+				value = string(b[start:i])
+			}
+		}
+		if key == "" {
+			return m, errors.New(__ERROR_STR_LOGFMT + ": " + string(b))
+		}
+		if _, ok := m.Values[key]; !ok {
+			m.Keys = append(m.Keys, key)
+		}
+		m.Values[key] = value
+	}
+
+	return m, nil
+}
@@ -0,0 +1,239 @@
+package receivers
+
+import (
+	"io"
+	"fmt"
+	"math"
+	"time"
+	"bufio"
+	"errors"
+	"strconv"
+	"encoding/binary"
+)
+
+// MSGPACK_EXT_EVENT_TIME is ext type of EventTime of Fluent Forward
+const MSGPACK_EXT_EVENT_TIME = int8(0)
+
+// MSGPACK_SIZE_MAX is the largest string, binary, array or map which is read
+const MSGPACK_SIZE_MAX = int(1 << 24)
+
+// Error string messages
+const (
+	__ERROR_STR_MSGPACK_TYPE = "Unknown msgpack type"
+	__ERROR_STR_MSGPACK_SIZE = "Too large msgpack value"
+)
+
+// msgpackExt is ext value other than EventTime
+type msgpackExt struct {
+	Type int8
+	Data []byte
+}
+
+// msgpackDecoder decodes values of msgpack: integers are int64 or uint64,
+// floats are float64, strings are string, binaries are []byte, maps are
+// map[string]interface{} by keys of any type as fmt strings and EventTime
+// is time.Time
+type msgpackDecoder struct {
+	reader *bufio.Reader
+}
+
+// decode reads one value
+func (d *msgpackDecoder) decode() (interface{}, error) {
+	c, err := d.reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c >= 0x80 && c <= 0x8f:
+		return d.decodeMap(int(c & 0x0f))
+	case c >= 0x90 && c <= 0x9f:
+		return d.decodeArray(int(c & 0x0f))
+	case c >= 0xa0 && c <= 0xbf:
+		b, err := d.read(int(c & 0x1f))
+		return string(b), err
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.size(c - 0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return d.read(n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.size(c - 0xc7)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(n)
+	case 0xca:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := d.read(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return msgpackUint(b), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		b, err := d.read(1 << (c - 0xd0))
+		if err != nil {
+			return nil, err
+		}
+		u := msgpackUint(b)
+		shift := 64 - 8 * uint(len(b))
+		return int64(u << shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.size(c - 0xd9)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		return string(b), err
+	case 0xdc, 0xdd:
+		n, err := d.size(c - 0xdc + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n)
+	case 0xde, 0xdf:
+		n, err := d.size(c - 0xde + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n)
+	}
+
+	return nil, errors.New(__ERROR_STR_MSGPACK_TYPE + ": 0x" + strconv.FormatUint(uint64(c), 16))
+}
+
+// size reads length of 1, 2 or 4 bytes by index 0, 1 or 2
+func (d *msgpackDecoder) size(i byte) (int, error) {
+	b, err := d.read(1 << i)
+	if err != nil {
+		return 0, err
+	}
+	n := msgpackUint(b)
+	if n > uint64(MSGPACK_SIZE_MAX) {
+		return 0, errors.New(__ERROR_STR_MSGPACK_SIZE + ": " + strconv.FormatUint(n, 10))
+	}
+
+	return int(n), nil
+}
+
+// read reads n bytes
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(d.reader, b)
+
+	return b, err
+}
+
+// decodeArray 
+func (d *msgpackDecoder) decodeArray(n int) ([]interface{}, error) {
+	a := make([]interface{}, n)
+	for i := range a {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+
+	return a, nil
+}
+
+// decodeMap 
+func (d *msgpackDecoder) decodeMap(n int) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		m[msgpackKey(k)] = v
+	}
+
+	return m, nil
+}
+
+// decodeExt reads type and n bytes of ext, EventTime is decoded as time
+func (d *msgpackDecoder) decodeExt(n int) (interface{}, error) {
+	t, err := d.reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(t) == MSGPACK_EXT_EVENT_TIME && n == 8 {
+		return time.Unix(int64(binary.BigEndian.Uint32(b[:4])), int64(binary.BigEndian.Uint32(b[4:]))), nil
+	}
+
+	return msgpackExt{int8(t), b}, nil
+}
+
+// msgpackUint decodes big endian integer
+func msgpackUint(b []byte) uint64 {
+	var u uint64
+	for _, c := range b {
+		u = u << 8 | uint64(c)
+	}
+
+	return u
+}
+
+// msgpackKey returns key of map as string
+func msgpackKey(k interface{}) string {
+	switch v := k.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+
+	return fmt.Sprint(k)
+}
+
+// msgpackAppendString appends string of any length
+func msgpackAppendString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0 | byte(n))
+	case n < 1 << 8:
+		b = append(b, 0xd9, byte(n))
+	case n < 1 << 16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+
+	return append(b, s...)
+}
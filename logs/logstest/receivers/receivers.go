@@ -0,0 +1,235 @@
+// Package receivers runs in-process servers of GELF, syslog and Fluent
+// Forward for tests of network backends. Servers listen on loopback by
+// random ports and keep decoded messages for assertions, messages which can
+// not be decoded are kept as errors. Records of logfmt streams are decoded
+// by ParseLogfmt.
+package receivers
+
+import (
+	"net"
+	"sync"
+	"time"
+	"errors"
+	"strconv"
+	"crypto/tls"
+)
+
+// RECEIVER_ADDRESS is address of servers, port is chosen by system
+const RECEIVER_ADDRESS = "127.0.0.1:0"
+
+// RECEIVER_DATAGRAM_SIZE_MAX is the largest datagram which is read
+const RECEIVER_DATAGRAM_SIZE_MAX = int(65536)
+
+// Error string messages
+const (
+	__ERROR_STR_TIMEOUT = "Timeout of waiting for messages"
+)
+
+// Messages keeps decoded messages of receiver in order of receiving
+type Messages[T any] struct {
+	mutex    *sync.Mutex
+	items    []T
+	errors   []error
+	received chan struct{}
+}
+
+// newMessages 
+func newMessages[T any]() *Messages[T] {
+	return &Messages[T]{
+		mutex:    &sync.Mutex{},
+		received: make(chan struct{}),
+	}
+}
+
+// add keeps message and wakes waiters
+func (m *Messages[T]) add(item T) {
+	m.mutex.Lock()
+	m.items = append(m.items, item)
+	close(m.received)
+	m.received = make(chan struct{})
+	m.mutex.Unlock()
+}
+
+// fail keeps error of decoding
+func (m *Messages[T]) fail(err error) {
+	m.mutex.Lock()
+	m.errors = append(m.errors, err)
+	m.mutex.Unlock()
+}
+
+// All returns received messages
+func (m *Messages[T]) All() []T {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]T(nil), m.items...)
+}
+
+// Len 
+func (m *Messages[T]) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.items)
+}
+
+// Errors returns errors of messages which are not decoded
+func (m *Messages[T]) Errors() []error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]error(nil), m.errors...)
+}
+
+// Reset forgets received messages and errors
+func (m *Messages[T]) Reset() {
+	m.mutex.Lock()
+	m.items = nil
+	m.errors = nil
+	m.mutex.Unlock()
+}
+
+// Wait returns messages when at least n messages are received, received
+// messages are returned with error after timeout
+func (m *Messages[T]) Wait(n int, timeout time.Duration) ([]T, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		m.mutex.Lock()
+		items, received := append([]T(nil), m.items...), m.received
+		m.mutex.Unlock()
+
+		if len(items) >= n {
+			return items, nil
+		}
+		select {
+		case <-received:
+		case <-timer.C:
+			return items, errors.New(__ERROR_STR_TIMEOUT + ": " + strconv.Itoa(len(items)) + " of " + strconv.Itoa(n))
+		}
+	}
+}
+
+// server reads datagrams or serves connections until it is closed
+type server struct {
+	listener net.Listener
+	packet   net.PacketConn
+	mutex    *sync.Mutex
+	conns    map[net.Conn]struct{}
+	group    *sync.WaitGroup
+}
+
+// listenPacket passes every datagram to handler
+func listenPacket(handle func(b []byte)) (*server, error) {
+	packet, err := net.ListenPacket("udp", RECEIVER_ADDRESS)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		packet: packet,
+		mutex:  &sync.Mutex{},
+		group:  &sync.WaitGroup{},
+	}
+	s.group.Add(1)
+	go func() {
+		defer s.group.Done()
+
+		b := make([]byte, RECEIVER_DATAGRAM_SIZE_MAX)
+		for {
+			n, _, err := packet.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			handle(append([]byte(nil), b[:n]...))
+		}
+	}()
+
+	return s, nil
+}
+
+// listenStream serves every connection by handler, connections are TLS when
+// config is set
+func listenStream(config *tls.Config, handle func(c net.Conn)) (*server, error) {
+	var (
+		listener net.Listener
+		err error
+	)
+
+	if config != nil {
+		listener, err = tls.Listen("tcp", RECEIVER_ADDRESS, config)
+	} else {
+		listener, err = net.Listen("tcp", RECEIVER_ADDRESS)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		listener: listener,
+		mutex:    &sync.Mutex{},
+		conns:    map[net.Conn]struct{}{},
+		group:    &sync.WaitGroup{},
+	}
+	s.group.Add(1)
+	go func() {
+		defer s.group.Done()
+
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mutex.Lock()
+			s.conns[c] = struct{}{}
+			s.group.Add(1)
+			s.mutex.Unlock()
+
+			go func() {
+				defer s.group.Done()
+				defer s.drop(c)
+				handle(c)
+			}()
+		}
+	}()
+
+	return s, nil
+}
+
+// drop closes connection and forgets it
+func (s *server) drop(c net.Conn) {
+	c.Close()
+
+	s.mutex.Lock()
+	delete(s.conns, c)
+	s.mutex.Unlock()
+}
+
+// addr returns address which server listens on
+func (s *server) addr() string {
+	if s.packet != nil {
+		return s.packet.LocalAddr().String()
+	}
+
+	return s.listener.Addr().String()
+}
+
+// close stops server and waits for its handlers
+func (s *server) close() error {
+	var err error
+
+	if s.packet != nil {
+		err = s.packet.Close()
+	} else {
+		err = s.listener.Close()
+		s.mutex.Lock()
+		for c := range s.conns {
+			c.Close()
+		}
+		s.mutex.Unlock()
+	}
+	s.group.Wait()
+
+	return err
+}
@@ -0,0 +1,294 @@
+package receivers
+
+import (
+	"net"
+	"time"
+	"bytes"
+	"strconv"
+	"testing"
+	"crypto/tls"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+)
+
+// testTimeout 
+const testTimeout = 5 * time.Second
+
+// testGELF 
+const testGELF = `{"version":"1.1","host":"test","short_message":"chunked","level":6,"_tag":"bctrader"}`
+
+// send writes every frame by one connection
+func send(t *testing.T, network string, address string, config *tls.Config, frames ...[]byte) {
+	t.Helper()
+
+	var (
+		c net.Conn
+		err error
+	)
+
+	if config != nil {
+		c, err = tls.Dial("tcp", address, config)
+	} else {
+		c, err = net.Dial(network, address)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for _, b := range frames {
+		if _, err := c.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGELFUDPReceiver(t *testing.T) {
+	r, err := NewGELFUDPReceiver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(testGELF))
+	w.Close()
+
+	b := gz.Bytes()
+	id := []byte("12345678")
+	var chunks [][]byte
+	for i, size := 0, (len(b) + 2) / 3; i < 3; i++ {
+		chunk := append([]byte{GELF_CHUNK_MAGIC_0, GELF_CHUNK_MAGIC_1}, id...)
+		chunk = append(chunk, byte(i), 3)
+		chunks = append(chunks, append(chunk, b[i * size:min((i + 1) * size, len(b))]...))
+	}
+	send(t, "udp", r.Addr(), nil, chunks[2], chunks[0], chunks[1])
+
+	var zl bytes.Buffer
+	zw := zlib.NewWriter(&zl)
+	zw.Write([]byte(`{"version":"1.1","host":"test","short_message":"zlib","level":3}`))
+	zw.Close()
+	send(t, "udp", r.Addr(), nil, zl.Bytes())
+
+	messages, err := r.Wait(2, testTimeout)
+	if err != nil {
+		t.Fatal(err, r.Errors())
+	}
+	for _, m := range messages {
+		switch m.ShortMessage {
+		case "chunked":
+			if m.Level != 6 || m.Host != "test" || m.Extra["_tag"] != "bctrader" || string(m.Raw) != testGELF {
+				t.Errorf("unexpected message %+v", m)
+			}
+		case "zlib":
+			if m.Level != 3 || len(m.Extra) != 0 {
+				t.Errorf("unexpected message %+v", m)
+			}
+		default:
+			t.Errorf("unexpected message %+v", m)
+		}
+	}
+}
+
+func TestGELFTCPReceiver(t *testing.T) {
+	r, err := NewGELFTCPReceiver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	send(t, "tcp", r.Addr(), nil, []byte(testGELF + "\x00" + `{"short_message":"second"}` + "\x00"), []byte("{bad}\x00"))
+
+	messages, err := r.Wait(2, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if messages[0].ShortMessage != "chunked" || messages[1].ShortMessage != "second" {
+		t.Errorf("unexpected messages %+v", messages)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(r.Errors()) != 1 {
+		t.Errorf("invalid message is not kept as error: %v", r.Errors())
+	}
+
+	r.Reset()
+	if r.Len() != 0 || len(r.Errors()) != 0 {
+		t.Error("receiver is not reset")
+	}
+}
+
+func TestSyslogReceiver(t *testing.T) {
+	udp, err := NewSyslogReceiver(SYSLOG_NETWORK_UDP, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+
+	send(t, "udp", udp.Addr(), nil, []byte("<14>Oct  8 12:00:01.123 host app[42]: hello world"))
+	messages, err := udp.Wait(1, testTimeout)
+	if err != nil {
+		t.Fatal(err, udp.Errors())
+	}
+	m := messages[0]
+	if m.Facility != 1 || m.Severity != 6 || m.Hostname != "host" || m.AppName != "app" || m.ProcID != "42" ||
+		m.Message != "hello world" || m.Timestamp.Nanosecond() != 123000000 || m.Timestamp.Day() != 8 || m.Framing != SYSLOG_FRAMING_NONE {
+		t.Errorf("unexpected RFC 3164 message %+v", m)
+	}
+
+	tcp, err := NewSyslogReceiver(SYSLOG_NETWORK_TCP, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+
+	rfc5424 := `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Appl\"ication\]"][trace@32473 trace_id="1"] An application event`
+	send(t, "tcp", tcp.Addr(), nil, []byte(strconv.Itoa(len(rfc5424)) + " " + rfc5424 + "<13>Oct 11 22:14:15 su: local\n"))
+	messages, err = tcp.Wait(2, testTimeout)
+	if err != nil {
+		t.Fatal(err, tcp.Errors())
+	}
+	m = messages[0]
+	if m.Version != 1 || m.Priority != 165 || m.Hostname != "mymachine" || m.AppName != "evntslog" || m.ProcID != "" || m.MsgID != "ID47" ||
+		m.Message != "An application event" || m.Framing != SYSLOG_FRAMING_OCTET_COUNTING || !m.Timestamp.Equal(time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC)) {
+		t.Errorf("unexpected RFC 5424 message %+v", m)
+	}
+	if len(m.StructuredData) != 2 || m.StructuredData[0].ID != "exampleSDID@32473" || len(m.StructuredData[0].Params) != 2 ||
+		m.StructuredData[0].Params[1] != (SyslogSDParam{"eventSource", `Appl"ication]`}) || m.StructuredData[1].Params[0].Value != "1" {
+		t.Errorf("unexpected structured data %+v", m.StructuredData)
+	}
+	m = messages[1]
+	if m.Hostname != "" || m.AppName != "su" || m.Message != "local" || m.Framing != SYSLOG_FRAMING_NON_TRANSPARENT {
+		t.Errorf("unexpected unix message %+v", m)
+	}
+
+	tlsReceiver, err := NewSyslogReceiver(SYSLOG_NETWORK_TLS, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tlsReceiver.Close()
+
+	send(t, "tcp", tlsReceiver.Addr(), tlsReceiver.ClientTLSConfig(), []byte("<34>1 - - - - - - nil\x00"))
+	messages, err = tlsReceiver.Wait(1, testTimeout)
+	if err != nil {
+		t.Fatal(err, tlsReceiver.Errors())
+	}
+	if m = messages[0]; !m.Timestamp.IsZero() || m.Hostname != "" || m.StructuredData != nil || m.Message != "nil" {
+		t.Errorf("unexpected nil values %+v", m)
+	}
+
+	if _, err := NewSyslogReceiver("sctp", nil); err == nil {
+		t.Error("unknown network is accepted")
+	}
+}
+
+func TestParseSyslog(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"14> no priority",
+		"<192>1 - - - - - -",
+		"<14>1 - - -",
+		"<14>1 - - - - - [id broken",
+		"<14>1 - - - - - [id a=\"b\"]x",
+		"<14>Oct",
+	} {
+		if _, err := ParseSyslog([]byte(s)); err == nil {
+			t.Errorf("invalid message %q is parsed", s)
+		}
+	}
+}
+
+func TestParseLogfmt(t *testing.T) {
+	m, err := ParseLogfmt([]byte(`level=info msg="a \"b\"\nc" ok n=1` + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Keys) != 4 || m.Keys[1] != "msg" || m.Values["msg"] != "a \"b\"\nc" || m.Values["ok"] != "" || m.Values["n"] != "1" {
+		t.Errorf("unexpected record %+v", m)
+	}
+	for _, s := range []string{`msg="open`, `=value`, `k"ey=1`} {
+		if _, err := ParseLogfmt([]byte(s)); err == nil {
+			t.Errorf("invalid record %q is parsed", s)
+		}
+	}
+}
+
+func TestFluentReceiver(t *testing.T) {
+	r, err := NewFluentReceiver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	eventTime := []byte{0xd7, 0x00}
+	eventTime = binary.BigEndian.AppendUint32(eventTime, 1700000000)
+	eventTime = binary.BigEndian.AppendUint32(eventTime, 500)
+	record := func(m string) []byte {
+		b := append([]byte{0x82}, msgpackAppendString(nil, "message")...)
+		b = msgpackAppendString(b, m)
+		b = append(b, msgpackAppendString(nil, "level")...)
+		return append(b, 0xcd, 0x01, 0x00)
+	}
+
+	message := append([]byte{0x93}, msgpackAppendString(nil, "app.message")...)
+	message = append(message, 0xce, 0x65, 0x53, 0xf1, 0x00)
+	message = append(message, record("message mode")...)
+
+	forward := append([]byte{0x92}, msgpackAppendString(nil, "app.forward")...)
+	forward = append(forward, 0x92, 0x92)
+	forward = append(append(forward, eventTime...), record("first")...)
+	forward = append(forward, 0x92, 0x01)
+	forward = append(forward, record("second")...)
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(append(append([]byte{0x92}, eventTime...), record("packed")...))
+	w.Close()
+	packed := append([]byte{0x93}, msgpackAppendString(nil, "app.packed")...)
+	packed = append(packed, 0xc5)
+	packed = binary.BigEndian.AppendUint16(packed, uint16(gz.Len()))
+	packed = append(packed, gz.Bytes()...)
+	packed = append(packed, 0x82)
+	packed = msgpackAppendString(packed, FLUENT_OPTION_COMPRESSED)
+	packed = msgpackAppendString(packed, FLUENT_COMPRESSED_GZIP)
+	packed = msgpackAppendString(packed, FLUENT_OPTION_CHUNK)
+	packed = msgpackAppendString(packed, "c2VjcmV0")
+
+	c, err := net.Dial("tcp", r.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for _, b := range [][]byte{message, forward, packed} {
+		if _, err := c.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.SetReadDeadline(time.Now().Add(testTimeout))
+	ack := make([]byte, 64)
+	n, err := c.Read(ack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := msgpackAppendString(append([]byte{0x81}, msgpackAppendString(nil, FLUENT_OPTION_ACK)...), "c2VjcmV0"); !bytes.Equal(ack[:n], expected) {
+		t.Errorf("unexpected ack %x", ack[:n])
+	}
+
+	messages, err := r.Wait(4, testTimeout)
+	if err != nil {
+		t.Fatal(err, r.Errors())
+	}
+	for i, expected := range []FluentMessage{
+		{"app.message", time.Unix(1700000000, 0), map[string]interface{}{"message": "message mode"}},
+		{"app.forward", time.Unix(1700000000, 500), map[string]interface{}{"message": "first"}},
+		{"app.forward", time.Unix(1, 0), map[string]interface{}{"message": "second"}},
+		{"app.packed", time.Unix(1700000000, 500), map[string]interface{}{"message": "packed"}},
+	} {
+		m := messages[i]
+		if m.Tag != expected.Tag || !m.Time.Equal(expected.Time) || m.Record["message"] != expected.Record["message"] || m.Record["level"] != uint64(256) {
+			t.Errorf("unexpected message %d %+v", i, m)
+		}
+	}
+}
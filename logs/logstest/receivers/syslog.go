@@ -0,0 +1,421 @@
+package receivers
+
+import (
+	"io"
+	"net"
+	"time"
+	"bufio"
+	"errors"
+	"strconv"
+	"strings"
+	"math/big"
+	"crypto/tls"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509/pkix"
+)
+
+// Networks of syslog receiver
+const (
+	SYSLOG_NETWORK_UDP = "udp"
+	SYSLOG_NETWORK_TCP = "tcp"
+	SYSLOG_NETWORK_TLS = "tls"
+)
+
+// Framing of syslog messages of stream, datagrams have none
+const (
+	SYSLOG_FRAMING_NONE            = ""
+	SYSLOG_FRAMING_OCTET_COUNTING  = "octet-counting"
+	SYSLOG_FRAMING_NON_TRANSPARENT = "non-transparent"
+)
+
+// SYSLOG_NIL is nil value of RFC 5424 header field
+const SYSLOG_NIL = "-"
+
+// SYSLOG_FRAME_SIZE_MAX is the largest octet-counted frame which is read
+const SYSLOG_FRAME_SIZE_MAX = int(1 << 20)
+
+// SYSLOG_STAMP is time stamp of RFC 3164, fraction of second is optional
+const SYSLOG_STAMP = "Jan _2 15:04:05"
+
+// Error string messages
+const (
+	__ERROR_STR_SYSLOG_NETWORK = "Unknown syslog network"
+	__ERROR_STR_SYSLOG_PRI     = "Invalid syslog priority"
+	__ERROR_STR_SYSLOG_HEADER  = "Invalid syslog header"
+	__ERROR_STR_SYSLOG_SD      = "Invalid syslog structured data"
+	__ERROR_STR_SYSLOG_FRAME   = "Invalid syslog frame"
+)
+
+// SyslogSDParam is SD-PARAM of RFC 5424, value is unescaped
+type SyslogSDParam struct {
+	Name  string
+	Value string
+}
+
+// SyslogSDElement is SD-ELEMENT of RFC 5424
+type SyslogSDElement struct {
+	ID     string
+	Params []SyslogSDParam
+}
+
+// SyslogMessage is decoded RFC 3164 or RFC 5424 message. Version is zero for
+// RFC 3164, its tag is AppName and pid is ProcID. Nil values of RFC 5424 are
+// empty.
+type SyslogMessage struct {
+	Priority       int
+	Facility       int
+	Severity       int
+	Version        int
+	Timestamp      time.Time
+	Hostname       string
+	AppName        string
+	ProcID         string
+	MsgID          string
+	StructuredData []SyslogSDElement
+	Message        string
+	Framing        string
+	Raw            []byte
+}
+
+// SyslogReceiver receives syslog messages by UDP, TCP or TLS
+type SyslogReceiver struct {
+	*Messages[SyslogMessage]
+	server *server
+	client *tls.Config
+}
+
+// NewSyslogReceiver starts receiver on network "udp", "tcp" or "tls". Config
+// is used by "tls", self-signed certificate of loopback is made when it is
+// nil, ClientTLSConfig returns config which trusts it.
+func NewSyslogReceiver(network string, config *tls.Config) (*SyslogReceiver, error) {
+	var (
+		s *server
+		err error
+	)
+
+	r := &SyslogReceiver{
+		Messages: newMessages[SyslogMessage](),
+	}
+
+	switch network {
+	case SYSLOG_NETWORK_UDP:
+		s, err = listenPacket(r.datagram)
+	case SYSLOG_NETWORK_TCP:
+		s, err = listenStream(nil, r.stream)
+	case SYSLOG_NETWORK_TLS:
+		if config == nil {
+			config, r.client, err = selfSignedTLS()
+		}
+		if err == nil {
+			s, err = listenStream(config, r.stream)
+		}
+	default:
+		err = errors.New(__ERROR_STR_SYSLOG_NETWORK + ": " + network)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.server = s
+
+	return r, nil
+}
+
+// Addr returns address which receiver listens on
+func (r *SyslogReceiver) Addr() string {
+	return r.server.addr()
+}
+
+// ClientTLSConfig returns config of client which trusts self-signed
+// certificate of receiver, it is nil when certificate is not made
+func (r *SyslogReceiver) ClientTLSConfig() *tls.Config {
+	if r.client == nil {
+		return nil
+	}
+
+	return r.client.Clone()
+}
+
+// Close stops receiver
+func (r *SyslogReceiver) Close() error {
+	return r.server.close()
+}
+
+// datagram decodes one message
+func (r *SyslogReceiver) datagram(b []byte) {
+	r.message(b, SYSLOG_FRAMING_NONE)
+}
+
+// stream decodes messages of connection, frame is octet-counted when it
+// starts by digit, otherwise it is terminated by LF or NUL
+func (r *SyslogReceiver) stream(c net.Conn) {
+	reader := bufio.NewReader(c)
+	for {
+		first, err := reader.Peek(1)
+		if err != nil {
+			return
+		}
+
+		if first[0] >= '0' && first[0] <= '9' {
+			s, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSuffix(s, " "))
+			if err != nil || n <= 0 || n > SYSLOG_FRAME_SIZE_MAX {
+				r.fail(errors.New(__ERROR_STR_SYSLOG_FRAME + ": " + s))
+				return
+			}
+			b := make([]byte, n)
+			if _, err := io.ReadFull(reader, b); err != nil {
+				r.fail(errors.New(__ERROR_STR_SYSLOG_FRAME + ": " + err.Error()))
+				return
+			}
+			r.message(b, SYSLOG_FRAMING_OCTET_COUNTING)
+			continue
+		}
+
+		b, err := readFrame(reader)
+		if len(b) > 0 {
+			r.message(b, SYSLOG_FRAMING_NON_TRANSPARENT)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readFrame reads frame until LF or NUL, trailer is dropped
+func readFrame(reader *bufio.Reader) ([]byte, error) {
+	var b []byte
+
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return b, err
+		}
+		if c == '\n' || c == 0 {
+			return b, nil
+		}
+		b = append(b, c)
+	}
+}
+
+// message decodes message and keeps it with its framing
+func (r *SyslogReceiver) message(b []byte, framing string) {
+	m, err := ParseSyslog(b)
+	if err != nil {
+		r.fail(err)
+		return
+	}
+	m.Framing = framing
+	r.add(m)
+}
+
+// ParseSyslog decodes RFC 5424 message or RFC 3164 message, whose hostname
+// may be missing as of local socket
+func ParseSyslog(b []byte) (SyslogMessage, error) {
+	m := SyslogMessage{Raw: append([]byte(nil), b...)}
+	s := strings.TrimRight(string(b), "\r\n")
+
+	end := strings.IndexByte(s, '>')
+	if !strings.HasPrefix(s, "<") || end < 2 || end > 4 {
+		return m, errors.New(__ERROR_STR_SYSLOG_PRI + ": " + s)
+	}
+	p, err := strconv.Atoi(s[1:end])
+	if err != nil || p < 0 || p > 191 {
+		return m, errors.New(__ERROR_STR_SYSLOG_PRI + ": " + s)
+	}
+	m.Priority, m.Facility, m.Severity = p, p / 8, p % 8
+	s = s[end + 1:]
+
+	if len(s) > 1 && s[0] >= '1' && s[0] <= '9' {
+		if v, rest, ok := strings.Cut(s, " "); ok {
+			if m.Version, err = strconv.Atoi(v); err == nil {
+				return m, parseRFC5424(&m, rest)
+			}
+		}
+	}
+
+	return m, parseRFC3164(&m, s)
+}
+
+// parseRFC5424 parses header after version, structured data and message
+func parseRFC5424(m *SyslogMessage, s string) error {
+	fields := strings.SplitN(s, " ", 6)
+	if len(fields) < 6 {
+		return errors.New(__ERROR_STR_SYSLOG_HEADER + ": " + s)
+	}
+
+	if fields[0] != SYSLOG_NIL {
+		t, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return errors.New(__ERROR_STR_SYSLOG_HEADER + ": " + err.Error())
+		}
+		m.Timestamp = t
+	}
+	for i, dst := range []*string{&m.Hostname, &m.AppName, &m.ProcID, &m.MsgID} {
+		if fields[i + 1] != SYSLOG_NIL {
+			*dst = fields[i + 1]
+		}
+	}
+
+	s = fields[5]
+	if strings.HasPrefix(s, SYSLOG_NIL) {
+		s = s[1:]
+	} else {
+		var err error
+		if m.StructuredData, s, err = parseStructuredData(s); err != nil {
+			return err
+		}
+	}
+	if s != "" && s[0] != ' ' {
+		return errors.New(__ERROR_STR_SYSLOG_SD + ": " + s)
+	}
+	if s != "" {
+		m.Message = strings.TrimPrefix(s[1:], "\xef\xbb\xbf")
+	}
+
+	return nil
+}
+
+// parseStructuredData parses SD-ELEMENTs and returns rest of message
+func parseStructuredData(s string) ([]SyslogSDElement, string, error) {
+	var elements []SyslogSDElement
+
+	for strings.HasPrefix(s, "[") {
+		end := strings.IndexAny(s, " ]")
+		if end < 2 {
+			return nil, s, errors.New(__ERROR_STR_SYSLOG_SD + ": " + s)
+		}
+		element := SyslogSDElement{ID: s[1:end]}
+		s = s[end:]
+
+		for strings.HasPrefix(s, " ") {
+			eq := strings.Index(s, "=\"")
+			if eq < 2 {
+				return nil, s, errors.New(__ERROR_STR_SYSLOG_SD + ": " + s)
+			}
+			param := SyslogSDParam{Name: s[1:eq]}
+			s = s[eq + 2:]
+
+			var value strings.Builder
+			for {
+				if s == "" {
+					return nil, s, errors.New(__ERROR_STR_SYSLOG_SD + ": value is not closed")
+				}
+				if s[0] == '"' {
+					s = s[1:]
+					break
+				}
+				if s[0] == '\\' && len(s) > 1 && (s[1] == '"' || s[1] == '\\' || s[1] == ']') {
+					s = s[1:]
+				}
+				value.WriteByte(s[0])
+				s = s[1:]
+			}
+			param.Value = value.String()
+			element.Params = append(element.Params, param)
+		}
+
+		if !strings.HasPrefix(s, "]") {
+			return nil, s, errors.New(__ERROR_STR_SYSLOG_SD + ": " + s)
+		}
+		s = s[1:]
+		elements = append(elements, element)
+	}
+	if elements == nil {
+		return nil, s, errors.New(__ERROR_STR_SYSLOG_SD + ": " + s)
+	}
+
+	return elements, s, nil
+}
+
+// parseRFC3164 parses time stamp, optional hostname, tag with pid and
+// message. Year of time stamp is current one.
+func parseRFC3164(m *SyslogMessage, s string) error {
+	if len(s) < len(SYSLOG_STAMP) {
+		return errors.New(__ERROR_STR_SYSLOG_HEADER + ": " + s)
+	}
+	end := len(SYSLOG_STAMP)
+	if end < len(s) && s[end] == '.' {
+		end++
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+	}
+	layout := SYSLOG_STAMP
+	if end > len(SYSLOG_STAMP) {
+		layout += "." + strings.Repeat("0", end - len(SYSLOG_STAMP) - 1)
+	}
+	t, err := time.ParseInLocation(layout, s[:end], time.Local)
+	if err != nil {
+		return errors.New(__ERROR_STR_SYSLOG_HEADER + ": " + err.Error())
+	}
+	m.Timestamp = t.AddDate(time.Now().Year(), 0, 0)
+	s = strings.TrimPrefix(s[end:], " ")
+
+	word, rest, _ := strings.Cut(s, " ")
+	if !strings.HasSuffix(word, ":") && !strings.Contains(word, "[") {
+		m.Hostname, s = word, rest
+	}
+
+	tag, message, ok := strings.Cut(s, ": ")
+	if !ok {
+		tag, ok = strings.CutSuffix(s, ":")
+	}
+	if !ok || strings.Contains(tag, " ") {
+		m.Message = s
+		return nil
+	}
+	if name, pid, ok := strings.Cut(tag, "["); ok && strings.HasSuffix(pid, "]") {
+		m.AppName, m.ProcID = name, strings.TrimSuffix(pid, "]")
+	} else {
+		m.AppName = tag
+	}
+	m.Message = message
+
+	return nil
+}
+
+// selfSignedTLS makes certificate of loopback, it returns config of server
+// and config of client which trusts it
+func selfSignedTLS() (*tls.Config, *tls.Config, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "receivers"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &tls.Config{
+			Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}},
+			MinVersion:   tls.VersionTLS12,
+		}, &tls.Config{
+			RootCAs:    pool,
+			ServerName: "127.0.0.1",
+			MinVersion: tls.VersionTLS12,
+		}, nil
+}
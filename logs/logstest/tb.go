@@ -0,0 +1,70 @@
+package logstest
+
+import (
+	"sync"
+	"strings"
+	"testing"
+
+	"bctrader/logs"
+)
+
+// TB_LAYOUT is layout of records of test logger. Location which t.Log adds
+// is inside of logs, so caller of record leads the record.
+const TB_LAYOUT = "{caller}: {level} {msg} {fields} {logger}"
+
+// tbWriter writes records by log of test, records written after end of
+// test are dropped, because testing panics on them
+type tbWriter struct {
+	t      testing.TB
+	mutex  *sync.Mutex
+	isDone bool
+}
+
+// NewTB returns logger writing text records by t.Log, so output belongs to
+// the test and it is shown for failed or verbose tests. Records start with
+// caller by TB_LAYOUT. Logger without Formatter writes all levels.
+func NewTB(t testing.TB, f ...*logs.Formatter) *logs.Logs {
+	format := &logs.Formatter{Level: logs.TRACE_LEVEL}
+	if len(f) > 0 && f[0] != nil {
+		format = f[0]
+	}
+	w := &tbWriter{
+		t:     t,
+		mutex: &sync.Mutex{},
+	}
+	t.Cleanup(w.done)
+
+	ls, err := logs.New(format)
+	if err == nil {
+		var text *logs.Text
+		text, err = logs.NewText(&logs.TextSettings{Writer: w, Layout: TB_LAYOUT}, format)
+		if err == nil {
+			err = ls.SetBackend(text)
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ls
+}
+
+// Write 
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.isDone {
+		w.t.Helper()
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+
+	return len(p), nil
+}
+
+// done 
+func (w *tbWriter) done() {
+	w.mutex.Lock()
+	w.isDone = true
+	w.mutex.Unlock()
+}
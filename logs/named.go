@@ -0,0 +1,161 @@
+package logs
+
+import (
+	"sort"
+	"sync"
+	"errors"
+	"strings"
+	"sync/atomic"
+)
+
+// 
+const (
+	NAMED_SEPARATOR        = DOT_STRING
+	NAMED_LEVELS_SEPARATOR = LABELS_SEPARATOR
+	NAMED_LEVEL_EQUALLY    = "="
+)
+
+// Error string messages
+const (
+	__ERROR_STR_NAME         = "Invalid logger name"
+	__ERROR_STR_NAMED_LEVELS = "Invalid named log levels"
+)
+
+// namedLevels keeps log level overrides by logger name prefix. The map is
+// copy-on-write: lookups are lock-free, changes publish a new map.
+type namedLevels struct {
+	mutex  *sync.Mutex
+	levels atomic.Pointer[map[string]int]
+}
+
+// newNamedLevels 
+func newNamedLevels() *namedLevels {
+	n := &namedLevels{
+		mutex: &sync.Mutex{},
+	}
+	n.levels.Store(&map[string]int{})
+
+	return n
+}
+
+// namedJoin 
+func namedJoin(parent string, name string) string {
+	if parent == EMPTY_STRING {
+		return name
+	}
+
+	return parent + NAMED_SEPARATOR + name
+}
+
+// namedCheck 
+func namedCheck(name string) error {
+	if name == EMPTY_STRING || strings.HasPrefix(name, NAMED_SEPARATOR) || strings.HasSuffix(name, NAMED_SEPARATOR) || strings.Contains(name, NAMED_SEPARATOR+NAMED_SEPARATOR) {
+		return errors.New(__ERROR_STR_NAME)
+	}
+
+	return nil
+}
+
+// parseNamedLevels parses list like "storage=debug,http=warn"
+func parseNamedLevels(s string) (map[string]int, error) {
+	levels := map[string]int{}
+
+	for _, item := range strings.Split(s, NAMED_LEVELS_SEPARATOR) {
+		item = strings.TrimSpace(item)
+		if item == EMPTY_STRING {
+			continue
+		}
+
+		pair := strings.SplitN(item, NAMED_LEVEL_EQUALLY, 2)
+		if len(pair) != 2 {
+			return nil, errors.New(__ERROR_STR_NAMED_LEVELS)
+		}
+		name := strings.TrimSpace(pair[0])
+		level := strings.ToLower(strings.TrimSpace(pair[1]))
+		if namedCheck(name) != nil || !IsLevelName(level) || sliceIndex(levelNames, level) > TRACE_LEVEL {
+			return nil, errors.New(__ERROR_STR_NAMED_LEVELS)
+		}
+		levels[name] = sliceIndex(levelNames, level)
+	}
+
+	return levels, nil
+}
+
+// level returns level of the longest matching name prefix
+func (n *namedLevels) level(name string) (int, bool) {
+	levels := *n.levels.Load()
+
+	if len(levels) == 0 {
+		return 0, false
+	}
+
+	for name != EMPTY_STRING {
+		if l, ok := levels[name]; ok {
+			return l, true
+		}
+
+		i := strings.LastIndex(name, NAMED_SEPARATOR)
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	return 0, false
+}
+
+// update copies levels, applies fn and publishes the copy
+func (n *namedLevels) update(fn func(levels map[string]int)) {
+	n.mutex.Lock()
+	old := *n.levels.Load()
+	levels := make(map[string]int, len(old)+1)
+	for name, l := range old {
+		levels[name] = l
+	}
+	fn(levels)
+	n.levels.Store(&levels)
+	n.mutex.Unlock()
+}
+
+// set 
+func (n *namedLevels) set(name string, l int) {
+	n.update(func(levels map[string]int) {
+		levels[name] = l
+	})
+}
+
+// unset 
+func (n *namedLevels) unset(name string) {
+	n.update(func(levels map[string]int) {
+		delete(levels, name)
+	})
+}
+
+// parse replaces all overrides
+func (n *namedLevels) parse(s string) error {
+	levels, err := parseNamedLevels(s)
+
+	if err == nil {
+		n.mutex.Lock()
+		n.levels.Store(&levels)
+		n.mutex.Unlock()
+	}
+
+	return err
+}
+
+// String 
+func (n *namedLevels) String() string {
+	levels := *n.levels.Load()
+	names := make([]string, 0, len(levels))
+	for name := range levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]string, len(names))
+	for i, name := range names {
+		list[i] = name + NAMED_LEVEL_EQUALLY + levelNames[levels[name]]
+	}
+
+	return strings.Join(list, NAMED_LEVELS_SEPARATOR)
+}
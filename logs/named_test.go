@@ -0,0 +1,65 @@
+package logs
+
+import "testing"
+
+func TestNamedLevels(t *testing.T) {
+	n := newNamedLevels()
+
+	if err := n.parse("storage=debug, http=WARN,storage.s3=trace"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		level int
+		ok    bool
+	}{
+		{"storage", DEBUG_LEVEL, true},
+		{"storage.db", DEBUG_LEVEL, true},
+		{"storage.s3", TRACE_LEVEL, true},
+		{"storage.s3.put", TRACE_LEVEL, true},
+		{"http.server", WARN_LEVEL, true},
+		{"httpd", 0, false},
+		{"grpc", 0, false},
+	}
+	for _, test := range tests {
+		if l, ok := n.level(test.name); l != test.level || ok != test.ok {
+			t.Errorf("%q: got %d %v, want %d %v", test.name, l, ok, test.level, test.ok)
+		}
+	}
+
+	if s := n.String(); s != "http=warn,storage=debug,storage.s3=trace" {
+		t.Errorf("unexpected levels string %q", s)
+	}
+
+	for _, s := range []string{"storage", "storage=verbose", ".storage=info", "=info"} {
+		if err := n.parse(s); err == nil {
+			t.Errorf("%q should be invalid", s)
+		}
+	}
+}
+
+func TestNamed(t *testing.T) {
+	ls, err := New(&Formatter{Level: INFO_LEVEL, NamedLevels: "storage=debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s3 := ls.Named("storage").Named("s3")
+	if s3.Name() != "storage.s3" {
+		t.Fatalf("unexpected name %q", s3.Name())
+	}
+	if s3.Level() != DEBUG_LEVEL || ls.Named("http").Level() != INFO_LEVEL {
+		t.Fatal("named level is not applied")
+	}
+
+	s3.SetLevelName("error")
+	if s3.Level() != ERROR_LEVEL || ls.Named("storage").Level() != DEBUG_LEVEL || ls.Level() != INFO_LEVEL {
+		t.Fatal("named level is not changed")
+	}
+
+	s3.UnsetLevel()
+	if s3.Level() != DEBUG_LEVEL {
+		t.Fatal("named level is not unset")
+	}
+}
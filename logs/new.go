@@ -3,10 +3,12 @@ package logs
 import (
 	"os"
 	"io"
+	"fmt"
 	"log"
 	"time"
 	"sync"
 	"errors"
+	"sync/atomic"
 	"strconv"
 	"runtime"
 	"strings"
@@ -63,9 +65,14 @@ var keys = []string{
 	"timestamp",
 	"env",
 	"tag",
+	"logger",
+	KEY_TRACE_ID,
+	KEY_SPAN_ID,
+	KEY_TRACE_FLAGS,
 }
 
-// formats 
+// formats are formats of names, backends which need settings, e.g. OTLP,
+// Slog handler or Hook function, are set by SetBackend only
 var formats = []int{
 	TEXT_FORMAT,
 	JSON_FORMAT,
@@ -180,21 +187,146 @@ type logger interface {
 	Printf(s string, i ...interface{})
 	Println(i ...interface{})
 	Close()                            error
+	write(e *Entry)
+	formatter()                        *Formatter
+}
+
+// Backend is logger of format, e.g. *Text, *JSON, *OTLP or *Slog. It is
+// implemented by loggers of this package only.
+type Backend interface {
+	logger
+}
+
+// sprinter is implemented by loggers which format params by themselves
+type sprinter interface {
+	sprintf(l int, s string, i ...interface{}) string
+	sprintln(l int, i ...interface{}) string
 }
 
 // Error string messages
 const (
 	__ERROR_STR_FORMAT                = "Invalid log format"
 	__ERROR_STR_FORMAT_NAME           = "Invalid log format name"
+	__ERROR_STR_FORMAT_BUILD          = "Logger of format can not be built by name"
 	__ERROR_STR_LEVEL                 = "Invalid log level"
 	__ERROR_STR_LEVEL_NAME            = "Invalid log level name"
 	__ERROR_STR_TIME_STAMP_LEVEL      = "Invalid timestamp level"
 	__ERROR_STR_TIME_STAMP_LEVEL_NAME = "Invalid timestamp level name"
+	__ERROR_STR_BACKEND               = "Backend must be defined"
 )
 
+// logsCore is shared by Logs and all its named children. Logger is
+// swapped atomically, so records never wait for format switching.
+type logsCore struct {
+	mutex   *sync.RWMutex
+	logger  atomic.Pointer[loggerRef]
+	levels  *namedLevels
+	sampler atomic.Pointer[sampler]
+}
+
+// loggerRef holds published logger. Records are written under read lock, so
+// replaced logger is closed after records which have already loaded it.
+type loggerRef struct {
+	logger   logger
+	mutex    *sync.RWMutex
+	isClosed bool
+}
+
+// newLogsCore 
+func newLogsCore(l logger, levels *namedLevels) *logsCore {
+	c := &logsCore{
+		mutex:  &sync.RWMutex{},
+		levels: levels,
+	}
+	c.store(l)
+
+	return c
+}
+
+// load 
+func (c *logsCore) load() logger {
+	return c.logger.Load().logger
+}
+
+// store 
+func (c *logsCore) store(l logger) {
+	c.logger.Store(&loggerRef{logger: l, mutex: &sync.RWMutex{}})
+}
+
+// set changes settings of current logger under read lock of core, so they
+// are not lost by logger which is replaced at the same time
+func (c *logsCore) set(fn func(l logger)) {
+	c.mutex.RLock()
+	fn(c.load())
+	c.mutex.RUnlock()
+}
+
+// acquire returns read locked reference of current logger, reference of
+// replaced logger is skipped
+func (c *logsCore) acquire() *loggerRef {
+	for {
+		r := c.logger.Load()
+		r.mutex.RLock()
+		if !r.isClosed {
+			return r
+		}
+		r.mutex.RUnlock()
+	}
+}
+
+// write writes entry by current logger
+func (c *logsCore) write(e *Entry) {
+	r := c.acquire()
+	defer r.mutex.RUnlock()
+
+	r.logger.write(e)
+}
+
+// replace publishes logger, old one is closed when its writes are done. It
+// is called under mutex.
+func (c *logsCore) replace(l logger) error {
+	old := c.logger.Swap(&loggerRef{logger: l, mutex: &sync.RWMutex{}})
+	old.mutex.Lock()
+	old.isClosed = true
+	old.mutex.Unlock()
+
+	return old.logger.Close()
+}
+
+// allow 
+func (c *logsCore) allow(l int, m string) bool {
+	sm := c.sampler.Load()
+
+	return sm == nil || sm.allow(l, m)
+}
+
+// summary writes counts of records dropped by sampler
+func (c *logsCore) summary(sm *sampler) {
+	e := newEntry(PRINT_LEVEL, EMPTY_STRING, SAMPLING_SUMMARY_MESSAGE, nil)
+	e.Fields, e.Level = sm.drain(e.Fields)
+	if len(e.Fields) > 0 {
+		c.write(e)
+	}
+	e.free()
+}
+
+// swapSampler publishes new sampler, old one is stopped and its summary is
+// written. It is called under mutex.
+func (c *logsCore) swapSampler(sm *sampler) {
+	if old := c.sampler.Swap(sm); old != nil {
+		old.close()
+		c.summary(old)
+	}
+	if sm != nil {
+		sm.run(c.summary)
+	}
+}
+
 // Logs 
 type Logs struct {
-	logger logger
+	core  *logsCore
+	name  string
+	trace TraceContext
 }
 
 // syncOE 
@@ -334,6 +466,18 @@ func defaultFormatterKeys(f *Formatter) {
 	if f.Keys.Names.Tag == EMPTY_STRING {
 		f.Keys.Names.Tag = keys[6]
 	}
+	if f.Keys.Names.Logger == EMPTY_STRING {
+		f.Keys.Names.Logger = keys[7]
+	}
+	if f.Keys.Names.TraceID == EMPTY_STRING {
+		f.Keys.Names.TraceID = keys[8]
+	}
+	if f.Keys.Names.SpanID == EMPTY_STRING {
+		f.Keys.Names.SpanID = keys[9]
+	}
+	if f.Keys.Names.TraceFlags == EMPTY_STRING {
+		f.Keys.Names.TraceFlags = keys[10]
+	}
 }
 
 // defaultFormatter 
@@ -368,8 +512,8 @@ func defaultFormatter(f *Formatter, outIsPrintable bool, errIsPrintable bool) {
 	defaultFormatterKeys(f)
 }
 
-// initLogger 
-func initLogger(format int) (logger, error) {
+// initLogger creates logger of format by Formatter
+func initLogger(format int, f *Formatter) (logger, error) {
 	var (
 		l logger
 		err error
@@ -377,17 +521,19 @@ func initLogger(format int) (logger, error) {
 
 	switch format {
 	case TEXT_FORMAT:
-		l, err = NewText(nil)
+		l, err = NewText(nil, f)
 	case JSON_FORMAT:
-		l, err = NewJSON(nil)
+		l, err = NewJSON(nil, f)
 	case FMT_FORMAT:
-		l, err = NewFMT(nil)
+		l, err = NewFMT(nil, f)
 	case GELF_FORMAT:
-		l, err = NewGELF(nil)
+		l, err = NewGELF(nil, f)
 	case SYS_FORMAT:
-		l, err = NewSys(nil)
+		l, err = NewSys(nil, f)
+	case FLUENT_FORMAT:
+		l, err = NewFluent(nil, f)
 	default:
-		l = nil
+		err = errors.New(__ERROR_STR_FORMAT_BUILD + ": " + StrInt(format))
 	}
 
 	return l, err
@@ -431,38 +577,74 @@ if e == nil {
 }
 g.Close()
 	if err == nil {
-		return &Logs{
-			newLog,
-		}, nil
-	} else {
-		return nil, err
+		var sm *sampler
+
+		levels := newNamedLevels()
+		if len(f) > 0 && f[0] != nil && f[0].NamedLevels != EMPTY_STRING {
+			err = levels.parse(f[0].NamedLevels)
+		}
+		if err == nil && len(f) > 0 && f[0] != nil {
+			sm, err = newSampler(f[0].Sampling)
+		}
+		if err == nil {
+			core := newLogsCore(newLog, levels)
+			core.swapSampler(sm)
+
+			return &Logs{
+				core: core,
+			}, nil
+		}
+	}
+
+	return nil, err
+}
+
+// SetBackend replaces logger by backend with its own settings, e.g. by *Slog,
+// named children share it. Old logger is closed.
+func (ls *Logs) SetBackend(b Backend) error {
+	if b == nil {
+		return errors.New(__ERROR_STR_BACKEND)
 	}
+
+	ls.core.mutex.Lock()
+	defer ls.core.mutex.Unlock()
+
+	if logger(b) == ls.core.load() {
+		return nil
+	}
+
+	return ls.core.replace(b)
 }
 
-// switchFormat 
-func (ls *Logs) switchFormat(f int) {
-	if f != ls.logger.Format() {
-		oldLogger := ls.logger
-		err := ls.logger.Close()
+// switchFormat creates logger of new format by copy of Formatter of the
+// current one, publishes it and closes old logger when its writes are done.
+// Key prefix and standard outputs are defaults of new format.
+func (ls *Logs) switchFormat(f int) error {
+	var err error
+
+	ls.core.mutex.Lock()
+	defer ls.core.mutex.Unlock()
+
+	oldLogger := ls.core.load()
+	if f != oldLogger.Format() {
+		var newLogger logger
+
+		format := cloneFormatter(oldLogger.formatter())
+		format.Stdout, format.Stderr = nil, nil
+		if format.Keys != nil {
+			format.Keys.Prefix, format.Keys.PrefixSeparator = EMPTY_STRING, EMPTY_STRING
+		}
 
+		newLogger, err = initLogger(f, format)
 		if err == nil {
-			ls.logger, err = initLogger(f)
-			ls.logger.SetLevel(oldLogger.Level())
-			ls.logger.SetLabels(oldLogger.Labels())
-			ls.logger.SetLabelsSeparator(oldLogger.LabelsSeparator())
-			ls.logger.SetEnvironment(oldLogger.Environment())
-			ls.logger.SetTag(oldLogger.Tag())
-			ls.logger.SetTimeFormat(oldLogger.TimeFormat())
-			ls.logger.SetTimeUTC(oldLogger.IsTimeUTC())
-			ls.logger.SetTimeStamp(oldLogger.IsTimeStamp())
-			ls.logger.SetTimeStampLevel(oldLogger.TimeStampLevel())
-
-			oldLogger.Close()
-		} else {
+			err = ls.core.replace(newLogger)
+		}
+		if err != nil {
 			ls.Error(err)
 		}
-		oldLogger = nil
 	}
+
+	return err
 }
 
 // Formats 
@@ -472,7 +654,7 @@ func (ls *Logs) Formats() []int {
 
 // Format 
 func (ls *Logs) Format() int {
-	return ls.logger.Format()
+	return ls.core.load().Format()
 }
 
 // IsFormat 
@@ -485,7 +667,7 @@ func (ls *Logs) SetFormat(f int) error {
 	var err error
 
 	if ls.IsFormat(f) {
-		ls.switchFormat(f)
+		err = ls.switchFormat(f)
 	} else {
 		err = errors.New(__ERROR_STR_FORMAT)
 		ls.Errorv(err, Vars{
@@ -503,7 +685,7 @@ func (ls *Logs) FormatNames() []string {
 
 // FormatName 
 func (ls *Logs) FormatName() string {
-	return ls.logger.FormatName()
+	return ls.core.load().FormatName()
 }
 
 // IsFormatName 
@@ -517,7 +699,7 @@ func (ls *Logs) SetFormatName(f string) error {
 
 	f = strings.TrimSpace(f)
 	if ls.IsFormatName(f) {
-		ls.switchFormat(sliceIndex(formatNames, f))
+		err = ls.switchFormat(sliceIndex(formatNames, strings.ToLower(f)))
 	} else {
 		err = errors.New(__ERROR_STR_FORMAT_NAME)
 		ls.Errorv(err, Vars{
@@ -533,9 +715,13 @@ func (ls *Logs) Levels() []int {
 	return Levels()
 }
 
-// Level 
+// Level returns named level override or logger level
 func (ls *Logs) Level() int {
-	return ls.logger.Level()
+	if l, ok := ls.namedLevel(); ok {
+		return l
+	}
+
+	return ls.core.load().Level()
 }
 
 // IsLevel 
@@ -548,7 +734,13 @@ func (ls *Logs) SetLevel(l int) error {
 	var err error
 
 	if ls.IsLevel(l) {
-		ls.logger.SetLevel(l)
+		if ls.name == EMPTY_STRING {
+			ls.core.set(func(lg logger) {
+				lg.SetLevel(l)
+			})
+		} else {
+			ls.core.levels.set(ls.name, l)
+		}
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL)
 		ls.Errorv(err, Vars{
@@ -566,7 +758,11 @@ func (ls *Logs) LevelNames() []string {
 
 // LevelName 
 func (ls *Logs) LevelName() string {
-	return ls.logger.LevelName()
+	if l, ok := ls.namedLevel(); ok {
+		return levelNames[l]
+	}
+
+	return ls.core.load().LevelName()
 }
 
 // IsLevelName 
@@ -580,7 +776,13 @@ func (ls *Logs) SetLevelName(l string) error {
 
 	l = strings.TrimSpace(l)
 	if ls.IsLevelName(l) {
-		ls.logger.SetLevelName(l)
+		if ls.name == EMPTY_STRING {
+			ls.core.set(func(lg logger) {
+				lg.SetLevelName(l)
+			})
+		} else {
+			ls.core.levels.set(ls.name, sliceIndex(levelNames, strings.ToLower(l)))
+		}
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL_NAME)
 		ls.Errorv(err, Vars{
@@ -591,74 +793,235 @@ func (ls *Logs) SetLevelName(l string) error {
 	return err
 }
 
+// Name 
+func (ls *Logs) Name() string {
+	return ls.name
+}
+
+// Named returns child logger, its records are marked by dotted name
+func (ls *Logs) Named(n string) *Logs {
+	n = strings.TrimSpace(n)
+	if err := namedCheck(n); err != nil {
+		ls.Errorv(err, Vars{
+			KEY_VALUE: n,
+			KEY_NAME: NAME})
+
+		return ls
+	}
+
+	return &Logs{
+		core:  ls.core,
+		name:  namedJoin(ls.name, n),
+		trace: ls.trace,
+	}
+}
+
+// namedLevel 
+func (ls *Logs) namedLevel() (int, bool) {
+	if ls.name == EMPTY_STRING {
+		return 0, false
+	}
+
+	return ls.core.levels.level(ls.name)
+}
+
+// NamedLevels 
+func (ls *Logs) NamedLevels() string {
+	return ls.core.levels.String()
+}
+
+// SetNamedLevels replaces all levels by name prefix, e.g. "storage=debug,http=warn"
+func (ls *Logs) SetNamedLevels(s string) error {
+	err := ls.core.levels.parse(s)
+
+	if err != nil {
+		ls.Errorv(err, Vars{
+			KEY_VALUE: s,
+			KEY_NAME: NAME})
+	}
+
+	return err
+}
+
+// UnsetLevel drops named level override, so parent prefix or logger level is used
+func (ls *Logs) UnsetLevel() {
+	if ls.name != EMPTY_STRING {
+		ls.core.levels.unset(ls.name)
+	}
+}
+
+// Sampling returns copy of sampling settings or nil
+func (ls *Logs) Sampling() *SamplingSettings {
+	if sm := ls.core.sampler.Load(); sm != nil {
+		s := sm.settings
+		return &s
+	}
+
+	return nil
+}
+
+// SetSampling replaces sampling of logger and all named children, nil disables it
+func (ls *Logs) SetSampling(s *SamplingSettings) error {
+	sm, err := newSampler(s)
+
+	if err == nil {
+		ls.core.mutex.Lock()
+		ls.core.swapSampler(sm)
+		ls.core.mutex.Unlock()
+	} else {
+		ls.Errorv(err, Vars{
+			KEY_VALUE: s,
+			KEY_NAME: NAME})
+	}
+
+	return err
+}
+
+// isEnabled 
+func (ls *Logs) isEnabled(l int) bool {
+	return l >= PRINT_LEVEL || ls.Level() >= l
+}
+
+// log 
+func (ls *Logs) log(l int, m string, v Vars) {
+	if ls.isEnabled(l) && ls.core.allow(l, m) {
+		e := newEntry(l, ls.name, m, v)
+		e.Trace = ls.trace
+		ls.core.write(e)
+		e.free()
+	}
+}
+
+// logw copies fields into pooled entry, so variadic fields stay on stack
+func (ls *Logs) logw(l int, m string, f []Field) {
+	if ls.isEnabled(l) && ls.core.allow(l, m) {
+		e := newEntry(l, ls.name, m, nil)
+		e.Trace = ls.trace
+		e.Fields = append(e.Fields, f...)
+		ls.core.write(e)
+		e.free()
+	}
+}
+
+// logf samples records by format string
+func (ls *Logs) logf(l int, s string, i ...interface{}) {
+	if ls.isEnabled(l) && ls.core.allow(l, s) {
+		r := ls.core.acquire()
+		defer r.mutex.RUnlock()
+
+		logger := r.logger
+		if p, ok := logger.(sprinter); ok {
+			s = p.sprintf(l, s, i...)
+		} else {
+			s = fmt.Sprintf(s, i...)
+		}
+		e := newEntry(l, ls.name, s, nil)
+		e.Trace = ls.trace
+		logger.write(e)
+		e.free()
+	}
+}
+
+// logln samples records by message which is formatted without styles of
+// logger
+func (ls *Logs) logln(l int, i ...interface{}) {
+	if ls.isEnabled(l) {
+		if s := sprintln(i...); ls.core.allow(l, s) {
+			r := ls.core.acquire()
+			defer r.mutex.RUnlock()
+
+			logger := r.logger
+			if p, ok := logger.(sprinter); ok {
+				s = p.sprintln(l, i...)
+			}
+			e := newEntry(l, ls.name, s, nil)
+			e.Trace = ls.trace
+			logger.write(e)
+			e.free()
+		}
+	}
+}
+
 // Labels 
 func (ls *Logs) Labels() string {
-	return ls.logger.Labels()
+	return ls.core.load().Labels()
 }
 
 // SetLabels 
 func (ls *Logs) SetLabels(l string) {
-	ls.logger.SetLabels(l)
+	ls.core.set(func(lg logger) {
+		lg.SetLabels(l)
+	})
 }
 
 // LabelsSeparator 
 func (ls *Logs) LabelsSeparator() string {
-	return ls.logger.LabelsSeparator()
+	return ls.core.load().LabelsSeparator()
 }
 
 // SetLabelsSeparator 
 func (ls *Logs) SetLabelsSeparator(l string) {
-	ls.logger.SetLabelsSeparator(l)
+	ls.core.set(func(lg logger) {
+		lg.SetLabelsSeparator(l)
+	})
 }
 
 // LabelsToString 
 func (ls *Logs) LabelsToString(l []string) string {
-	return ls.logger.LabelsToString(l)
+	return ls.core.load().LabelsToString(l)
 }
 
 // LabelsToSlice 
 func (ls *Logs) LabelsToSlice(l string) []string {
-	return ls.logger.LabelsToSlice(l)
+	return ls.core.load().LabelsToSlice(l)
 }
 
 // Environment 
 func (ls *Logs) Environment() string {
-	return ls.logger.Environment()
+	return ls.core.load().Environment()
 }
 
 // SetEnvironment 
 func (ls *Logs) SetEnvironment(s string) {
-	ls.logger.SetEnvironment(s)
+	ls.core.set(func(lg logger) {
+		lg.SetEnvironment(s)
+	})
 }
 
 // Tag 
 func (ls *Logs) Tag() string {
-	return ls.logger.Tag()
+	return ls.core.load().Tag()
 }
 
 // SetTag 
 func (ls *Logs) SetTag(t string) {
-	ls.logger.SetTag(t)
+	ls.core.set(func(lg logger) {
+		lg.SetTag(t)
+	})
 }
 
 // IsTimeUTC 
 func (ls *Logs) IsTimeUTC() bool {
-	return ls.logger.IsTimeUTC()
+	return ls.core.load().IsTimeUTC()
 }
 
 // SetTimeUTC 
 func (ls *Logs) SetTimeUTC(u bool) {
-	ls.logger.SetTimeUTC(u)
+	ls.core.set(func(lg logger) {
+		lg.SetTimeUTC(u)
+	})
 }
 
 // IsTimeStamp 
 func (ls *Logs) IsTimeStamp() bool {
-	return ls.logger.IsTimeStamp()
+	return ls.core.load().IsTimeStamp()
 }
 
 // SetTimeStamp 
 func (ls *Logs) SetTimeStamp(t bool) {
-	ls.logger.SetTimeStamp(t)
+	ls.core.set(func(lg logger) {
+		lg.SetTimeStamp(t)
+	})
 }
 
 // TimeStampLevels 
@@ -668,7 +1031,7 @@ func (ls *Logs) TimeStampLevels() []int {
 
 // TimeStampLevel 
 func (ls *Logs) TimeStampLevel() int {
-	return ls.logger.TimeStampLevel()
+	return ls.core.load().TimeStampLevel()
 }
 
 // IsTimeStampLevel 
@@ -681,7 +1044,9 @@ func (ls *Logs) SetTimeStampLevel(l int) error {
 	var err error
 
 	if ls.IsTimeStampLevel(l) {
-		ls.logger.SetTimeStampLevel(l)
+		ls.core.set(func(lg logger) {
+			lg.SetTimeStampLevel(l)
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
 		ls.Errorv(err, Vars{
@@ -699,7 +1064,7 @@ func (ls *Logs) TimeStampLevelNames() []string {
 
 // TimeStampLevelName 
 func (ls *Logs) TimeStampLevelName() string {
-	return ls.logger.TimeStampLevelName()
+	return ls.core.load().TimeStampLevelName()
 }
 
 // IsTimeStampLevelName 
@@ -713,7 +1078,9 @@ func (ls *Logs) SetTimeStampLevelName(l string) error {
 
 	l = strings.TrimSpace(l)
 	if ls.IsTimeStampLevelName(l) {
-		ls.logger.SetTimeStampLevelName(l)
+		ls.core.set(func(lg logger) {
+			lg.SetTimeStampLevelName(l)
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
 		ls.Errorv(err, Vars{
@@ -726,184 +1093,225 @@ func (ls *Logs) SetTimeStampLevelName(l string) error {
 
 // TimeFormat 
 func (ls *Logs) TimeFormat() string {
-	return ls.logger.TimeFormat()
+	return ls.core.load().TimeFormat()
 }
 
 // SetTimeFormat 
 func (ls *Logs) SetTimeFormat(f string) {
-	ls.logger.SetTimeFormat(f)
+	ls.core.set(func(lg logger) {
+		lg.SetTimeFormat(f)
+	})
 }
 
 // Panic 
 func (ls *Logs) Panic(e error) {
-	ls.logger.Panic(e)
+	ls.log(PANIC_LEVEL, e.Error(), nil)
 }
 
 // Panicv 
 func (ls *Logs) Panicv(e error, v Vars) {
-	ls.logger.Panicv(e, v)
+	ls.log(PANIC_LEVEL, e.Error(), v)
 }
 
 // Panicf 
 func (ls *Logs) Panicf(e error, i ...interface{}) {
-	ls.logger.Panicf(e, i...)
+	ls.logf(PANIC_LEVEL, e.Error(), i...)
 }
 
 // Panicln 
 func (ls *Logs) Panicln(i ...interface{}) {
-	ls.logger.Panicln(i...)
+	ls.logln(PANIC_LEVEL, i...)
+}
+
+// Panicw 
+func (ls *Logs) Panicw(e error, f ...Field) {
+	ls.logw(PANIC_LEVEL, e.Error(), f)
 }
 
 // Fatal 
 func (ls *Logs) Fatal(e error) {
-	ls.logger.Fatal(e)
+	ls.log(FATAL_LEVEL, e.Error(), nil)
 }
 
 // Fatalv 
 func (ls *Logs) Fatalv(e error, v Vars) {
-	ls.logger.Fatalv(e, v)
+	ls.log(FATAL_LEVEL, e.Error(), v)
 }
 
 // Fatalf 
 func (ls *Logs) Fatalf(e error, i ...interface{}) {
-	ls.logger.Fatalf(e, i...)
+	ls.logf(FATAL_LEVEL, e.Error(), i...)
 }
 
 // Fatalln 
 func (ls *Logs) Fatalln(i ...interface{}) {
-	ls.logger.Fatalln(i...)
+	ls.logln(FATAL_LEVEL, i...)
+}
+
+// Fatalw 
+func (ls *Logs) Fatalw(e error, f ...Field) {
+	ls.logw(FATAL_LEVEL, e.Error(), f)
 }
 
 // Error 
 func (ls *Logs) Error(e error) {
-	ls.logger.Error(e)
+	ls.log(ERROR_LEVEL, e.Error(), nil)
 }
 
 // Errorv 
 func (ls *Logs) Errorv(e error, v Vars) {
-	ls.logger.Errorv(e, v)
+	ls.log(ERROR_LEVEL, e.Error(), v)
 }
 
 // Errorf 
 func (ls *Logs) Errorf(e error, i ...interface{}) {
-	ls.logger.Errorf(e, i...)
+	ls.logf(ERROR_LEVEL, e.Error(), i...)
 }
 
 // Errorln 
 func (ls *Logs) Errorln(i ...interface{}) {
-	ls.logger.Errorln(i...)
+	ls.logln(ERROR_LEVEL, i...)
+}
+
+// Errorw 
+func (ls *Logs) Errorw(e error, f ...Field) {
+	ls.logw(ERROR_LEVEL, e.Error(), f)
 }
 
 // Warn 
 func (ls *Logs) Warn(s string) {
-	ls.logger.Warn(s)
+	ls.log(WARN_LEVEL, s, nil)
 }
 
 // Warnv 
 func (ls *Logs) Warnv(s string, v Vars) {
-	ls.logger.Warnv(s, v)
+	ls.log(WARN_LEVEL, s, v)
 }
 
 // Warnf 
 func (ls *Logs) Warnf(s string, i ...interface{}) {
-	ls.logger.Warnf(s, i...)
+	ls.logf(WARN_LEVEL, s, i...)
 }
 
 // Warnln 
 func (ls *Logs) Warnln(i ...interface{}) {
-	ls.logger.Warnln(i...)
+	ls.logln(WARN_LEVEL, i...)
+}
+
+// Warnw 
+func (ls *Logs) Warnw(s string, f ...Field) {
+	ls.logw(WARN_LEVEL, s, f)
 }
 
 // Info 
 func (ls *Logs) Info(s string) {
-	ls.logger.Info(s)
+	ls.log(INFO_LEVEL, s, nil)
 }
 
 // Infov 
 func (ls *Logs) Infov(s string, v Vars) {
-	ls.logger.Infov(s, v)
+	ls.log(INFO_LEVEL, s, v)
 }
 
 // Infof 
 func (ls *Logs) Infof(s string, i ...interface{}) {
-	ls.logger.Infof(s, i...)
+	ls.logf(INFO_LEVEL, s, i...)
 }
 
 // Infoln 
 func (ls *Logs) Infoln(i ...interface{}) {
-	ls.logger.Infoln(i...)
+	ls.logln(INFO_LEVEL, i...)
+}
+
+// Infow 
+func (ls *Logs) Infow(s string, f ...Field) {
+	ls.logw(INFO_LEVEL, s, f)
 }
 
 // Debug
 func (ls *Logs) Debug(s string) {
-	ls.logger.Debug(s)
+	ls.log(DEBUG_LEVEL, s, nil)
 }
 
 // Debugv 
 func (ls *Logs) Debugv(s string, v Vars) {
-	ls.logger.Debugv(s, v)
+	ls.log(DEBUG_LEVEL, s, v)
 }
 
 // Debugf 
 func (ls *Logs) Debugf(s string, i ...interface{}) {
-	ls.logger.Debugf(s, i...)
+	ls.logf(DEBUG_LEVEL, s, i...)
 }
 
 // Debugln 
 func (ls *Logs) Debugln(i ...interface{}) {
-	ls.logger.Debugln(i...)
+	ls.logln(DEBUG_LEVEL, i...)
+}
+
+// Debugw 
+func (ls *Logs) Debugw(s string, f ...Field) {
+	ls.logw(DEBUG_LEVEL, s, f)
 }
 
 // Trace 
 func (ls *Logs) Trace(s string) {
-	ls.logger.Trace(s)
+	ls.log(TRACE_LEVEL, s, nil)
 }
 
 // Tracev 
 func (ls *Logs) Tracev(s string, v Vars) {
-	ls.logger.Tracev(s, v)
+	ls.log(TRACE_LEVEL, s, v)
 }
 
 // Tracef 
 func (ls *Logs) Tracef(s string, i ...interface{}) {
-	ls.logger.Tracef(s, i...)
+	ls.logf(TRACE_LEVEL, s, i...)
 }
 
 // Traceln 
 func (ls *Logs) Traceln(i ...interface{}) {
-	ls.logger.Traceln(i...)
+	ls.logln(TRACE_LEVEL, i...)
+}
+
+// Tracew 
+func (ls *Logs) Tracew(s string, f ...Field) {
+	ls.logw(TRACE_LEVEL, s, f)
 }
 
 // Print 
 func (ls *Logs) Print(s string) {
-	ls.logger.Print(s)
+	ls.log(PRINT_LEVEL, s, nil)
 }
 
 // Printv 
 func (ls *Logs) Printv(s string, v Vars) {
-	ls.logger.Printv(s, v)
+	ls.log(PRINT_LEVEL, s, v)
 }
 
 // Printf 
 func (ls *Logs) Printf(s string, i ...interface{}) {
-	ls.logger.Printf(s, i ...)
+	ls.logf(PRINT_LEVEL, s, i...)
 }
 
 // Println 
 func (ls *Logs) Println(i ...interface{}) {
-	ls.logger.Println(i...)
+	ls.logln(PRINT_LEVEL, i...)
+}
+
+// Printw 
+func (ls *Logs) Printw(s string, f ...Field) {
+	ls.logw(PRINT_LEVEL, s, f)
 }
 
-// Close 
+// Close closes logger, named children leave shared logger open
 func (ls *Logs) Close() error {
 	var err error
 
-	if ls != nil {
-		if ls.logger != nil {
-			err = ls.logger.Close()
-			ls.logger = nil
-		}
-		ls = nil
+	if ls != nil && ls.name == EMPTY_STRING {
+		ls.core.mutex.Lock()
+		ls.core.swapSampler(nil)
+		err = ls.core.load().Close()
+		ls.core.mutex.Unlock()
 	}
 
 	return err
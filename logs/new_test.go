@@ -1,2 +1,50 @@
 package logs
 
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetFormatName(t *testing.T) {
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL}, io.Discard, false))
+
+	for _, name := range []string{OTLP_NAME, SLOG_NAME, HOOK_NAME, "awslogs", "unknown"} {
+		if err := ls.SetFormatName(name); err == nil {
+			t.Errorf("format %s is set by name", name)
+		}
+		if ls.Format() != TEXT_FORMAT {
+			t.Errorf("format %s: logger is replaced", name)
+		}
+	}
+	if err := ls.SetFormatName("Fluent"); err != nil || ls.Format() != FLUENT_FORMAT {
+		t.Errorf("unexpected format %d: %v", ls.Format(), err)
+	}
+	if err := ls.SetFormat(OTLP_FORMAT); err == nil {
+		t.Error("OTLP format is set")
+	}
+}
+
+func TestSetFormatSettings(t *testing.T) {
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL}, io.Discard, false))
+	isDone := &atomic.Bool{}
+	wg := &sync.WaitGroup{}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; !isDone.Load(); i++ {
+			ls.SetFormat([...]int{FMT_FORMAT, TEXT_FORMAT}[i % 2])
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		ls.SetTag(StrInt(i))
+		if tag := ls.Tag(); tag != StrInt(i) {
+			t.Errorf("tag %s is set by replaced logger, tag is %q", StrInt(i), tag)
+			break
+		}
+	}
+	isDone.Store(true)
+	wg.Wait()
+}
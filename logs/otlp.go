@@ -0,0 +1,1246 @@
+package logs
+
+import (
+	"io"
+	"os"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"net/url"
+	"net/http"
+	"compress/gzip"
+	"encoding/binary"
+)
+
+// OTLP_NAME 
+const OTLP_NAME = "otlp"
+
+// Protocols of OTLP/HTTP
+const (
+	OTLP_PROTOCOL_PROTOBUF = "http/protobuf"
+	OTLP_PROTOCOL_JSON     = "http/json"
+)
+
+// Defaults of OTLP exporter, durations are in milliseconds
+const (
+	OTLP_DEFAULT_URL            = "http://localhost:4318/v1/logs"
+	OTLP_DEFAULT_BATCH_SIZE     = int(512)
+	OTLP_DEFAULT_BATCH_INTERVAL = int(1000)
+	OTLP_DEFAULT_BUFFER_LIMIT   = int(2048)
+	OTLP_DEFAULT_TIMEOUT        = int(10000)
+	OTLP_DEFAULT_RETRY_WAIT     = int(500)
+	OTLP_DEFAULT_MAX_RETRY      = int(5)
+	OTLP_DEFAULT_MAX_RETRY_WAIT = int(30000)
+	OTLP_MAX_RETRY_NO           = int(-1)
+)
+
+// Infinities of double values of OTLP/JSON by proto3 JSON mapping
+const (
+	OTLP_JSON_INF_P = `"Infinity"`
+	OTLP_JSON_INF_N = `"-Infinity"`
+)
+
+// Resource and log record attributes by semantic conventions of OpenTelemetry
+const (
+	OTLP_KEY_SERVICE_NAME      = "service.name"
+	OTLP_KEY_ENVIRONMENT       = "deployment.environment.name"
+	OTLP_KEY_HOST_NAME         = "host.name"
+	OTLP_KEY_EXCEPTION_TYPE    = "exception.type"
+	OTLP_KEY_EXCEPTION_MESSAGE = "exception.message"
+	OTLP_KEY_EXCEPTION_STACK   = "exception.stacktrace"
+)
+
+// Content types and headers of OTLP/HTTP
+const (
+	OTLP_CONTENT_TYPE_PROTOBUF = "application/x-protobuf"
+	OTLP_CONTENT_TYPE_JSON     = "application/json"
+	OTLP_ENCODING_GZIP         = "gzip"
+)
+
+// Kinds of AnyValue
+const (
+	OTLP_VALUE_STRING = int(1)
+	OTLP_VALUE_BOOL   = int(2)
+	OTLP_VALUE_INT    = int(3)
+	OTLP_VALUE_DOUBLE = int(4)
+	OTLP_VALUE_KVLIST = int(6)
+)
+
+// Wire types of protobuf
+const (
+	PROTO_WIRE_VARINT  = int(0)
+	PROTO_WIRE_FIXED64 = int(1)
+	PROTO_WIRE_BYTES   = int(2)
+	PROTO_WIRE_FIXED32 = int(5)
+)
+
+// Error string messages
+const (
+	__ERROR_STR_OTLP_URL      = "Invalid OTLP endpoint"
+	__ERROR_STR_OTLP_PROTOCOL = "Invalid OTLP protocol"
+	__ERROR_STR_OTLP_SETTINGS = "Invalid OTLP settings"
+	__ERROR_STR_OTLP_EXPORT   = "Can not export logs to OTLP endpoint"
+	__ERROR_STR_OTLP_DROPPED  = "OTLP queue is full, records are dropped"
+)
+
+// OTLPSettings configures exporter, Connection.URL is endpoint of logs,
+// Connection.Timeout, RetryWait, MaxRetryWait are in milliseconds, MaxRetry
+// limits retries of failed export, OTLP_MAX_RETRY_NO disables them, and
+// BufferLimit limits records waiting for
+// export. BatchInterval in milliseconds is the longest wait of record.
+type OTLPSettings struct {
+	Connection    *Connection       `json:"connection" yaml:"connection" xml:"connection" toml:"connection"`
+	Protocol      string            `json:"protocol" yaml:"protocol" xml:"protocol" toml:"protocol"`
+	Headers       map[string]string `json:"headers" yaml:"headers" xml:"headers" toml:"headers"`
+	IsGzip        bool              `json:"is_gzip" yaml:"is_gzip" xml:"is_gzip" toml:"is_gzip"`
+	BatchSize     int               `json:"batch_size" yaml:"batch_size" xml:"batch_size" toml:"batch_size"`
+	BatchInterval int               `json:"batch_interval" yaml:"batch_interval" xml:"batch_interval" toml:"batch_interval"`
+	Hostname      string            `json:"hostname" yaml:"hostname" xml:"hostname" toml:"hostname"`
+}
+
+// OTLP exports records as OpenTelemetry logs by OTLP/HTTP. Records are
+// batched and sent by background exporter.
+type OTLP struct {
+	format   *formatState
+	settings *OTLPSettings
+	client   *http.Client
+	mutex    sync.Mutex
+	records  []otlpRecord
+	batches  chan []otlpRecord
+	done     chan struct{}
+	isClosed bool
+}
+
+// otlpKeyValue is attribute of resource or log record
+type otlpKeyValue struct {
+	key   string
+	value otlpValue
+}
+
+// otlpValue is AnyValue of OpenTelemetry, objects are key value lists
+type otlpValue struct {
+	kind    int
+	str     string
+	integer int64
+	double  float64
+	values  []otlpKeyValue
+}
+
+// otlpRecord is LogRecord of OpenTelemetry, scope is name of logger and
+// trace context correlates record with span
+type otlpRecord struct {
+	scope      string
+	time       int64
+	observed   int64
+	severity   int
+	body       string
+	attributes []otlpKeyValue
+	trace      TraceContext
+}
+
+// otlpSeverities are severity numbers of levels, print level is unspecified
+var otlpSeverities = []int{24, 21, 17, 13, 9, 5, 1, 0}
+
+// otlpScope is name of instrumentation scope of records without logger name
+var otlpScope = strings.TrimSuffix(callerPrefix, DOT_STRING)
+
+// otlpSettingsCheck sets defaults of settings and checks them
+func otlpSettingsCheck(s *OTLPSettings) error {
+	if s.Connection == nil {
+		s.Connection = &Connection{}
+	}
+	c := s.Connection
+	if c.URL == EMPTY_STRING {
+		c.URL = OTLP_DEFAULT_URL
+	}
+	if s.Protocol == EMPTY_STRING {
+		s.Protocol = OTLP_PROTOCOL_PROTOBUF
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || !IsHTTP(c.URL) || u.Host == EMPTY_STRING {
+		return errors.New(__ERROR_STR_OTLP_URL + ": " + c.URL)
+	}
+	c.Scheme = u.Scheme
+	c.Address = u.Host
+	c.Host = u.Hostname()
+	if port := u.Port(); port != EMPTY_STRING {
+		c.Port, _ = strconv.Atoi(port)
+	}
+
+	switch s.Protocol {
+	case OTLP_PROTOCOL_PROTOBUF, OTLP_PROTOCOL_JSON:
+	default:
+		return errors.New(__ERROR_STR_OTLP_PROTOCOL + ": " + s.Protocol)
+	}
+
+	for _, value := range []struct {
+		value *int
+		name  string
+		def   int
+		min   int
+	}{
+		{&s.BatchSize, "batch size", OTLP_DEFAULT_BATCH_SIZE, 0},
+		{&s.BatchInterval, "batch interval", OTLP_DEFAULT_BATCH_INTERVAL, 0},
+		{&c.BufferLimit, "buffer limit", OTLP_DEFAULT_BUFFER_LIMIT, 0},
+		{&c.Timeout, "timeout", OTLP_DEFAULT_TIMEOUT, 0},
+		{&c.RetryWait, "retry wait", OTLP_DEFAULT_RETRY_WAIT, 0},
+		{&c.MaxRetry, "max retry", OTLP_DEFAULT_MAX_RETRY, OTLP_MAX_RETRY_NO},
+		{&c.MaxRetryWait, "max retry wait", OTLP_DEFAULT_MAX_RETRY_WAIT, 0},
+	} {
+		if *value.value < value.min {
+			return errors.New(__ERROR_STR_OTLP_SETTINGS + ": " + value.name + " must be a positive integer")
+		}
+		if *value.value == 0 {
+			*value.value = value.def
+		}
+	}
+	if c.BufferLimit < s.BatchSize {
+		c.BufferLimit = s.BatchSize
+	}
+
+	if s.Hostname == EMPTY_STRING {
+		s.Hostname, err = os.Hostname()
+	}
+
+	return err
+}
+
+// NewOTLP 
+func NewOTLP(s *OTLPSettings, f ...*Formatter) (*OTLP, error) {
+	var (
+		format *Formatter
+		settings *OTLPSettings
+	)
+
+	if s != nil {
+		settings = s
+	} else {
+		settings = &OTLPSettings{}
+	}
+
+	if len(f) == 0 {
+		format = &Formatter{}
+	} else {
+		format = f[0]
+	}
+	defaultFormatter(format, false, true)
+
+	err := redactCheck(format.Redact)
+
+	if err == nil {
+		err = otlpSettingsCheck(settings)
+	}
+	if err == nil {
+		o := &OTLP{
+			format:   newFormatState(format),
+			settings: settings,
+			client:   &http.Client{Timeout: time.Duration(settings.Connection.Timeout) * time.Millisecond},
+			batches:  make(chan []otlpRecord, settings.Connection.BufferLimit/settings.BatchSize),
+			done:     make(chan struct{}),
+		}
+		go o.run(time.Duration(settings.BatchInterval) * time.Millisecond)
+		return o, nil
+	} else {
+		if format.Stderr.IsPrintable {
+			format.Stderr.Logger.Print(err.Error())
+		}
+		return nil, err
+	}
+}
+
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (o *OTLP) write(e *Entry) {
+	o.format.write(o, e)
+}
+
+// formatter returns current snapshot of Formatter
+func (o *OTLP) formatter() *Formatter {
+	return o.format.get()
+}
+
+// record converts entry to log record and adds it to batch, full batch is
+// queued for exporter. Panic and fatal records are exported at once, so they
+// are not lost when process ends.
+func (o *OTLP) record(e *Entry) {
+	format := o.format.get()
+	record := o.newRecord(format, e)
+
+	o.mutex.Lock()
+	if o.isClosed {
+		o.mutex.Unlock()
+		return
+	}
+	o.records = append(o.records, record)
+	if len(o.records) >= o.settings.BatchSize {
+		select {
+		case o.batches <- o.records:
+		default:
+			if format.Stderr.IsPrintable {
+				format.Stderr.Logger.Print(__ERROR_STR_OTLP_DROPPED + ": " + strconv.Itoa(len(o.records)))
+			}
+		}
+		o.records = nil
+	}
+	o.mutex.Unlock()
+
+	if e.Level <= FATAL_LEVEL {
+		o.Flush()
+	}
+}
+
+// take removes records waiting in batch
+func (o *OTLP) take() []otlpRecord {
+	o.mutex.Lock()
+	records := o.records
+	o.records = nil
+	o.mutex.Unlock()
+
+	return records
+}
+
+// run exports queued batches and batch waiting longer than interval
+func (o *OTLP) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer close(o.done)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case records, ok := <-o.batches:
+			if !ok {
+				return
+			}
+			o.export(records)
+		case <-ticker.C:
+			if records := o.take(); len(records) > 0 {
+				o.export(records)
+			}
+		}
+	}
+}
+
+// Flush exports waiting records at once
+func (o *OTLP) Flush() error {
+	if records := o.take(); len(records) > 0 {
+		return o.export(records)
+	}
+
+	return nil
+}
+
+// export sends records, failed export is retried with exponential backoff
+// while error is temporary, Retry-After of response replaces backoff
+func (o *OTLP) export(records []otlpRecord) error {
+	format := o.format.get()
+	c := o.settings.Connection
+
+	body, err := o.encode(format, records)
+	wait := time.Duration(c.RetryWait) * time.Millisecond
+	for i := 0; err == nil; i++ {
+		isRetry, after, e := o.post(body)
+		if e == nil || !isRetry || i >= c.MaxRetry {
+			err = e
+			break
+		}
+		if after > 0 {
+			wait = after
+		}
+		if max := time.Duration(c.MaxRetryWait) * time.Millisecond; wait > max {
+			wait = max
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+
+	if err != nil && format.Stderr.IsPrintable {
+		format.Stderr.Logger.Print(err.Error())
+	}
+
+	return err
+}
+
+// post sends request, it tells if failed request can be retried and when
+func (o *OTLP) post(body []byte) (bool, time.Duration, error) {
+	request, err := http.NewRequest(http.MethodPost, o.settings.Connection.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, errors.New(__ERROR_STR_OTLP_EXPORT + ": " + err.Error())
+	}
+	if o.settings.Protocol == OTLP_PROTOCOL_JSON {
+		request.Header.Set("Content-Type", OTLP_CONTENT_TYPE_JSON)
+	} else {
+		request.Header.Set("Content-Type", OTLP_CONTENT_TYPE_PROTOBUF)
+	}
+	if o.settings.IsGzip {
+		request.Header.Set("Content-Encoding", OTLP_ENCODING_GZIP)
+	}
+	for k, v := range o.settings.Headers {
+		request.Header.Set(k, v)
+	}
+
+	response, err := o.client.Do(request)
+	if err != nil {
+		return true, 0, errors.New(__ERROR_STR_OTLP_EXPORT + ": " + err.Error())
+	}
+	io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, 0, nil
+	}
+	err = fmt.Errorf("%s: %s %s", __ERROR_STR_OTLP_EXPORT, o.settings.Connection.URL, response.Status)
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		after, _ := strconv.Atoi(response.Header.Get("Retry-After"))
+		return true, time.Duration(after) * time.Second, err
+	}
+
+	return false, 0, err
+}
+
+// encode encodes request by protocol and compresses it by gzip when set
+func (o *OTLP) encode(format *Formatter, records []otlpRecord) ([]byte, error) {
+	var b []byte
+
+	resource := o.resource(format)
+	if o.settings.Protocol == OTLP_PROTOCOL_JSON {
+		b = appendOTLPJSONRequest(b, resource, records)
+	} else {
+		b = appendOTLPProtoRequest(b, resource, records)
+	}
+	if !o.settings.IsGzip {
+		return b, nil
+	}
+
+	w := &bytes.Buffer{}
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(b); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
+
+// resource returns attributes of resource from tag, environment and hostname
+func (o *OTLP) resource(format *Formatter) []otlpKeyValue {
+	var attributes []otlpKeyValue
+
+	for _, attribute := range []otlpKeyValue{
+		otlpString(OTLP_KEY_SERVICE_NAME, format.Tag),
+		otlpString(OTLP_KEY_ENVIRONMENT, format.Environment),
+		otlpString(OTLP_KEY_HOST_NAME, o.settings.Hostname),
+	} {
+		if attribute.value.str != EMPTY_STRING {
+			attributes = append(attributes, attribute)
+		}
+	}
+
+	return attributes
+}
+
+// newRecord converts entry to log record. Fields and vars are attributes,
+// objects are key value lists. Error under KEY_ERROR is written as
+// exception.* attributes.
+func (o *OTLP) newRecord(format *Formatter, e *Entry) otlpRecord {
+	record := otlpRecord{
+		scope:    e.Name,
+		time:     e.Time.UnixNano(),
+		observed: time.Now().UnixNano(),
+		severity: e.Level,
+		body:     e.Message,
+		trace:    e.Trace,
+	}
+	if record.scope == EMPTY_STRING {
+		record.scope = otlpScope
+	}
+
+	if format.Labels.String != EMPTY_STRING && !isOverwritten(format, e, format.Keys.Names.Labels) {
+		record.attributes = append(record.attributes, otlpString(format.Keys.Names.Labels, format.Labels.String))
+	}
+	if err := ecsError(e); err != nil {
+		s := err.Error()
+		record.attributes = append(record.attributes,
+			otlpString(OTLP_KEY_EXCEPTION_TYPE, fmt.Sprintf("%T", err)),
+			otlpString(OTLP_KEY_EXCEPTION_MESSAGE, s))
+		if stack := fmt.Sprintf("%+v", err); stack != s {
+			record.attributes = append(record.attributes, otlpString(OTLP_KEY_EXCEPTION_STACK, strings.TrimPrefix(strings.TrimPrefix(stack, s), "\n")))
+		}
+	}
+
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() && !isECSError(&f) && isKeptKey(format, f.Key) {
+			record.attributes = append(record.attributes, otlpKeyValue{f.Key, otlpFieldValue(format, &f)})
+		}
+	}
+	buffer := newBuffer()
+	for _, key := range buffer.sortKeys(e.Vars) {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() && !isECSError(&f) && isKeptKey(format, key) {
+			record.attributes = append(record.attributes, otlpKeyValue{key, otlpFieldValue(format, &f)})
+		}
+	}
+	buffer.free()
+
+	return record
+}
+
+// otlpString returns attribute with string value
+func otlpString(k string, v string) otlpKeyValue {
+	return otlpKeyValue{k, otlpValue{kind: OTLP_VALUE_STRING, str: v}}
+}
+
+// otlpFieldValue converts resolved field to AnyValue, values without own
+// kind are strings as text format writes them
+func otlpFieldValue(format *Formatter, f *Field) otlpValue {
+	switch f.Type {
+	case FIELD_TYPE_INT64:
+		return otlpValue{kind: OTLP_VALUE_INT, integer: f.Integer}
+	case FIELD_TYPE_UINT64:
+		if f.Integer >= 0 {
+			return otlpValue{kind: OTLP_VALUE_INT, integer: f.Integer}
+		}
+	case FIELD_TYPE_FLOAT64:
+		return otlpValue{kind: OTLP_VALUE_DOUBLE, double: math.Float64frombits(uint64(f.Integer))}
+	case FIELD_TYPE_BOOL:
+		return otlpValue{kind: OTLP_VALUE_BOOL, integer: f.Integer}
+	case FIELD_TYPE_OBJECT:
+		value := otlpValue{kind: OTLP_VALUE_KVLIST}
+		fields := objectFields(nil, f)
+		for i := 0; i < len(fields); i++ {
+			if !fields[i].isSkipped() {
+				value.values = append(value.values, otlpKeyValue{fields[i].Key, otlpFieldValue(format, &fields[i])})
+			}
+		}
+		return value
+	}
+
+	return otlpValue{kind: OTLP_VALUE_STRING, str: string(appendTextField(nil, format, f))}
+}
+
+// otlpScopes groups records by scope in order of their first record
+func otlpScopes(records []otlpRecord) [][]otlpRecord {
+	var scopes [][]otlpRecord
+
+	for i := 0; i < len(records); i++ {
+		j := 0
+		for j < len(scopes) && scopes[j][0].scope != records[i].scope {
+			j++
+		}
+		if j == len(scopes) {
+			scopes = append(scopes, nil)
+		}
+		scopes[j] = append(scopes[j], records[i])
+	}
+
+	return scopes
+}
+
+// appendProtoTag appends number and wire type of field
+func appendProtoTag(b []byte, n int, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(n<<3|wire))
+}
+
+// appendProtoString appends string field, empty string is default value
+// which is not written
+func appendProtoString(b []byte, n int, s string) []byte {
+	if s == EMPTY_STRING {
+		return b
+	}
+	b = appendProtoTag(b, n, PROTO_WIRE_BYTES)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+
+	return append(b, s...)
+}
+
+// appendProtoBegin appends tag of embedded message, its length is inserted
+// by appendProtoEnd from returned start
+func appendProtoBegin(b []byte, n int) ([]byte, int) {
+	b = appendProtoTag(b, n, PROTO_WIRE_BYTES)
+
+	return b, len(b)
+}
+
+// appendProtoEnd inserts length of embedded message before it
+func appendProtoEnd(b []byte, start int) []byte {
+	var size [binary.MaxVarintLen64]byte
+
+	n := len(b) - start
+	l := binary.PutUvarint(size[:], uint64(n))
+	b = append(b, size[:l]...)
+	copy(b[start+l:], b[start:start+n])
+	copy(b[start:], size[:l])
+
+	return b
+}
+
+// appendOTLPProtoRequest appends ExportLogsServiceRequest with one resource
+func appendOTLPProtoRequest(b []byte, resource []otlpKeyValue, records []otlpRecord) []byte {
+	var resourceLogs, resourceStart, scopeLogs, scopeStart, start int
+
+	b, resourceLogs = appendProtoBegin(b, 1)
+	b, resourceStart = appendProtoBegin(b, 1)
+	for i := 0; i < len(resource); i++ {
+		b = appendOTLPProtoKeyValue(b, 1, &resource[i])
+	}
+	b = appendProtoEnd(b, resourceStart)
+
+	for _, scope := range otlpScopes(records) {
+		b, scopeLogs = appendProtoBegin(b, 2)
+		b, scopeStart = appendProtoBegin(b, 1)
+		b = appendProtoString(b, 1, scope[0].scope)
+		b = appendProtoEnd(b, scopeStart)
+		for i := 0; i < len(scope); i++ {
+			r := &scope[i]
+			b, start = appendProtoBegin(b, 2)
+			b = appendProtoTag(b, 1, PROTO_WIRE_FIXED64)
+			b = binary.LittleEndian.AppendUint64(b, uint64(r.time))
+			if severity := otlpSeverities[r.severity]; severity > 0 {
+				b = appendProtoTag(b, 2, PROTO_WIRE_VARINT)
+				b = binary.AppendUvarint(b, uint64(severity))
+			}
+			b = appendProtoString(b, 3, textLayoutLevels["upper"][r.severity])
+			b = appendOTLPProtoValue(b, 5, &otlpValue{kind: OTLP_VALUE_STRING, str: r.body})
+			for j := 0; j < len(r.attributes); j++ {
+				b = appendOTLPProtoKeyValue(b, 6, &r.attributes[j])
+			}
+			if r.trace.IsValid() {
+				b = appendProtoTag(b, 8, PROTO_WIRE_FIXED32)
+				b = binary.LittleEndian.AppendUint32(b, uint32(r.trace.Flags))
+				b = appendProtoString(b, 9, string(r.trace.TraceID[:]))
+				b = appendProtoString(b, 10, string(r.trace.SpanID[:]))
+			}
+			b = appendProtoTag(b, 11, PROTO_WIRE_FIXED64)
+			b = binary.LittleEndian.AppendUint64(b, uint64(r.observed))
+			b = appendProtoEnd(b, start)
+		}
+		b = appendProtoEnd(b, scopeLogs)
+	}
+
+	return appendProtoEnd(b, resourceLogs)
+}
+
+// appendOTLPProtoKeyValue appends KeyValue as field n
+func appendOTLPProtoKeyValue(b []byte, n int, kv *otlpKeyValue) []byte {
+	var start int
+
+	b, start = appendProtoBegin(b, n)
+	b = appendProtoString(b, 1, kv.key)
+	b = appendOTLPProtoValue(b, 2, &kv.value)
+
+	return appendProtoEnd(b, start)
+}
+
+// appendOTLPProtoValue appends AnyValue as field n, kind is field of value
+func appendOTLPProtoValue(b []byte, n int, v *otlpValue) []byte {
+	var start, list int
+
+	b, start = appendProtoBegin(b, n)
+	switch v.kind {
+	case OTLP_VALUE_STRING:
+		b = appendProtoTag(b, v.kind, PROTO_WIRE_BYTES)
+		b = binary.AppendUvarint(b, uint64(len(v.str)))
+		b = append(b, v.str...)
+	case OTLP_VALUE_BOOL, OTLP_VALUE_INT:
+		b = appendProtoTag(b, v.kind, PROTO_WIRE_VARINT)
+		b = binary.AppendUvarint(b, uint64(v.integer))
+	case OTLP_VALUE_DOUBLE:
+		b = appendProtoTag(b, v.kind, PROTO_WIRE_FIXED64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v.double))
+	case OTLP_VALUE_KVLIST:
+		b, list = appendProtoBegin(b, v.kind)
+		for i := 0; i < len(v.values); i++ {
+			b = appendOTLPProtoKeyValue(b, 1, &v.values[i])
+		}
+		b = appendProtoEnd(b, list)
+	}
+
+	return appendProtoEnd(b, start)
+}
+
+// appendOTLPJSONRequest appends ExportLogsServiceRequest by JSON mapping of
+// protobuf, 64 bit integers are strings and ids are hex
+func appendOTLPJSONRequest(b []byte, resource []otlpKeyValue, records []otlpRecord) []byte {
+	b = append(b, `{"resourceLogs":[{"resource":{"attributes":`...)
+	b = appendOTLPJSONKeyValues(b, resource)
+	b = append(b, `},"scopeLogs":[`...)
+	for i, scope := range otlpScopes(records) {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, `{"scope":{"name":`...)
+		b = appendJSONString(b, scope[0].scope)
+		b = append(b, `},"logRecords":[`...)
+		for j := 0; j < len(scope); j++ {
+			r := &scope[j]
+			if j > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, `{"timeUnixNano":"`...)
+			b = strconv.AppendInt(b, r.time, 10)
+			b = append(b, `","observedTimeUnixNano":"`...)
+			b = strconv.AppendInt(b, r.observed, 10)
+			b = append(b, '"')
+			if severity := otlpSeverities[r.severity]; severity > 0 {
+				b = append(b, `,"severityNumber":`...)
+				b = strconv.AppendInt(b, int64(severity), 10)
+				b = append(b, `,"severityText":`...)
+				b = appendJSONString(b, textLayoutLevels["upper"][r.severity])
+			}
+			b = append(b, `,"body":`...)
+			b = appendOTLPJSONValue(b, &otlpValue{kind: OTLP_VALUE_STRING, str: r.body})
+			b = append(b, `,"attributes":`...)
+			b = appendOTLPJSONKeyValues(b, r.attributes)
+			if r.trace.IsValid() {
+				b = append(b, `,"flags":`...)
+				b = strconv.AppendInt(b, int64(r.trace.Flags), 10)
+				b = append(b, `,"traceId":"`...)
+				b = r.trace.appendPart(b, 0)
+				b = append(b, `","spanId":"`...)
+				b = r.trace.appendPart(b, 1)
+				b = append(b, '"')
+			}
+			b = append(b, '}')
+		}
+		b = append(b, "]}"...)
+	}
+
+	return append(b, "]}]}"...)
+}
+
+// appendOTLPJSONKeyValues appends array of KeyValue
+func appendOTLPJSONKeyValues(b []byte, values []otlpKeyValue) []byte {
+	b = append(b, '[')
+	for i := 0; i < len(values); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, `{"key":`...)
+		b = appendJSONString(b, values[i].key)
+		b = append(b, `,"value":`...)
+		b = appendOTLPJSONValue(b, &values[i].value)
+		b = append(b, '}')
+	}
+
+	return append(b, ']')
+}
+
+// appendOTLPJSONValue appends AnyValue
+func appendOTLPJSONValue(b []byte, v *otlpValue) []byte {
+	switch v.kind {
+	case OTLP_VALUE_STRING:
+		b = append(b, `{"stringValue":`...)
+		b = appendJSONString(b, v.str)
+	case OTLP_VALUE_BOOL:
+		b = append(b, `{"boolValue":`...)
+		b = strconv.AppendBool(b, v.integer == 1)
+	case OTLP_VALUE_INT:
+		b = append(b, `{"intValue":"`...)
+		b = strconv.AppendInt(b, v.integer, 10)
+		b = append(b, '"')
+	case OTLP_VALUE_DOUBLE:
+		b = append(b, `{"doubleValue":`...)
+		switch {
+		case math.IsInf(v.double, 1):
+			b = append(b, OTLP_JSON_INF_P...)
+		case math.IsInf(v.double, -1):
+			b = append(b, OTLP_JSON_INF_N...)
+		default:
+			b = appendJSONFloat(b, v.double, 64)
+		}
+	case OTLP_VALUE_KVLIST:
+		b = append(b, `{"kvlistValue":{"values":`...)
+		b = appendOTLPJSONKeyValues(b, v.values)
+		b = append(b, '}')
+	}
+
+	return append(b, '}')
+}
+
+// Format 
+func (o *OTLP) Format() int {
+	return OTLP_FORMAT
+}
+
+// FormatName 
+func (o *OTLP) FormatName() string {
+	return OTLP_NAME
+}
+
+// Levels 
+func (o *OTLP) Levels() []int {
+	return Levels()
+}
+
+// Level 
+func (o *OTLP) Level() int {
+	return o.format.get().Level
+}
+
+// IsLevel 
+func (o *OTLP) IsLevel(l int) bool {
+	return IsLevel(l)
+}
+
+// SetLevel 
+func (o *OTLP) SetLevel(l int) error {
+	var err error
+
+	if o.IsLevel(l) {
+		o.format.set(func(format *Formatter) {
+			format.Level = l
+		})
+	} else {
+		err = errors.New(__ERROR_STR_LEVEL)
+		o.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: o.FormatName()})
+	}
+
+	return err
+}
+
+// LevelNames 
+func (o *OTLP) LevelNames() []string {
+	return LevelNames()
+}
+
+// LevelName 
+func (o *OTLP) LevelName() string {
+	return levelNames[o.format.get().Level]
+}
+
+// IsLevelName 
+func (o *OTLP) IsLevelName(l string) bool {
+	return IsLevelName(l)
+}
+
+// SetLevelName 
+func (o *OTLP) SetLevelName(l string) error {
+	var err error
+
+	l = strings.ToLower(strings.TrimSpace(l))
+	if o.IsLevelName(l) {
+		o.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
+	} else {
+		err = errors.New(__ERROR_STR_LEVEL_NAME)
+		o.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: o.FormatName()})
+	}
+
+	return err
+}
+
+// Labels 
+func (o *OTLP) Labels() string {
+	return o.format.get().Labels.String
+}
+
+// SetLabels 
+func (o *OTLP) SetLabels(l string) {
+	o.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
+}
+
+// LabelsSeparator 
+func (o *OTLP) LabelsSeparator() string {
+	return o.format.get().Labels.Separator
+}
+
+// SetLabelsSeparator 
+func (o *OTLP) SetLabelsSeparator(s string) {
+	o.format.set(func(format *Formatter) {
+		format.Labels.Separator = s
+	})
+}
+
+// LabelsToString 
+func (o *OTLP) LabelsToString(l []string) string {
+	return strings.Join(l, o.format.get().Labels.Separator)
+}
+
+// LabelsToSlice 
+func (o *OTLP) LabelsToSlice(l string) []string {
+	return strings.Split(l, o.format.get().Labels.Separator)
+}
+
+// Environment 
+func (o *OTLP) Environment() string {
+	return o.format.get().Environment
+}
+
+// SetEnvironment 
+func (o *OTLP) SetEnvironment(e string) {
+	o.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
+}
+
+// Tag 
+func (o *OTLP) Tag() string {
+	return o.format.get().Tag
+}
+
+// SetTag 
+func (o *OTLP) SetTag(t string) {
+	o.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(t)
+	})
+}
+
+// IsTimeUTC 
+func (o *OTLP) IsTimeUTC() bool {
+	return o.format.get().Time.IsUTC
+}
+
+// SetTimeUTC 
+func (o *OTLP) SetTimeUTC(u bool) {
+	o.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
+}
+
+// IsTimeStamp 
+func (o *OTLP) IsTimeStamp() bool {
+	return o.format.get().Time.IsStamp
+}
+
+// SetTimeStamp 
+func (o *OTLP) SetTimeStamp(t bool) {
+	o.format.set(func(format *Formatter) {
+		format.Time.IsStamp = t
+	})
+}
+
+// TimeStampLevels 
+func (o *OTLP) TimeStampLevels() []int {
+	return TimeStampLevels()
+}
+
+// TimeStampLevel 
+func (o *OTLP) TimeStampLevel() int {
+	return o.format.get().Time.StampLevel
+}
+
+// IsTimeStampLevel 
+func (o *OTLP) IsTimeStampLevel(l int) bool {
+	return IsTimeStampLevel(l)
+}
+
+// SetTimeStampLevel 
+func (o *OTLP) SetTimeStampLevel(l int) error {
+	var err error
+
+	if o.IsTimeStampLevel(l) {
+		o.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
+	} else {
+		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
+		o.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: o.FormatName()})
+	}
+
+	return err
+}
+
+// TimeStampLevelNames 
+func (o *OTLP) TimeStampLevelNames() []string {
+	return TimeStampLevelNames()
+}
+
+// TimeStampLevelName 
+func (o *OTLP) TimeStampLevelName() string {
+	return timeStampLevelNames[o.format.get().Time.StampLevel]
+}
+
+// IsTimeStampLevelName 
+func (o *OTLP) IsTimeStampLevelName(l string) bool {
+	return IsTimeStampLevelName(l)
+}
+
+// SetTimeStampLevelName 
+func (o *OTLP) SetTimeStampLevelName(l string) error {
+	var err error
+
+	l = strings.ToLower(strings.TrimSpace(l))
+	if o.IsTimeStampLevelName(l) {
+		o.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
+	} else {
+		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
+		o.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: o.FormatName()})
+	}
+
+	return err
+}
+
+// TimeFormat 
+func (o *OTLP) TimeFormat() string {
+	return o.format.get().Time.Format
+}
+
+// SetTimeFormat 
+func (o *OTLP) SetTimeFormat(f string) {
+	o.format.set(func(format *Formatter) {
+		format.Time.Format = f
+	})
+}
+
+// Panic 
+func (o *OTLP) Panic(e error) {
+	if o.format.get().Level >= PANIC_LEVEL {
+		o.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Panicv 
+func (o *OTLP) Panicv(e error, v Vars) {
+	if o.format.get().Level >= PANIC_LEVEL {
+		o.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Panicf 
+func (o *OTLP) Panicf(e error, i ...interface{}) {
+	if o.format.get().Level >= PANIC_LEVEL {
+		o.write(newEntry(PANIC_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Panicln 
+func (o *OTLP) Panicln(i ...interface{}) {
+	if o.format.get().Level >= PANIC_LEVEL {
+		o.write(newEntry(PANIC_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Fatal 
+func (o *OTLP) Fatal(e error) {
+	if o.format.get().Level >= FATAL_LEVEL {
+		o.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Fatalv 
+func (o *OTLP) Fatalv(e error, v Vars) {
+	if o.format.get().Level >= FATAL_LEVEL {
+		o.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Fatalf 
+func (o *OTLP) Fatalf(e error, i ...interface{}) {
+	if o.format.get().Level >= FATAL_LEVEL {
+		o.write(newEntry(FATAL_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Fatalln 
+func (o *OTLP) Fatalln(i ...interface{}) {
+	if o.format.get().Level >= FATAL_LEVEL {
+		o.write(newEntry(FATAL_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Error 
+func (o *OTLP) Error(e error) {
+	if o.format.get().Level >= ERROR_LEVEL {
+		o.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Errorv 
+func (o *OTLP) Errorv(e error, v Vars) {
+	if o.format.get().Level >= ERROR_LEVEL {
+		o.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Errorf 
+func (o *OTLP) Errorf(e error, i ...interface{}) {
+	if o.format.get().Level >= ERROR_LEVEL {
+		o.write(newEntry(ERROR_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Errorln 
+func (o *OTLP) Errorln(i ...interface{}) {
+	if o.format.get().Level >= ERROR_LEVEL {
+		o.write(newEntry(ERROR_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Warn 
+func (o *OTLP) Warn(s string) {
+	if o.format.get().Level >= WARN_LEVEL {
+		o.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, nil))
+	}
+}
+
+// Warnv 
+func (o *OTLP) Warnv(s string, v Vars) {
+	if o.format.get().Level >= WARN_LEVEL {
+		o.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, v))
+	}
+}
+
+// Warnf 
+func (o *OTLP) Warnf(s string, i ...interface{}) {
+	if o.format.get().Level >= WARN_LEVEL {
+		o.write(newEntry(WARN_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
+	}
+}
+
+// Warnln 
+func (o *OTLP) Warnln(i ...interface{}) {
+	if o.format.get().Level >= WARN_LEVEL {
+		o.write(newEntry(WARN_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Info 
+func (o *OTLP) Info(s string) {
+	if o.format.get().Level >= INFO_LEVEL {
+		o.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, nil))
+	}
+}
+
+// Infov 
+func (o *OTLP) Infov(s string, v Vars) {
+	if o.format.get().Level >= INFO_LEVEL {
+		o.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, v))
+	}
+}
+
+// Infof 
+func (o *OTLP) Infof(s string, i ...interface{}) {
+	if o.format.get().Level >= INFO_LEVEL {
+		o.write(newEntry(INFO_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
+	}
+}
+
+// Infoln 
+func (o *OTLP) Infoln(i ...interface{}) {
+	if o.format.get().Level >= INFO_LEVEL {
+		o.write(newEntry(INFO_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Debug 
+func (o *OTLP) Debug(s string) {
+	if o.format.get().Level >= DEBUG_LEVEL {
+		o.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, nil))
+	}
+}
+
+// Debugv 
+func (o *OTLP) Debugv(s string, v Vars) {
+	if o.format.get().Level >= DEBUG_LEVEL {
+		o.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, v))
+	}
+}
+
+// Debugf 
+func (o *OTLP) Debugf(s string, i ...interface{}) {
+	if o.format.get().Level >= DEBUG_LEVEL {
+		o.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
+	}
+}
+
+// Debugln 
+func (o *OTLP) Debugln(i ...interface{}) {
+	if o.format.get().Level >= DEBUG_LEVEL {
+		o.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Trace 
+func (o *OTLP) Trace(s string) {
+	if o.format.get().Level >= TRACE_LEVEL {
+		o.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, nil))
+	}
+}
+
+// Tracev 
+func (o *OTLP) Tracev(s string, v Vars) {
+	if o.format.get().Level >= TRACE_LEVEL {
+		o.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, v))
+	}
+}
+
+// Tracef 
+func (o *OTLP) Tracef(s string, i ...interface{}) {
+	if o.format.get().Level >= TRACE_LEVEL {
+		o.write(newEntry(TRACE_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
+	}
+}
+
+// Traceln 
+func (o *OTLP) Traceln(i ...interface{}) {
+	if o.format.get().Level >= TRACE_LEVEL {
+		o.write(newEntry(TRACE_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Print 
+func (o *OTLP) Print(s string) {
+	o.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, nil))
+}
+
+// Printv 
+func (o *OTLP) Printv(s string, v Vars) {
+	o.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, v))
+}
+
+// Printf 
+func (o *OTLP) Printf(s string, i ...interface{}) {
+	o.write(newEntry(PRINT_LEVEL, EMPTY_STRING, fmt.Sprintf(s, i...), nil))
+}
+
+// Println 
+func (o *OTLP) Println(i ...interface{}) {
+	o.write(newEntry(PRINT_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+}
+
+// Close exports waiting records and stops exporter, state is kept for
+// callers still holding logger
+func (o *OTLP) Close() error {
+	if o == nil {
+		return nil
+	}
+
+	o.mutex.Lock()
+	if o.isClosed {
+		o.mutex.Unlock()
+		return nil
+	}
+	o.isClosed = true
+	o.mutex.Unlock()
+
+	o.format.flush()
+	if records := o.take(); len(records) > 0 {
+		o.batches <- records
+	}
+	close(o.batches)
+	<-o.done
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package logs
+
+import (
+	"io"
+	"math"
+	"sync"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"net/http"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/binary"
+	"net/http/httptest"
+)
+
+// otlpCollector is stub of OTLP/HTTP collector, first requests fail by
+// status unavailable
+type otlpCollector struct {
+	mutex    sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+// ServeHTTP 
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var reader io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == OTLP_ENCODING_GZIP {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reader = gz
+	}
+	body, _ := io.ReadAll(reader)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.requests = append(c.requests, r)
+	if c.failures > 0 {
+		c.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	c.bodies = append(c.bodies, body)
+}
+
+func TestOTLPJSON(t *testing.T) {
+	collector := &otlpCollector{failures: 1}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	o, err := NewOTLP(&OTLPSettings{
+		Connection: &Connection{URL: server.URL + "/v1/logs", RetryWait: 1},
+		Protocol:   OTLP_PROTOCOL_JSON,
+		Headers:    map[string]string{"Authorization": "Bearer token"},
+		IsGzip:     true,
+		BatchSize:  2,
+		Hostname:   "host",
+	}, &Formatter{Level: INFO_LEVEL, Tag: "api", Environment: "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Infov("started", Vars{"port": 8080, "tls": true, "db": map[string]interface{}{"name": "main"}})
+	o.Errorv(errors.New("failed"), Vars{"ratio": 0.5, "max": math.Inf(1), "min": math.Inf(-1)})
+	o.Debug("skipped")
+	o.Close()
+
+	if len(collector.requests) != 2 || len(collector.bodies) != 1 {
+		t.Fatalf("unexpected requests %d, bodies %d", len(collector.requests), len(collector.bodies))
+	}
+	if r := collector.requests[1]; r.Header.Get("Content-Type") != OTLP_CONTENT_TYPE_JSON || r.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("unexpected headers %v", r.Header)
+	}
+
+	var request struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []map[string]interface{} `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []struct {
+					SeverityNumber int                      `json:"severityNumber"`
+					SeverityText   string                   `json:"severityText"`
+					Body           map[string]interface{}   `json:"body"`
+					Attributes     []map[string]interface{} `json:"attributes"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal(collector.bodies[0], &request); err != nil {
+		t.Fatalf("%v %s", err, collector.bodies[0])
+	}
+	if s := mustJSON(request.ResourceLogs[0].Resource.Attributes); s != `[{"key":"service.name","value":{"stringValue":"api"}},{"key":"deployment.environment.name","value":{"stringValue":"prod"}},{"key":"host.name","value":{"stringValue":"host"}}]` {
+		t.Errorf("unexpected resource %s", s)
+	}
+
+	records := request.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 2 {
+		t.Fatalf("unexpected records %s", collector.bodies[0])
+	}
+	if records[0].SeverityNumber != 9 || records[0].SeverityText != "INFO" || records[0].Body["stringValue"] != "started" {
+		t.Errorf("unexpected record %+v", records[0])
+	}
+	if s := mustJSON(records[0].Attributes); s != `[{"key":"db","value":{"kvlistValue":{"values":[{"key":"name","value":{"stringValue":"main"}}]}}},{"key":"port","value":{"intValue":"8080"}},{"key":"tls","value":{"boolValue":true}}]` {
+		t.Errorf("unexpected attributes %s", s)
+	}
+	if records[1].SeverityNumber != 17 || records[1].Body["stringValue"] != "failed" {
+		t.Errorf("unexpected record %+v", records[1])
+	}
+	if s := mustJSON(records[1].Attributes); !strings.Contains(s, `{"key":"max","value":{"doubleValue":"Infinity"}},{"key":"min","value":{"doubleValue":"-Infinity"}}`) {
+		t.Errorf("unexpected infinities %s", s)
+	}
+}
+
+func TestOTLPNoRetry(t *testing.T) {
+	collector := &otlpCollector{failures: 1}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	o, err := NewOTLP(&OTLPSettings{Connection: &Connection{URL: server.URL, RetryWait: 1, MaxRetry: OTLP_MAX_RETRY_NO}}, &Formatter{Level: INFO_LEVEL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Info("lost")
+	o.Close()
+
+	if len(collector.requests) != 1 || len(collector.bodies) != 0 {
+		t.Errorf("unexpected requests %d, bodies %d", len(collector.requests), len(collector.bodies))
+	}
+	if _, err := NewOTLP(&OTLPSettings{Connection: &Connection{MaxRetry: -2}}); err == nil {
+		t.Error("invalid max retry is accepted")
+	}
+}
+
+func TestOTLPProtobuf(t *testing.T) {
+	collector := &otlpCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	o, err := NewOTLP(&OTLPSettings{Connection: &Connection{URL: server.URL}}, &Formatter{Level: INFO_LEVEL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, _ := ParseTraceparent(testTraceparent)
+	newTestLogs(o).WithContext(ContextWithTrace(context.Background(), tc)).Warnv("slow", Vars{"ms": 250})
+	if err := o.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	o.Close()
+
+	if len(collector.bodies) != 1 || collector.requests[0].Header.Get("Content-Type") != OTLP_CONTENT_TYPE_PROTOBUF {
+		t.Fatalf("unexpected requests %d", len(collector.requests))
+	}
+	record := protoField(t, protoField(t, protoField(t, collector.bodies[0], 1), 2), 2)
+	if body := protoField(t, protoField(t, record, 5), 1); string(body) != "slow" {
+		t.Errorf("unexpected body %q", body)
+	}
+	if severity := protoField(t, record, 2); len(severity) != 1 || severity[0] != 13 {
+		t.Errorf("unexpected severity %v", severity)
+	}
+	if traceID := protoField(t, record, 9); !bytes.Equal(traceID, tc.TraceID[:]) {
+		t.Errorf("unexpected trace id %x", traceID)
+	}
+	attribute := protoField(t, record, 6)
+	if key := protoField(t, attribute, 1); string(key) != "ms" {
+		t.Errorf("unexpected attribute %q", key)
+	}
+	if value := protoField(t, protoField(t, attribute, 2), 3); len(value) != 2 || binary.LittleEndian.Uint16(value) != 0x1fa {
+		t.Errorf("unexpected value %v", value)
+	}
+}
+
+func TestOTLPInvalid(t *testing.T) {
+	for _, s := range []*OTLPSettings{
+		{Connection: &Connection{URL: "tcp://localhost:4318"}},
+		{Protocol: "grpc"},
+		{BatchSize: -1},
+	} {
+		if _, err := NewOTLP(s, &Formatter{}); err == nil {
+			t.Errorf("invalid settings %+v are accepted", s)
+		}
+	}
+}
+
+// protoField returns first field n of message, varint is returned as bytes
+func protoField(t *testing.T, b []byte, n int) []byte {
+	t.Helper()
+	for len(b) > 0 {
+		tag, l := binary.Uvarint(b)
+		b = b[l:]
+		var value []byte
+		switch int(tag & 7) {
+		case PROTO_WIRE_VARINT:
+			_, l = binary.Uvarint(b)
+			value, b = b[:l], b[l:]
+		case PROTO_WIRE_FIXED64:
+			value, b = b[:8], b[8:]
+		case PROTO_WIRE_FIXED32:
+			value, b = b[:4], b[4:]
+		case PROTO_WIRE_BYTES:
+			size, l := binary.Uvarint(b)
+			value, b = b[l:l+int(size)], b[l+int(size):]
+		}
+		if int(tag>>3) == n {
+			return value
+		}
+	}
+	t.Fatalf("field %d is not found", n)
+
+	return nil
+}
+
+// mustJSON 
+func mustJSON(v interface{}) string {
+	b, _ := json.Marshal(v)
+
+	return string(bytes.TrimSpace(b))
+}
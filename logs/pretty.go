@@ -0,0 +1,210 @@
+package logs
+
+import (
+	"fmt"
+	"time"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Columns and limits of pretty text
+const (
+	PRETTY_TIME_WIDTH    = int(9)
+	PRETTY_LEVEL_WIDTH   = int(5)
+	PRETTY_NAME_WIDTH    = int(12)
+	PRETTY_MESSAGE_WIDTH = int(40)
+	PRETTY_INLINE_FIELDS = int(4)
+	PRETTY_INLINE_WIDTH  = int(80)
+	PRETTY_INDENT        = "    "
+	PRETTY_CAUSE         = "caused by: "
+	PRETTY_EQUALLY       = " = "
+)
+
+// processStart is origin of relative time of pretty text
+var processStart = time.Now()
+
+// Columns of pretty text
+var (
+	prettyTime    = textSegment{width: PRETTY_TIME_WIDTH, isRight: true}
+	prettyLevel   = textSegment{width: PRETTY_LEVEL_WIDTH}
+	prettyName    = textSegment{width: PRETTY_NAME_WIDTH}
+	prettyMessage = textSegment{width: PRETTY_MESSAGE_WIDTH}
+)
+
+// prettyField is flattened field with rendered value, lines are written
+// below the value
+type prettyField struct {
+	key     string
+	value   string
+	lines   []string
+	isError bool
+}
+
+// prettyFields appends flattened field, errors get lines of their causes and
+// of stack trace which is printed by %+v
+func prettyFields(fields []prettyField, format *Formatter, k string, f *Field) []prettyField {
+	if f.Type == FIELD_TYPE_OBJECT {
+		list := objectFields(nil, f)
+		for i := 0; i < len(list); i++ {
+			if !list[i].isSkipped() {
+				fields = prettyFields(fields, format, k+OBJECT_KEY_SEPARATOR+list[i].Key, &list[i])
+			}
+		}
+		return fields
+	}
+
+	var details []string
+
+	field := prettyField{key: k}
+	if f.Type == FIELD_TYPE_ERROR {
+		err := f.Interface.(error)
+		field.value = err.Error()
+		field.isError = true
+		for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+			details = append(details, PRETTY_CAUSE+cause.Error())
+		}
+		if s := fmt.Sprintf("%+v", err); s != field.value {
+			details = append(details, strings.Split(strings.TrimPrefix(s, field.value), "\n")...)
+		}
+	} else {
+		field.value = string(appendTextField(nil, format, f))
+	}
+
+	lines := strings.Split(strings.TrimRight(field.value, "\n"), "\n")
+	field.value = lines[0]
+	for _, line := range append(lines[1:], details...) {
+		if strings.TrimSpace(line) != EMPTY_STRING {
+			field.lines = append(field.lines, line)
+		}
+	}
+
+	return append(fields, field)
+}
+
+// appendPretty appends entry as developer console record. Time since start
+// of process, level and logger name are aligned columns, fields follow
+// message when they are few and short, otherwise every field is written on
+// its own indented line. Errors are highlighted with their causes and stack
+// traces, caller, environment, labels, tag and trace id are dimmed.
+func (t *Text) appendPretty(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	var fields []prettyField
+
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() {
+			fields = prettyFields(fields, format, f.Key, &f)
+		}
+	}
+	for _, key := range keys {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() {
+			fields = prettyFields(fields, format, key, &f)
+		}
+	}
+
+	key := textSegment{}
+	isBlock, inline := len(fields) > PRETTY_INLINE_FIELDS, 0
+	for i := 0; i < len(fields); i++ {
+		isBlock = isBlock || len(fields[i].lines) > 0
+		inline += len(fields[i].key) + len(fields[i].value) + 2
+		if n := utf8.RuneCountInString(fields[i].key); n > key.width {
+			key.width = n
+		}
+	}
+	isBlock = isBlock || inline > PRETTY_INLINE_WIDTH
+
+	b = t.dim(b)
+	start := len(b)
+	b = strconv.AppendFloat(b, e.Time.Sub(processStart).Seconds(), 'f', 3, 64)
+	b = prettyTime.fit(b, start)
+	b = t.reset(b)
+	b = append(b, ' ')
+
+	b = t.color(b, e.Level)
+	start = len(b)
+	b = append(b, textLayoutLevels["upper"][e.Level]...)
+	b = prettyLevel.fit(b, start)
+	b = t.reset(b)
+	b = append(b, ' ')
+
+	start = len(b)
+	b = append(b, e.Name...)
+	b = prettyName.fit(b, start)
+	b = append(b, ' ')
+
+	b = t.color(b, PRINT_LEVEL)
+	start = len(b)
+	b = t.appendString(b, format, e.Message)
+	if len(fields) > 0 && !isBlock {
+		b = prettyMessage.fit(b, start)
+		for i := 0; i < len(fields); i++ {
+			b = append(b, ' ')
+			b = t.appendVar(b, e.Level, fields[i].key)
+			b = t.appendPrettyValue(b, format, &fields[i], fields[i].value)
+		}
+	}
+	b = t.reset(b)
+
+	b = t.dim(b)
+	b = appendTextWord(b, format.Environment)
+	b = appendTextWord(b, format.Labels.String)
+	b = appendTextWord(b, format.Tag)
+	if e.Trace.IsValid() {
+		b = append(b, ' ')
+		b = append(b, format.Keys.Names.TraceID...)
+		b = append(b, '=')
+		b = e.Trace.appendPart(b, 0)
+	}
+	if frame := caller(); frame.File != EMPTY_STRING {
+		b = append(b, ' ')
+		b = appendCaller(b, frame.File, frame.Line)
+	}
+	b = t.reset(b)
+
+	if isBlock {
+		for i := 0; i < len(fields); i++ {
+			b = append(b, '\n')
+			b = append(b, PRETTY_INDENT...)
+			b = t.color(b, e.Level)
+			start = len(b)
+			b = append(b, fields[i].key...)
+			b = key.fit(b, start)
+			b = t.reset(b)
+			b = append(b, PRETTY_EQUALLY...)
+			b = t.appendPrettyValue(b, format, &fields[i], fields[i].value)
+			for _, line := range fields[i].lines {
+				b = append(b, '\n')
+				b = append(b, PRETTY_INDENT...)
+				for n := key.width + len(PRETTY_EQUALLY); n > 0; n-- {
+					b = append(b, ' ')
+				}
+				if fields[i].isError {
+					b = t.dim(b)
+				}
+				b = t.appendPrettyValue(b, format, &fields[i], line)
+			}
+		}
+	}
+
+	return b
+}
+
+// appendPrettyValue appends value or its line, errors are highlighted by
+// color of error level
+func (t *Text) appendPrettyValue(b []byte, format *Formatter, f *prettyField, s string) []byte {
+	if f.isError {
+		b = t.color(b, ERROR_LEVEL)
+	}
+	b = t.appendString(b, format, s)
+
+	return t.reset(b)
+}
+
+// dim appends escape code of dimmed text
+func (t *Text) dim(b []byte) []byte {
+	if t.isColorize {
+		b = append(b, TEXT_COLOR_DIM...)
+	}
+
+	return b
+}
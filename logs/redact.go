@@ -0,0 +1,236 @@
+package logs
+
+import (
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// 
+const (
+	REDACT_MASK           = "***"
+	REDACT_PATTERN_CARD   = `\b(?:\d[ -]?){12,18}\d\b`
+	REDACT_PATTERN_BEARER = `(?i)\bbearer\s+[a-z0-9\-._~+/]+=*`
+)
+
+// RedactSettings hides sensitive data before any encoder runs. Values of
+// keys matching Keys globs, e.g. "password" or "*_token", are masked, only
+// last Keep characters are left when it is set. Matches of Patterns are
+// masked in messages and values, elements of lists are redacted one by one.
+// Keys of nested objects are matched without their parent keys.
+type RedactSettings struct {
+	Keys     []string `json:"keys" yaml:"keys" xml:"keys" toml:"keys"`
+	Patterns []string `json:"patterns" yaml:"patterns" xml:"patterns" toml:"patterns"`
+	Mask     string   `json:"mask" yaml:"mask" xml:"mask" toml:"mask"`
+	Keep     int      `json:"keep" yaml:"keep" xml:"keep" toml:"keep"`
+}
+
+// Secret is string which is always logged as REDACT_MASK
+type Secret string
+
+// String 
+func (s Secret) String() string {
+	return REDACT_MASK
+}
+
+// GoString 
+func (s Secret) GoString() string {
+	return REDACT_MASK
+}
+
+// MarshalText 
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(REDACT_MASK), nil
+}
+
+// MarshalLog 
+func (s Secret) MarshalLog() interface{} {
+	return REDACT_MASK
+}
+
+// redactedObject is object with redacted fields
+type redactedObject []Field
+
+// MarshalLogObject 
+func (o redactedObject) MarshalLogObject(e ObjectEncoder) {
+	e.Add(o...)
+}
+
+// MarshalJSON keeps order of fields of object which is element of list
+func (o redactedObject) MarshalJSON() ([]byte, error) {
+	b := []byte{'{'}
+	for i := 0; i < len(o); i++ {
+		if o[i].isSkipped() {
+			continue
+		}
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, o[i].Key)
+		b = append(b, ':')
+		b = appendJSONInterface(b, o[i].Value())
+	}
+
+	return append(b, '}'), nil
+}
+
+// redactor 
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	mask     string
+	keep     int
+}
+
+// newRedactor returns nil when redaction is disabled
+func newRedactor(s *RedactSettings) (*redactor, error) {
+	if s == nil || len(s.Keys) == 0 && len(s.Patterns) == 0 {
+		return nil, nil
+	}
+
+	r := &redactor{
+		mask: s.Mask,
+		keep: s.Keep,
+	}
+	if r.mask == EMPTY_STRING {
+		r.mask = REDACT_MASK
+	}
+	for _, key := range s.Keys {
+		if _, err := path.Match(key, EMPTY_STRING); err != nil {
+			return nil, err
+		}
+		r.keys = append(r.keys, strings.ToLower(key))
+	}
+	for _, pattern := range s.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// redactCheck 
+func redactCheck(s *RedactSettings) error {
+	_, err := newRedactor(s)
+
+	return err
+}
+
+// isKey tells if value of key is masked
+func (r *redactor) isKey(k string) bool {
+	if len(r.keys) > 0 {
+		k = strings.ToLower(k)
+		for _, key := range r.keys {
+			if ok, _ := path.Match(key, k); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// scrub masks matches of patterns
+func (r *redactor) scrub(s string) string {
+	for _, re := range r.patterns {
+		if re.MatchString(s) {
+			s = re.ReplaceAllLiteralString(s, r.mask)
+		}
+	}
+
+	return s
+}
+
+// masked returns mask with last Keep characters of value, short values are
+// masked fully
+func (r *redactor) masked(s string) string {
+	if r.keep > 0 && utf8.RuneCountInString(s) > 2*r.keep {
+		i := len(s)
+		for n := 0; n < r.keep; n++ {
+			_, size := utf8.DecodeLastRuneInString(s[:i])
+			i -= size
+		}
+		return r.mask + s[i:]
+	}
+
+	return r.mask
+}
+
+// field returns redacted copy of field
+func (r *redactor) field(format *Formatter, f Field) Field {
+	return r.redact(format, f.resolve(), 0)
+}
+
+// redact redacts resolved field. Objects and lists are redacted by their
+// elements until OBJECT_DEPTH_MAX, rendered text of other values is
+// scrubbed by patterns.
+func (r *redactor) redact(format *Formatter, f Field, depth int) Field {
+	switch {
+	case f.isSkipped():
+	case r.isKey(f.Key):
+		if r.keep > 0 && f.Type != FIELD_TYPE_OBJECT {
+			return String(f.Key, r.masked(string(appendTextField(nil, format, &f))))
+		}
+		return String(f.Key, r.mask)
+	case f.Type == FIELD_TYPE_OBJECT && depth < OBJECT_DEPTH_MAX:
+		fields := objectFields(nil, &f)
+		for i := 0; i < len(fields); i++ {
+			fields[i] = r.redact(format, fields[i].resolve(), depth + 1)
+		}
+		f.Interface = redactedObject(fields)
+	case f.Type == FIELD_TYPE_ANY && isList(f.Interface) && depth < OBJECT_DEPTH_MAX:
+		v := reflect.ValueOf(f.Interface)
+		list := make([]interface{}, v.Len())
+		for i := 0; i < len(list); i++ {
+			list[i] = r.redact(format, valueField(EMPTY_STRING, v.Index(i).Interface(), 0), depth + 1).Value()
+		}
+		f.Interface = list
+	case len(r.patterns) == 0:
+	case f.Type == FIELD_TYPE_STRING:
+		f.String = r.scrub(f.String)
+	case f.Type == FIELD_TYPE_ERROR, f.Type == FIELD_TYPE_ANY, f.Type == FIELD_TYPE_OBJECT:
+		s := string(appendTextField(nil, format, &f))
+		if scrubbed := r.scrub(s); scrubbed != s {
+			return String(f.Key, scrubbed)
+		}
+	}
+
+	return f
+}
+
+// isList is true for arrays and slices which are not nil
+func isList(v interface{}) bool {
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Array:
+		return true
+	case reflect.Slice:
+		return !r.IsNil()
+	}
+
+	return false
+}
+
+// apply redacts entry in place, vars of caller are moved to fields of entry
+// instead of being changed
+func (r *redactor) apply(format *Formatter, e *Entry) {
+	if len(r.patterns) > 0 {
+		e.Message = r.scrub(e.Message)
+	}
+	if len(e.Vars) > 0 {
+		buffer := newBuffer()
+		for _, key := range buffer.sortKeys(e.Vars) {
+			e.Fields = append(e.Fields, valueField(key, e.Vars[key], 0))
+		}
+		buffer.free()
+		e.Vars = nil
+	}
+	for i := 0; i < len(e.Fields); i++ {
+		e.Fields[i] = r.field(format, e.Fields[i])
+	}
+}
@@ -0,0 +1,87 @@
+package logs
+
+import (
+	"log"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	redact := &RedactSettings{
+		Keys:     []string{"password", "*_token"},
+		Patterns: []string{REDACT_PATTERN_BEARER, REDACT_PATTERN_CARD},
+		Keep:     4,
+	}
+	v := Vars{
+		"password":  "hunter2",
+		"api_token": "abcdef123456",
+		"auth":      "Bearer abc.def",
+		"user":      map[string]interface{}{"name": "joe", "Password": "nested"},
+		"key":       Secret("s3cr3t"),
+		"list":      []string{"Bearer abc.def"},
+		"users":     []interface{}{map[string]interface{}{"name": "ann", "password": "hunter2"}, [1]string{"Bearer abc.def"}},
+		"codes":     map[int]string{1: "Bearer abc.def"},
+	}
+	w := &bytes.Buffer{}
+	for _, l := range []logger{
+		writerJSON(&Formatter{Level: INFO_LEVEL, Redact: redact}, w),
+		writerFMT(&Formatter{Level: INFO_LEVEL, Redact: redact}, w),
+		writerText(&Formatter{Level: INFO_LEVEL, Redact: redact}, w, false),
+	} {
+		ls := newTestLogs(l)
+		w.Reset()
+		ls.Infov("paid by 4111 1111 1111 1111", v)
+		ls.Infow("login", String("password", "hunter2"), Err(&testError{"token Bearer abc.def"}))
+
+		out := w.String()
+		for _, s := range []string{"hunter2", "abc.def", "4111", "nested", "s3cr3t"} {
+			if strings.Contains(out, s) {
+				t.Errorf("%s: %q is not redacted in %s", l.FormatName(), s, out)
+			}
+		}
+		if !strings.Contains(out, "***3456") || !strings.Contains(out, "joe") || !strings.Contains(out, "ann") {
+			t.Errorf("%s: unexpected output %s", l.FormatName(), out)
+		}
+	}
+	if out := w.String(); !strings.Contains(out, `list=["***"]`) || !strings.Contains(out, `users=[{"name":"ann","password":"***"},["***"]]`) {
+		t.Errorf("unexpected lists %s", out)
+	}
+	if v["password"] != "hunter2" || v["user"].(map[string]interface{})["Password"] != "nested" || v["list"].([]string)[0] != "Bearer abc.def" {
+		t.Errorf("vars are changed: %v", v)
+	}
+}
+
+func TestRedactSwitchFormat(t *testing.T) {
+	ls, err := New(&Formatter{Level: INFO_LEVEL, Multiline: MULTILINE_SPLIT, Redact: &RedactSettings{Keys: []string{"password"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ls.SetFormat(JSON_FORMAT); err != nil {
+		t.Fatal(err)
+	}
+	w := &bytes.Buffer{}
+	j := ls.core.load().(*JSON)
+	j.stdout = log.New(w, EMPTY_STRING, 0)
+
+	ls.Infov("login\nok", Vars{"password": "hunter2"})
+	if s := w.String(); strings.Contains(s, "hunter2") || strings.Count(s, "\n") != 2 || j.formatter().Keys.Prefix != JSON_KEYS_PREFIX {
+		t.Errorf("settings of formatter are lost by switch of format: %s", s)
+	}
+}
+
+func TestRedactInvalid(t *testing.T) {
+	if _, err := NewJSON(nil, &Formatter{Redact: &RedactSettings{Patterns: []string{"("}}}); err == nil {
+		t.Error("invalid pattern is accepted")
+	}
+}
+
+// testError 
+type testError struct {
+	s string
+}
+
+// Error 
+func (e *testError) Error() string {
+	return e.s
+}
@@ -0,0 +1,235 @@
+package logs
+
+import (
+	"sync"
+	"time"
+	"errors"
+	"sync/atomic"
+)
+
+// 
+const (
+	SAMPLING_INTERVAL_DEFAULT = int(1000)
+	SAMPLING_COUNTERS         = int(1024)
+	SAMPLING_SUMMARY_MESSAGE  = "Log records are dropped by sampling"
+	KEY_DROPPED               = "dropped"
+)
+
+// Error string messages
+const (
+	__ERROR_STR_SAMPLING = "Invalid sampling settings"
+)
+
+// SamplingSettings drops repeated records. First records of the same level and
+// message are logged in every interval, after that every Thereafter-th one.
+// Message of *f methods is their format string and message of *ln methods is
+// their arguments formatted like fmt.Sprintln without styles of logger.
+// Rate limits records per second of every level, Burst is size of the bucket.
+// Counts of dropped records are logged every Summary interval. Intervals are
+// in milliseconds, zero values disable the feature.
+type SamplingSettings struct {
+	Interval   int `json:"interval" yaml:"interval" xml:"interval" toml:"interval"`
+	First      int `json:"first" yaml:"first" xml:"first" toml:"first"`
+	Thereafter int `json:"thereafter" yaml:"thereafter" xml:"thereafter" toml:"thereafter"`
+	Rate       int `json:"rate" yaml:"rate" xml:"rate" toml:"rate"`
+	Burst      int `json:"burst" yaml:"burst" xml:"burst" toml:"burst"`
+	Summary    int `json:"summary" yaml:"summary" xml:"summary" toml:"summary"`
+}
+
+// droppedKeys are summary keys by level
+var droppedKeys = []string{
+	KEY_DROPPED + "_" + PANIC_LEVEL_NAME,
+	KEY_DROPPED + "_" + FATAL_LEVEL_NAME,
+	KEY_DROPPED + "_" + ERROR_LEVEL_NAME,
+	KEY_DROPPED + "_" + WARN_LEVEL_NAME,
+	KEY_DROPPED + "_" + INFO_LEVEL_NAME,
+	KEY_DROPPED + "_" + DEBUG_LEVEL_NAME,
+	KEY_DROPPED + "_" + TRACE_LEVEL_NAME,
+}
+
+// samplingCounter counts records of one key in current interval
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// inc 
+func (c *samplingCounter) inc(now int64, interval int64) uint64 {
+	if c.resetAt.Load() > now {
+		return c.count.Add(1)
+	}
+	c.count.Store(1)
+	c.resetAt.Store(now + interval)
+
+	return 1
+}
+
+// tokenBucket 
+type tokenBucket struct {
+	mutex  sync.Mutex
+	tokens float64
+	last   int64
+}
+
+// take 
+func (b *tokenBucket) take(now int64, rate float64, burst float64) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.last == 0 {
+		b.tokens = burst
+	} else {
+		b.tokens += float64(now-b.last) * rate / float64(time.Second)
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// sampler applies SamplingSettings to records of ERROR..TRACE levels, panic, fatal
+// and print records are never dropped
+type sampler struct {
+	settings SamplingSettings
+	interval int64
+	counters []samplingCounter
+	buckets  [TRACE_LEVEL + 1]tokenBucket
+	dropped  [TRACE_LEVEL + 1]atomic.Uint64
+	stop     chan struct{}
+	done     chan struct{}
+	closing  sync.Once
+}
+
+// samplingCheck 
+func samplingCheck(s *SamplingSettings) error {
+	if s.Interval < 0 || s.First < 0 || s.Thereafter < 0 || s.Rate < 0 || s.Burst < 0 || s.Summary < 0 {
+		return errors.New(__ERROR_STR_SAMPLING)
+	}
+
+	return nil
+}
+
+// newSampler returns nil when nothing is enabled
+func newSampler(s *SamplingSettings) (*sampler, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if err := samplingCheck(s); err != nil {
+		return nil, err
+	}
+	if s.First == 0 && s.Rate == 0 {
+		return nil, nil
+	}
+
+	sm := &sampler{
+		settings: *s,
+	}
+	if sm.settings.Interval == 0 {
+		sm.settings.Interval = SAMPLING_INTERVAL_DEFAULT
+	}
+	if sm.settings.Burst == 0 {
+		sm.settings.Burst = sm.settings.Rate
+	}
+	sm.interval = int64(sm.settings.Interval) * int64(time.Millisecond)
+	if sm.settings.First > 0 {
+		sm.counters = make([]samplingCounter, (TRACE_LEVEL+1)*SAMPLING_COUNTERS)
+	}
+
+	return sm, nil
+}
+
+// samplingHash is FNV-1a of message
+func samplingHash(s string) uint32 {
+	h := uint32(2166136261)
+
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+
+	return h
+}
+
+// allow counts record and tells if it has to be logged
+func (sm *sampler) allow(l int, m string) bool {
+	if l < ERROR_LEVEL || l > TRACE_LEVEL {
+		return true
+	}
+
+	now := time.Now().UnixNano()
+	if sm.counters != nil {
+		c := &sm.counters[l*SAMPLING_COUNTERS+int(samplingHash(m)%uint32(SAMPLING_COUNTERS))]
+		n := c.inc(now, sm.interval)
+		if n > uint64(sm.settings.First) && (sm.settings.Thereafter == 0 || (n-uint64(sm.settings.First))%uint64(sm.settings.Thereafter) != 0) {
+			sm.dropped[l].Add(1)
+			return false
+		}
+	}
+	if sm.settings.Rate > 0 && !sm.buckets[l].take(now, float64(sm.settings.Rate), float64(sm.settings.Burst)) {
+		sm.dropped[l].Add(1)
+		return false
+	}
+
+	return true
+}
+
+// drain appends counts of dropped records and resets them. Level of the
+// most severe dropped record is returned, so summary is seen whenever the
+// dropped records would be.
+func (sm *sampler) drain(fields []Field) ([]Field, int) {
+	level := PRINT_LEVEL
+
+	for l := ERROR_LEVEL; l <= TRACE_LEVEL; l++ {
+		if n := sm.dropped[l].Swap(0); n > 0 {
+			fields = append(fields, Uint64(droppedKeys[l], n))
+			if l < level {
+				level = l
+			}
+		}
+	}
+
+	return fields, level
+}
+
+// run calls summary every Summary interval until stop is closed
+func (sm *sampler) run(summary func(sm *sampler)) {
+	if sm.settings.Summary == 0 {
+		return
+	}
+
+	sm.stop = make(chan struct{})
+	sm.done = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Duration(sm.settings.Summary) * time.Millisecond)
+		defer func() {
+			ticker.Stop()
+			close(sm.done)
+		}()
+
+		for {
+			select {
+			case <-ticker.C:
+				summary(sm)
+			case <-sm.stop:
+				return
+			}
+		}
+	}()
+}
+
+// close stops summary goroutine
+func (sm *sampler) close() {
+	sm.closing.Do(func() {
+		if sm.stop != nil {
+			close(sm.stop)
+			<-sm.done
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// samplingLogs returns logger writing logfmt records to w
+func samplingLogs(t *testing.T, w *bytes.Buffer, s *SamplingSettings) *Logs {
+	ls := newTestLogs(writerFMT(&Formatter{Level: TRACE_LEVEL}, w))
+	if err := ls.SetSampling(s); err != nil {
+		t.Fatal(err)
+	}
+
+	return ls
+}
+
+func TestSamplingFirstThereafter(t *testing.T) {
+	w := &bytes.Buffer{}
+	err := errors.New("repeated")
+	ls := samplingLogs(t, w, &SamplingSettings{Interval: 60000, First: 2, Thereafter: 3})
+
+	for i := 0; i < 10; i++ {
+		ls.Error(err)
+		ls.Print("printed")
+	}
+	ls.Warn("other")
+	if n := strings.Count(w.String(), "msg=repeated"); n != 4 {
+		t.Errorf("got %d records, want 4", n)
+	}
+	if n := strings.Count(w.String(), "msg=printed"); n != 10 {
+		t.Errorf("print records are sampled: %d", n)
+	}
+	if !strings.Contains(w.String(), "msg=other") {
+		t.Error("record of other level is dropped")
+	}
+
+	w.Reset()
+	ls.Close()
+	if !strings.Contains(w.String(), "dropped_error=6") || !strings.Contains(w.String(), "level=error") {
+		t.Errorf("unexpected summary %q", w.String())
+	}
+}
+
+func TestSamplingKeys(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL}, w, true))
+	if err := ls.SetSampling(&SamplingSettings{Interval: 60000, First: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	ls.Info("ready 1")
+	ls.Infoln("ready", 1)
+	ls.Infof("ready %d", 2)
+	ls.Infof("ready %d", 3)
+	if n := strings.Count(w.String(), "ready"); n != 2 || !strings.Contains(w.String(), "ready 1") || !strings.Contains(w.String(), "2") {
+		t.Errorf("unexpected records %q", w.String())
+	}
+}
+
+func TestSamplingRate(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := samplingLogs(t, w, &SamplingSettings{Rate: 1, Burst: 2})
+
+	for i := 0; i < 5; i++ {
+		ls.Named("http").Infof("request %d", i)
+	}
+	if n := strings.Count(w.String(), "request"); n != 2 {
+		t.Errorf("got %d records, want 2", n)
+	}
+
+	w.Reset()
+	if err := ls.SetSampling(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.String(), "dropped_info=3") {
+		t.Errorf("unexpected summary %q", w.String())
+	}
+	if ls.Sampling() != nil {
+		t.Error("sampling is not disabled")
+	}
+}
+
+func TestSamplingInvalid(t *testing.T) {
+	ls := newTestLogs(discardFMT(&Formatter{Level: INFO_LEVEL}))
+
+	if err := ls.SetSampling(&SamplingSettings{First: -1}); err == nil {
+		t.Error("invalid sampling is accepted")
+	}
+}
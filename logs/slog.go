@@ -0,0 +1,835 @@
+package logs
+
+import (
+	"fmt"
+	"math"
+	"time"
+	"errors"
+	"context"
+	"strings"
+	"log/slog"
+)
+
+// SLOG_NAME 
+const SLOG_NAME = "slog"
+
+// Levels of slog which have no constant in slog, records of fatal and panic
+// levels end process as records of logger do
+const (
+	SLOG_LEVEL_TRACE = slog.LevelDebug - 4
+	SLOG_LEVEL_FATAL = slog.LevelError + 4
+	SLOG_LEVEL_PANIC = slog.LevelError + 8
+)
+
+// Error string messages
+const (
+	__ERROR_STR_SLOG_HANDLER = "Slog handler must be defined"
+	__ERROR_STR_SLOG_HANDLE  = "Slog handler can not handle record"
+)
+
+// SlogSettings 
+type SlogSettings struct {
+	Handler slog.Handler
+}
+
+// Slog writes records by slog.Handler, e.g. by bridge of OpenTelemetry or
+// handler shared by platform. Entry maps onto slog record as:
+//
+//	level           record level, PANIC is SLOG_LEVEL_PANIC, FATAL is
+//	                SLOG_LEVEL_FATAL, TRACE is SLOG_LEVEL_TRACE, PRINT is
+//	                slog.LevelInfo and others are slog levels of the same name
+//	message, time   record message and time
+//	labels          string attribute under Keys.Names.Labels
+//	environment     string attribute under Keys.Names.Environment
+//	tag             string attribute under Keys.Names.Tag
+//	logger name     string attribute under Keys.Names.Logger
+//	trace context   string attributes under Keys.Names.TraceID, SpanID and
+//	                TraceFlags
+//	fields, Vars    attributes in order of fields and keys of Vars, objects
+//	                are groups and errors stay errors
+//
+// Empty labels, environment, tag and name are not written. Caller of logger
+// is source of record.
+type Slog struct {
+	format   *formatState
+	settings *SlogSettings
+}
+
+// slogLevels are slog levels by level of logger, print level is info
+var slogLevels = []slog.Level{SLOG_LEVEL_PANIC, SLOG_LEVEL_FATAL, slog.LevelError, slog.LevelWarn, slog.LevelInfo, slog.LevelDebug, SLOG_LEVEL_TRACE, slog.LevelInfo}
+
+// SlogHandler is slog.Handler which writes records by logger, so slog calls
+// get its format, settings, named levels, sampling and trace context
+type SlogHandler struct {
+	logs   *Logs
+	attrs  []slog.Attr
+	groups []slogGroup
+}
+
+// slogGroup is group opened by WithGroup with attributes added to it
+type slogGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// slogAttrs is group of attributes written as object
+type slogAttrs []slog.Attr
+
+// NewSlogHandler returns slog.Handler writing records by logger
+func NewSlogHandler(ls *Logs) *SlogHandler {
+	return &SlogHandler{logs: ls}
+}
+
+// slogLevel maps level of slog onto level of logger
+func slogLevel(l slog.Level) int {
+	switch {
+	case l < slog.LevelDebug:
+		return TRACE_LEVEL
+	case l < slog.LevelInfo:
+		return DEBUG_LEVEL
+	case l < slog.LevelWarn:
+		return INFO_LEVEL
+	case l < slog.LevelError:
+		return WARN_LEVEL
+	case l < SLOG_LEVEL_FATAL:
+		return ERROR_LEVEL
+	case l < SLOG_LEVEL_PANIC:
+		return FATAL_LEVEL
+	}
+
+	return PANIC_LEVEL
+}
+
+// Enabled reports whether level is enabled by logger
+func (h *SlogHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.logs.isEnabled(slogLevel(l))
+}
+
+// Handle writes record with attributes of handler and record, groups are
+// objects. Trace context of ctx is preferred to trace context of logger.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	l := slogLevel(r.Level)
+	ls := h.logs
+	if !ls.isEnabled(l) || !ls.core.allow(l, r.Message) {
+		return nil
+	}
+
+	var attrs []slog.Attr
+	if r.NumAttrs() > 0 {
+		attrs = make([]slog.Attr, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			attrs = append(attrs, a)
+			return true
+		})
+	}
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		group := h.groups[i]
+		attrs = append(group.attrs[:len(group.attrs):len(group.attrs)], attrs...)
+		attrs = []slog.Attr{{Key: group.name, Value: slog.GroupValue(attrs...)}}
+	}
+
+	e := newEntry(l, ls.name, r.Message, nil)
+	if !r.Time.IsZero() {
+		e.Time = r.Time
+	}
+	e.Trace = ls.trace
+	if t, ok := TraceFromContext(ctx); ok {
+		e.Trace = t
+	}
+	e.Fields = slogFields(e.Fields, h.attrs)
+	e.Fields = slogFields(e.Fields, attrs)
+	ls.core.write(e)
+	e.free()
+
+	return nil
+}
+
+// WithAttrs returns handler whose records have attributes, they belong to
+// the last group
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	c := *h
+	if n := len(h.groups); n > 0 {
+		c.groups = append([]slogGroup(nil), h.groups...)
+		c.groups[n-1].attrs = append(h.groups[n-1].attrs[:len(h.groups[n-1].attrs):len(h.groups[n-1].attrs)], attrs...)
+	} else {
+		c.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+	}
+
+	return &c
+}
+
+// WithGroup returns handler whose following attributes are in group, empty
+// name keeps handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == EMPTY_STRING {
+		return h
+	}
+
+	c := *h
+	c.groups = append(h.groups[:len(h.groups):len(h.groups)], slogGroup{name: name})
+
+	return &c
+}
+
+// MarshalLogObject 
+func (a slogAttrs) MarshalLogObject(o ObjectEncoder) {
+	o.Add(slogFields(nil, a)...)
+}
+
+// slogFields appends attributes as fields. Empty attributes and groups are
+// skipped, attributes of group without key are inlined.
+func slogFields(fields []Field, attrs []slog.Attr) []Field {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			if group := a.Value.Group(); len(group) == 0 {
+				continue
+			} else if a.Key == EMPTY_STRING {
+				fields = slogFields(fields, group)
+			} else {
+				fields = append(fields, Object(a.Key, slogAttrs(group)))
+			}
+			continue
+		}
+		fields = append(fields, slogField(a.Key, a.Value))
+	}
+
+	return fields
+}
+
+// slogField converts resolved value of slog to typed field
+func slogField(k string, v slog.Value) Field {
+	switch v.Kind() {
+	case slog.KindString:
+		return String(k, v.String())
+	case slog.KindInt64:
+		return Int64(k, v.Int64())
+	case slog.KindUint64:
+		return Uint64(k, v.Uint64())
+	case slog.KindFloat64:
+		return Float64(k, v.Float64())
+	case slog.KindBool:
+		return Bool(k, v.Bool())
+	case slog.KindDuration:
+		return Duration(k, v.Duration())
+	case slog.KindTime:
+		return Datetime(k, v.Time())
+	}
+
+	return Any(k, v.Any())
+}
+
+// NewSlog 
+func NewSlog(s *SlogSettings, f ...*Formatter) (*Slog, error) {
+	var (
+		format *Formatter
+		settings *SlogSettings
+	)
+
+	if s != nil {
+		settings = s
+	} else {
+		settings = &SlogSettings{}
+	}
+
+	if len(f) == 0 {
+		format = &Formatter{}
+	} else {
+		format = f[0]
+	}
+	defaultFormatter(format, false, true)
+
+	err := redactCheck(format.Redact)
+
+	if err == nil && settings.Handler == nil {
+		err = errors.New(__ERROR_STR_SLOG_HANDLER)
+	}
+	if err == nil {
+		return &Slog{
+			format:   newFormatState(format),
+			settings: settings,
+		}, nil
+	} else {
+		if format.Stderr.IsPrintable {
+			format.Stderr.Logger.Print(err.Error())
+		}
+		return nil, err
+	}
+}
+
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (s *Slog) write(e *Entry) {
+	s.format.write(s, e)
+}
+
+// formatter returns current snapshot of Formatter
+func (s *Slog) formatter() *Formatter {
+	return s.format.get()
+}
+
+// record converts entry to slog record and passes it to handler, level
+// disabled by handler is skipped
+func (s *Slog) record(e *Entry) {
+	ctx := context.Background()
+	level := slogLevels[e.Level]
+	if !s.settings.Handler.Enabled(ctx, level) {
+		return
+	}
+
+	format := s.format.get()
+	t := e.Time
+	if format.Time.IsUTC {
+		t = t.UTC()
+	}
+	var pc uintptr
+	if frame := caller(); frame.PC != 0 {
+		// Frames of slog are looked up by return address
+		pc = frame.PC + 1
+	}
+
+	r := slog.NewRecord(t, level, e.Message, pc)
+	r.AddAttrs(slogEntryAttrs(format, e)...)
+	if err := s.settings.Handler.Handle(ctx, r); err != nil && format.Stderr.IsPrintable {
+		format.Stderr.Logger.Print(__ERROR_STR_SLOG_HANDLE + ": " + err.Error())
+	}
+}
+
+// slogEntryAttrs returns attributes of entry by mapping of Slog
+func slogEntryAttrs(format *Formatter, e *Entry) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 4+len(e.Fields)+len(e.Vars))
+
+	for _, label := range []struct {
+		key   string
+		value string
+	}{
+		{format.Keys.Names.Labels, format.Labels.String},
+		{format.Keys.Names.Environment, format.Environment},
+		{format.Keys.Names.Tag, format.Tag},
+		{format.Keys.Names.Logger, e.Name},
+	} {
+		if label.value != EMPTY_STRING && !isOverwritten(format, e, label.key) {
+			attrs = append(attrs, slog.String(label.key, label.value))
+		}
+	}
+	for _, f := range traceFields(format, e) {
+		attrs = append(attrs, slog.String(f.Key, f.String))
+	}
+
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() && isKeptKey(format, f.Key) {
+			attrs = append(attrs, slogAttr(&f))
+		}
+	}
+	buffer := newBuffer()
+	for _, key := range buffer.sortKeys(e.Vars) {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() && isKeptKey(format, key) {
+			attrs = append(attrs, slogAttr(&f))
+		}
+	}
+	buffer.free()
+
+	return attrs
+}
+
+// slogAttr converts resolved field to attribute, object is group
+func slogAttr(f *Field) slog.Attr {
+	switch f.Type {
+	case FIELD_TYPE_STRING:
+		return slog.String(f.Key, f.String)
+	case FIELD_TYPE_INT64:
+		return slog.Int64(f.Key, f.Integer)
+	case FIELD_TYPE_UINT64:
+		return slog.Uint64(f.Key, uint64(f.Integer))
+	case FIELD_TYPE_FLOAT64:
+		return slog.Float64(f.Key, math.Float64frombits(uint64(f.Integer)))
+	case FIELD_TYPE_BOOL:
+		return slog.Bool(f.Key, f.Integer == 1)
+	case FIELD_TYPE_DURATION:
+		return slog.Duration(f.Key, time.Duration(f.Integer))
+	case FIELD_TYPE_TIME:
+		return slog.Time(f.Key, f.time())
+	case FIELD_TYPE_OBJECT:
+		fields := objectFields(nil, f)
+		attrs := make([]slog.Attr, 0, len(fields))
+		for i := 0; i < len(fields); i++ {
+			if !fields[i].isSkipped() {
+				attrs = append(attrs, slogAttr(&fields[i]))
+			}
+		}
+		return slog.Attr{Key: f.Key, Value: slog.GroupValue(attrs...)}
+	}
+
+	return slog.Any(f.Key, f.Interface)
+}
+
+// Format 
+func (s *Slog) Format() int {
+	return SLOG_FORMAT
+}
+
+// FormatName 
+func (s *Slog) FormatName() string {
+	return SLOG_NAME
+}
+
+// Levels 
+func (s *Slog) Levels() []int {
+	return Levels()
+}
+
+// Level 
+func (s *Slog) Level() int {
+	return s.format.get().Level
+}
+
+// IsLevel 
+func (s *Slog) IsLevel(l int) bool {
+	return IsLevel(l)
+}
+
+// SetLevel 
+func (s *Slog) SetLevel(l int) error {
+	var err error
+
+	if s.IsLevel(l) {
+		s.format.set(func(format *Formatter) {
+			format.Level = l
+		})
+	} else {
+		err = errors.New(__ERROR_STR_LEVEL)
+		s.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: s.FormatName()})
+	}
+
+	return err
+}
+
+// LevelNames 
+func (s *Slog) LevelNames() []string {
+	return LevelNames()
+}
+
+// LevelName 
+func (s *Slog) LevelName() string {
+	return levelNames[s.format.get().Level]
+}
+
+// IsLevelName 
+func (s *Slog) IsLevelName(l string) bool {
+	return IsLevelName(l)
+}
+
+// SetLevelName 
+func (s *Slog) SetLevelName(l string) error {
+	var err error
+
+	l = strings.ToLower(strings.TrimSpace(l))
+	if s.IsLevelName(l) {
+		s.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
+	} else {
+		err = errors.New(__ERROR_STR_LEVEL_NAME)
+		s.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: s.FormatName()})
+	}
+
+	return err
+}
+
+// Labels 
+func (s *Slog) Labels() string {
+	return s.format.get().Labels.String
+}
+
+// SetLabels 
+func (s *Slog) SetLabels(l string) {
+	s.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
+}
+
+// LabelsSeparator 
+func (s *Slog) LabelsSeparator() string {
+	return s.format.get().Labels.Separator
+}
+
+// SetLabelsSeparator 
+func (s *Slog) SetLabelsSeparator(spr string) {
+	s.format.set(func(format *Formatter) {
+		format.Labels.Separator = spr
+	})
+}
+
+// LabelsToString 
+func (s *Slog) LabelsToString(l []string) string {
+	return strings.Join(l, s.format.get().Labels.Separator)
+}
+
+// LabelsToSlice 
+func (s *Slog) LabelsToSlice(l string) []string {
+	return strings.Split(l, s.format.get().Labels.Separator)
+}
+
+// Environment 
+func (s *Slog) Environment() string {
+	return s.format.get().Environment
+}
+
+// SetEnvironment 
+func (s *Slog) SetEnvironment(e string) {
+	s.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
+}
+
+// Tag 
+func (s *Slog) Tag() string {
+	return s.format.get().Tag
+}
+
+// SetTag 
+func (s *Slog) SetTag(t string) {
+	s.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(t)
+	})
+}
+
+// IsTimeUTC 
+func (s *Slog) IsTimeUTC() bool {
+	return s.format.get().Time.IsUTC
+}
+
+// SetTimeUTC 
+func (s *Slog) SetTimeUTC(u bool) {
+	s.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
+}
+
+// IsTimeStamp 
+func (s *Slog) IsTimeStamp() bool {
+	return s.format.get().Time.IsStamp
+}
+
+// SetTimeStamp 
+func (s *Slog) SetTimeStamp(t bool) {
+	t = false
+	s.format.set(func(format *Formatter) {
+		format.Time.IsStamp = t
+	})
+}
+
+// TimeStampLevels 
+func (s *Slog) TimeStampLevels() []int {
+	return TimeStampLevels()
+}
+
+// TimeStampLevel 
+func (s *Slog) TimeStampLevel() int {
+	return s.format.get().Time.StampLevel
+}
+
+// IsTimeStampLevel 
+func (s *Slog) IsTimeStampLevel(l int) bool {
+	return IsTimeStampLevel(l)
+}
+
+// SetTimeStampLevel 
+func (s *Slog) SetTimeStampLevel(l int) error {
+	var err error
+
+	if s.IsTimeStampLevel(l) {
+		s.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
+	} else {
+		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
+		s.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: s.FormatName()})
+	}
+
+	return err
+}
+
+// TimeStampLevelNames 
+func (s *Slog) TimeStampLevelNames() []string {
+	return TimeStampLevelNames()
+}
+
+// TimeStampLevelName 
+func (s *Slog) TimeStampLevelName() string {
+	return timeStampLevelNames[s.format.get().Time.StampLevel]
+}
+
+// IsTimeStampLevelName 
+func (s *Slog) IsTimeStampLevelName(l string) bool {
+	return IsTimeStampLevelName(l)
+}
+
+// SetTimeStampLevelName 
+func (s *Slog) SetTimeStampLevelName(l string) error {
+	var err error
+
+	l = strings.ToLower(strings.TrimSpace(l))
+	if s.IsTimeStampLevelName(l) {
+		s.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
+	} else {
+		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
+		s.Errorv(err, Vars{
+			KEY_VALUE: l,
+			KEY_NAME: s.FormatName()})
+	}
+
+	return err
+}
+
+// TimeFormat 
+func (s *Slog) TimeFormat() string {
+	return s.format.get().Time.Format
+}
+
+// SetTimeFormat 
+func (s *Slog) SetTimeFormat(f string) {
+	s.format.set(func(format *Formatter) {
+		format.Time.Format = f
+	})
+}
+
+// Panic 
+func (s *Slog) Panic(e error) {
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Panicv 
+func (s *Slog) Panicv(e error, v Vars) {
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Panicf 
+func (s *Slog) Panicf(e error, i ...interface{}) {
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Panicln 
+func (s *Slog) Panicln(i ...interface{}) {
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Fatal 
+func (s *Slog) Fatal(e error) {
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Fatalv 
+func (s *Slog) Fatalv(e error, v Vars) {
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Fatalf 
+func (s *Slog) Fatalf(e error, i ...interface{}) {
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Fatalln 
+func (s *Slog) Fatalln(i ...interface{}) {
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Error 
+func (s *Slog) Error(e error) {
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
+	}
+}
+
+// Errorv 
+func (s *Slog) Errorv(e error, v Vars) {
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
+	}
+}
+
+// Errorf 
+func (s *Slog) Errorf(e error, i ...interface{}) {
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
+	}
+}
+
+// Errorln 
+func (s *Slog) Errorln(i ...interface{}) {
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Warn 
+func (s *Slog) Warn(m string) {
+	if s.format.get().Level >= WARN_LEVEL {
+		s.write(newEntry(WARN_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Warnv 
+func (s *Slog) Warnv(m string, v Vars) {
+	if s.format.get().Level >= WARN_LEVEL {
+		s.write(newEntry(WARN_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Warnf 
+func (s *Slog) Warnf(m string, i ...interface{}) {
+	if s.format.get().Level >= WARN_LEVEL {
+		s.write(newEntry(WARN_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Warnln 
+func (s *Slog) Warnln(i ...interface{}) {
+	if s.format.get().Level >= WARN_LEVEL {
+		s.write(newEntry(WARN_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Info 
+func (s *Slog) Info(m string) {
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Infov 
+func (s *Slog) Infov(m string, v Vars) {
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Infof 
+func (s *Slog) Infof(m string, i ...interface{}) {
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Infoln 
+func (s *Slog) Infoln(i ...interface{}) {
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Debug 
+func (s *Slog) Debug(m string) {
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Debugv 
+func (s *Slog) Debugv(m string, v Vars) {
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Debugf 
+func (s *Slog) Debugf(m string, i ...interface{}) {
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Debugln 
+func (s *Slog) Debugln(i ...interface{}) {
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Trace 
+func (s *Slog) Trace(m string) {
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, nil))
+	}
+}
+
+// Tracev 
+func (s *Slog) Tracev(m string, v Vars) {
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, v))
+	}
+}
+
+// Tracef 
+func (s *Slog) Tracef(m string, i ...interface{}) {
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+	}
+}
+
+// Traceln 
+func (s *Slog) Traceln(i ...interface{}) {
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+	}
+}
+
+// Print 
+func (s *Slog) Print(m string) {
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, nil))
+}
+
+// Printv 
+func (s *Slog) Printv(m string, v Vars) {
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, v))
+}
+
+// Printf 
+func (s *Slog) Printf(m string, i ...interface{}) {
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
+}
+
+// Println 
+func (s *Slog) Println(i ...interface{}) {
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, sprintln(i...), nil))
+}
+
+// Close writes pending repeats of deduplication, handler is not closed
+func (s *Slog) Close() error {
+	s.format.flush()
+
+	return nil
+}
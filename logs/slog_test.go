@@ -0,0 +1,147 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"context"
+	"strings"
+	"testing"
+	"log/slog"
+	"encoding/json"
+)
+
+func TestSlogHandler(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerJSON(&Formatter{Level: INFO_LEVEL}, w))
+
+	logger := slog.New(NewSlogHandler(ls.Named("slog"))).With("a", 1).WithGroup("req").With("method", "GET")
+	logger.Info("request", "id", 7, slog.Group("user", "name", "joe"), slog.Group("empty"), slog.Group(EMPTY_STRING, "inline", true))
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("%v: %s", err, w.Bytes())
+	}
+	req, _ := m["req"].(map[string]interface{})
+	user, _ := req["user"].(map[string]interface{})
+	if m["a"] != float64(1) || m["logger"] != "slog" || m["msg"] != "request" || req["method"] != "GET" || req["id"] != float64(7) ||
+		req["inline"] != true || user["name"] != "joe" {
+		t.Errorf("unexpected record %s", w.Bytes())
+	}
+	if _, ok := req["empty"]; ok {
+		t.Errorf("empty group is written %s", w.Bytes())
+	}
+
+	w.Reset()
+	logger.Debug("debug")
+	if w.Len() != 0 {
+		t.Errorf("disabled level is written %s", w.Bytes())
+	}
+
+	tc, _ := ParseTraceparent(testTraceparent)
+	w.Reset()
+	logger.WarnContext(ContextWithTrace(context.Background(), tc), "warn")
+	m = nil
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("%v: %s", err, w.Bytes())
+	}
+	if m["level"] != "warn" || m["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("unexpected record %s", w.Bytes())
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	for _, test := range []struct {
+		level slog.Level
+		want  int
+	}{
+		{SLOG_LEVEL_TRACE, TRACE_LEVEL},
+		{slog.LevelDebug, DEBUG_LEVEL},
+		{slog.LevelInfo, INFO_LEVEL},
+		{slog.LevelInfo + 1, INFO_LEVEL},
+		{slog.LevelWarn, WARN_LEVEL},
+		{slog.LevelError, ERROR_LEVEL},
+		{SLOG_LEVEL_FATAL, FATAL_LEVEL},
+		{SLOG_LEVEL_PANIC, PANIC_LEVEL},
+	} {
+		if l := slogLevel(test.level); l != test.want {
+			t.Errorf("%s: got %d, want %d", test.level, l, test.want)
+		}
+	}
+
+	h := NewSlogHandler(newTestLogs(writerJSON(&Formatter{Level: WARN_LEVEL}, &bytes.Buffer{})))
+	if h.Enabled(context.Background(), slog.LevelInfo) || !h.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("unexpected enabled levels")
+	}
+}
+
+func TestSlog(t *testing.T) {
+	w := &bytes.Buffer{}
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: SLOG_LEVEL_TRACE})
+	s, err := NewSlog(&SlogSettings{Handler: handler}, &Formatter{Level: TRACE_LEVEL, Environment: "prod", Tag: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.Infov("request", Vars{"id": 7, "user": map[string]interface{}{"name": "joe"}})
+	var m map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("%v: %s", err, w.Bytes())
+	}
+	user, _ := m["user"].(map[string]interface{})
+	source, _ := m["source"].(map[string]interface{})
+	if m["level"] != "INFO" || m["msg"] != "request" || m["env"] != "prod" || m["tag"] != "api" || m["id"] != float64(7) || user["name"] != "joe" {
+		t.Errorf("unexpected record %s", w.Bytes())
+	}
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "slog_test.go") {
+		t.Errorf("unexpected source %s", w.Bytes())
+	}
+
+	for _, test := range []struct {
+		fn    func()
+		level string
+	}{
+		{func() { s.Trace("trace") }, "DEBUG-4"},
+		{func() { s.Errorv(errors.New("error"), nil) }, "ERROR"},
+		{func() { s.Print("print") }, "INFO"},
+	} {
+		w.Reset()
+		test.fn()
+		m = nil
+		if err := json.Unmarshal(w.Bytes(), &m); err != nil || m["level"] != test.level {
+			t.Errorf("unexpected record %s, want level %s", w.Bytes(), test.level)
+		}
+	}
+
+	w.Reset()
+	s, _ = NewSlog(&SlogSettings{Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelWarn})}, &Formatter{Level: TRACE_LEVEL})
+	s.Info("info")
+	if w.Len() != 0 {
+		t.Errorf("level disabled by handler is written %s", w.Bytes())
+	}
+
+	if _, err := NewSlog(nil, &Formatter{}); err == nil {
+		t.Error("missing handler is accepted")
+	}
+}
+
+func TestSlogBackend(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerJSON(&Formatter{Level: INFO_LEVEL}, &bytes.Buffer{}))
+	s, _ := NewSlog(&SlogSettings{Handler: slog.NewJSONHandler(w, nil)}, &Formatter{Level: INFO_LEVEL})
+	if err := ls.SetBackend(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := ls.SetBackend(nil); err == nil {
+		t.Error("nil backend is accepted")
+	}
+
+	ls.Named("legacy").Warnv("slow", Vars{"ms": 1500})
+	var m map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("%v: %s", err, w.Bytes())
+	}
+	if m["level"] != "WARN" || m["logger"] != "legacy" || m["ms"] != float64(1500) || ls.Format() != SLOG_FORMAT {
+		t.Errorf("unexpected record %s", w.Bytes())
+	}
+}
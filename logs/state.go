@@ -0,0 +1,106 @@
+package logs
+
+import (
+	"sync"
+	"strings"
+	"sync/atomic"
+)
+
+// formatState is copy-on-write holder of Formatter. Readers get immutable
+// snapshot without locks, writers clone it under mutex and publish the copy.
+type formatState struct {
+	mutex    *sync.Mutex
+	value    atomic.Pointer[Formatter]
+	dedup    *deduper
+	redactor *redactor
+}
+
+// newFormatState 
+func newFormatState(f *Formatter) *formatState {
+	s := &formatState{
+		mutex: &sync.Mutex{},
+		dedup: newDeduper(f.Dedup),
+	}
+	// Settings are checked by constructors of loggers
+	s.redactor, _ = newRedactor(f.Redact)
+	s.value.Store(f)
+
+	return s
+}
+
+// get returns current snapshot, it must not be changed
+func (s *formatState) get() *Formatter {
+	return s.value.Load()
+}
+
+// set applies fn to the copy of current snapshot and publishes it
+func (s *formatState) set(fn func(format *Formatter)) {
+	s.mutex.Lock()
+	format := cloneFormatter(s.value.Load())
+	fn(format)
+	s.value.Store(format)
+	s.mutex.Unlock()
+}
+
+// write redacts entry, splits its message by lines when policy of format
+// is split and records every non-empty part which is not repeated within
+// deduplication window. Panic and fatal records are never split, because
+// writing of their first part stops the program.
+func (s *formatState) write(r recorder, e *Entry) {
+	format := s.get()
+	if s.redactor != nil {
+		s.redactor.apply(format, e)
+	}
+
+	if format.Multiline == MULTILINE_SPLIT && e.Level > FATAL_LEVEL && strings.IndexByte(e.Message, '\n') >= 0 {
+		message, rest, isRecorded := EMPTY_STRING, e.Message, false
+		for isFound := true; isFound; {
+			message, rest, isFound = strings.Cut(rest, "\n")
+			e.Message = strings.TrimSuffix(message, "\r")
+			if e.Message != EMPTY_STRING || !isFound && !isRecorded {
+				s.record(r, format, e)
+				isRecorded = true
+			}
+		}
+	} else {
+		s.record(r, format, e)
+	}
+}
+
+// record passes entry to recorder unless it is dropped by deduplication
+func (s *formatState) record(r recorder, format *Formatter, e *Entry) {
+	if s.dedup == nil || !s.dedup.isRepeated(r, format, e) {
+		r.record(e)
+	}
+}
+
+// flush writes pending repeats of deduplication
+func (s *formatState) flush() {
+	if s.dedup != nil {
+		s.dedup.close()
+	}
+}
+
+// cloneFormatter copies Formatter with its mutable blocks, writers are shared
+func cloneFormatter(f *Formatter) *Formatter {
+	format := *f
+
+	if f.Labels != nil {
+		labels := *f.Labels
+		format.Labels = &labels
+	}
+	if f.Time != nil {
+		t := *f.Time
+		format.Time = &t
+	}
+	if f.Keys != nil {
+		keys := *f.Keys
+		if f.Keys.Names != nil {
+			names := *f.Keys.Names
+			keys.Names = &names
+		}
+		format.Keys = &keys
+	}
+
+	return &format
+}
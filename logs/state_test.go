@@ -0,0 +1,270 @@
+package logs
+
+import (
+	"io"
+	"log"
+	"sync"
+	"time"
+	"errors"
+	"testing"
+	"sync/atomic"
+
+	"bctrader/logs/logstest/receivers"
+)
+
+// discardText 
+func discardText(f *Formatter) *Text {
+	return writerText(f, io.Discard, true)
+}
+
+// writerText 
+func writerText(f *Formatter, w io.Writer, isColorize bool) *Text {
+	defaultFormatter(f, false, false)
+
+	return &Text{
+		newFormatState(f),
+		&TextSettings{IsColorize: isColorize},
+		log.New(w, EMPTY_STRING, 0),
+		log.New(w, EMPTY_STRING, 0),
+		nil,
+		textColors,
+		isColorize,
+	}
+}
+
+// discardFMT 
+func discardFMT(f *Formatter) *FMT {
+	return writerFMT(f, io.Discard)
+}
+
+// writerFMT 
+func writerFMT(f *Formatter, w io.Writer) *FMT {
+	defaultFormatter(f, false, false)
+	f.Keys.Prefix = FMT_KEYS_PREFIX
+	f.Keys.PrefixSeparator = FMT_KEYS_PREFIX_SEPARATOR
+
+	return &FMT{
+		newFormatState(f),
+		&FMTSettings{},
+		log.New(w, EMPTY_STRING, 0),
+		log.New(w, EMPTY_STRING, 0),
+	}
+}
+
+// writerFluent 
+func writerFluent(f *Formatter, w io.Writer) *Fluent {
+	defaultFormatter(f, false, false)
+	f.Keys.Prefix = FLUENT_KEYS_PREFIX
+	f.Keys.PrefixSeparator = FLUENT_KEYS_PREFIX_SEPARATOR
+
+	return &Fluent{
+		newFormatState(f),
+		&FluentSettings{},
+		log.New(w, EMPTY_STRING, 0),
+		log.New(w, EMPTY_STRING, 0),
+	}
+}
+
+// discardJSON 
+func discardJSON(f *Formatter) *JSON {
+	return writerJSON(f, io.Discard)
+}
+
+// writerJSON 
+func writerJSON(f *Formatter, w io.Writer) *JSON {
+	defaultFormatter(f, false, false)
+	f.Keys.Prefix = JSON_KEYS_PREFIX
+	f.Keys.PrefixSeparator = JSON_KEYS_PREFIX_SEPARATOR
+
+	return &JSON{
+		newFormatState(f),
+		&JSONSettings{},
+		log.New(w, EMPTY_STRING, 0),
+		log.New(w, EMPTY_STRING, 0),
+	}
+}
+
+// discardFluent 
+func discardFluent(f *Formatter) *Fluent {
+	defaultFormatter(f, false, false)
+	f.Keys.Prefix = FLUENT_KEYS_PREFIX
+	f.Keys.PrefixSeparator = FLUENT_KEYS_PREFIX_SEPARATOR
+
+	return &Fluent{
+		newFormatState(f),
+		&FluentSettings{},
+		log.New(io.Discard, EMPTY_STRING, 0),
+		log.New(io.Discard, EMPTY_STRING, 0),
+	}
+}
+
+// newTestLogs wraps logger like New does
+func newTestLogs(l logger) *Logs {
+	return &Logs{
+		core: newLogsCore(l, newNamedLevels()),
+	}
+}
+
+// hammer runs setters and log calls of the logger concurrently
+func hammer(t *testing.T, l logger) {
+	const n = 200
+	wg := &sync.WaitGroup{}
+	err := errors.New("error")
+
+	run := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				fn(i)
+			}
+		}()
+	}
+
+	run(func(i int) { l.SetLevel(i % (TRACE_LEVEL + 1)) })
+	run(func(i int) { l.SetLevelName(levelNames[i%(TRACE_LEVEL+1)]) })
+	run(func(i int) { l.SetLabels(StrInt(i)) })
+	run(func(i int) { l.SetLabelsSeparator(LABELS_SEPARATOR) })
+	run(func(i int) { l.SetTag(StrInt(i)) })
+	run(func(i int) { l.SetEnvironment(StrInt(i)) })
+	run(func(i int) { l.SetTimeUTC(i%2 == 0) })
+	run(func(i int) { l.SetTimeStamp(i%2 == 0) })
+	run(func(i int) { l.SetTimeStampLevel(i % (TIME_STAMP_LEVEL_NANO + 1)) })
+	run(func(i int) { l.SetTimeFormat(TIME_FORMAT_RFC3339) })
+	for j := 0; j < 4; j++ {
+		run(func(i int) {
+			l.Infov("message", Vars{"i": i})
+			l.Errorf(err, i)
+			l.Debugln("message", i)
+			l.Print("message")
+			_ = l.Level()
+			_ = l.Tag()
+			_ = l.Labels()
+		})
+	}
+	wg.Wait()
+}
+
+func TestRaceText(t *testing.T) {
+	hammer(t, discardText(&Formatter{}))
+}
+
+func TestRaceFMT(t *testing.T) {
+	hammer(t, discardFMT(&Formatter{}))
+}
+
+func TestRaceJSON(t *testing.T) {
+	hammer(t, discardJSON(&Formatter{}))
+}
+
+func TestRaceFluent(t *testing.T) {
+	hammer(t, discardFluent(&Formatter{}))
+}
+
+func TestRaceGELF(t *testing.T) {
+	r, err := receivers.NewGELFUDPReceiver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	g, err := NewGELF(&GELFSettings{Connection: &Connection{URL: "udp://" + r.Addr()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	hammer(t, g)
+}
+
+func TestRaceSys(t *testing.T) {
+	r, err := receivers.NewSyslogReceiver(receivers.SYSLOG_NETWORK_TCP, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	s, err := NewSys(&SysSettings{Connection: &Connection{URL: "tcp://" + r.Addr()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	hammer(t, s)
+}
+
+// closedHook fails test when record is written after Close, records wait a
+// little, so logger is replaced while they are written
+type closedHook struct {
+	*Hook
+	isClosed *atomic.Bool
+}
+
+// newClosedHook 
+func newClosedHook(t *testing.T) *closedHook {
+	isClosed := &atomic.Bool{}
+	h, err := NewHook(&HookSettings{Func: func(e *Entry) {
+		time.Sleep(10 * time.Microsecond)
+		if isClosed.Load() {
+			t.Error("record is written by closed logger")
+		}
+	}}, &Formatter{Level: INFO_LEVEL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &closedHook{h, isClosed}
+}
+
+// Close 
+func (h *closedHook) Close() error {
+	h.isClosed.Store(true)
+
+	return h.Hook.Close()
+}
+
+func TestRaceLogs(t *testing.T) {
+	ls := &Logs{
+		core: newLogsCore(newClosedHook(t), newNamedLevels()),
+	}
+	named := ls.Named("storage")
+	wg := &sync.WaitGroup{}
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			var err error
+			switch i % 3 {
+			case 0:
+				err = ls.SetBackend(newClosedHook(t))
+			case 1:
+				err = ls.SetBackend(writerJSON(&Formatter{Level: INFO_LEVEL}, io.Discard))
+			default:
+				err = ls.SetBackend(writerFMT(&Formatter{Level: INFO_LEVEL}, io.Discard))
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		}
+		ls.SetBackend(discardText(&Formatter{}))
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			named.SetLevel(i % (TRACE_LEVEL + 1))
+			ls.SetNamedLevels("http=warn,storage.s3=debug")
+			ls.SetTag(StrInt(i))
+			ls.SetEnvironment(StrInt(i))
+		}
+	}()
+	for j := 0; j < 2; j++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				ls.Infov("message", Vars{"i": i})
+				named.Named("s3").Debugf("message %d", i)
+				named.Warnln("message", i)
+			}
+		}()
+	}
+	wg.Wait()
+}
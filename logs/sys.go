@@ -4,13 +4,12 @@ import (
 	"os"
 	"fmt"
 	"time"
-	"bytes"
 	"errors"
 	"strconv"
 	"strings"
 	"crypto/tls"
+	"unicode/utf8"
 
-	"github.com/go-logfmt/logfmt"
 	"bctrader/logs/syslog"
 //	syslog "github.com/RackSec/srslog"
 )
@@ -37,11 +36,32 @@ const (
 
 	__SYS_FORMATTER_UNIX_STRING    = "<%d>%s %s[%d]: %s"
 	__SYS_FORMATTER_RFC3164_STRING = "<%d>%s %s %s[%d]: %s"
-	__SYS_FORMATTER_RFC5424_STRING = "<%d>%d %s %s %s %d %s - %s"
+	__SYS_FORMATTER_RFC5424_STRING = "<%d>%d %s %s %s %d %s %s"
 
 	__SYS_FRAMER_RFC5425_FORMAT = "%d %s"
 )
 
+// Framing of messages of stream transports by RFC 6587, octet counting is
+// default of TCP and TLS. Non-transparent framing terminates message by
+// trailer, so trailer in message is replaced by space.
+const (
+	SYS_FRAMING_OCTET_COUNTING  = "octet-counting"
+	SYS_FRAMING_NON_TRANSPARENT = "non-transparent"
+	SYS_FRAMING_NONE            = "none"
+	SYS_FRAMING_TRAILER_LF      = "lf"
+	SYS_FRAMING_TRAILER_NUL     = "nul"
+)
+
+// Structured data of RFC 5424, SD-IDs are qualified by private enterprise
+// number
+const (
+	SYS_SD_NIL             = "-"
+	SYS_SD_ID_TRACE        = "trace"
+	SYS_SD_ID_ENTRY        = "logs"
+	SYS_SD_ENTERPRISE_ID   = "32473"
+	SYS_SD_NAME_MAX_LENGTH = int(32)
+)
+
 // 
 const (
 	SYS_KEYS_PREFIX           = KEY_FIELDS
@@ -59,25 +79,41 @@ type SysTime struct {
 	Level int  `json:"stamp_level" yaml:"stamp_level" xml:"stamp_level" toml:"stamp_level"`
 }
 
-// SysSettings 
+// SysSettings configures syslog writer, EnterpriseID qualifies SD-IDs of
+// RFC 5424 structured data and SYS_SD_ENTERPRISE_ID is used when it is empty.
+// SDID is SD-ID of element of labels, environment, tag, logger and fields,
+// it is qualified by EnterpriseID unless it has own as "name@number" and
+// SYS_SD_ID_ENTRY is used when it is empty. Framing is framing of stream
+// transport and FramingTrailer is trailer of non-transparent framing,
+// SYS_FRAMING_TRAILER_LF is used when it is empty.
 type SysSettings struct {
-	Connection *Connection `json:"connection" yaml:"connection" xml:"connection" toml:"connection"`
-	Hostname   string      `json:"hostname" yaml:"hostname" xml:"hostname" toml:"hostname"`
-	Facility   string      `json:"facility" yaml:"facility" xml:"facility" toml:"facility"`
-	AppName    string      `json:"app_name" yaml:"app_name" xml:"app_name" toml:"app_name"`
-	Format     string      `json:"format" yaml:"format" xml:"format" toml:"format"`
-	Tag        string      `json:"tag" yaml:"tag" xml:"tag" toml:"tag"`
-	TCP        *SysTCP     `json:"tcp" yaml:"tcp" xml:"tcp" toml:"tcp"`
-	Time       *SysTime    `json:"time" yaml:"time" xml:"time" toml:"time"`
+	Connection     *Connection `json:"connection" yaml:"connection" xml:"connection" toml:"connection"`
+	Hostname       string      `json:"hostname" yaml:"hostname" xml:"hostname" toml:"hostname"`
+	Facility       string      `json:"facility" yaml:"facility" xml:"facility" toml:"facility"`
+	AppName        string      `json:"app_name" yaml:"app_name" xml:"app_name" toml:"app_name"`
+	Format         string      `json:"format" yaml:"format" xml:"format" toml:"format"`
+	Tag            string      `json:"tag" yaml:"tag" xml:"tag" toml:"tag"`
+	TCP            *SysTCP     `json:"tcp" yaml:"tcp" xml:"tcp" toml:"tcp"`
+	Time           *SysTime    `json:"time" yaml:"time" xml:"time" toml:"time"`
+	EnterpriseID   string      `json:"enterprise_id" yaml:"enterprise_id" xml:"enterprise_id" toml:"enterprise_id"`
+	SDID           string      `json:"sd_id" yaml:"sd_id" xml:"sd_id" toml:"sd_id"`
+	Framing        string      `json:"framing" yaml:"framing" xml:"framing" toml:"framing"`
+	FramingTrailer string      `json:"framing_trailer" yaml:"framing_trailer" xml:"framing_trailer" toml:"framing_trailer"`
 }
 
 // Sys 
 type Sys struct {
-	format   *Formatter
+	format   *formatState
 	settings *SysSettings
 	writer   *syslog.Writer
 }
 
+// sysFramingTrailers are trailers of non-transparent framing by names
+var sysFramingTrailers = map[string]string{
+	SYS_FRAMING_TRAILER_LF:  "\n",
+	SYS_FRAMING_TRAILER_NUL: "\x00",
+}
+
 var sysFacilities = map[string]syslog.Priority{
 	"kern":     syslog.LOG_KERN,
 	"user":     syslog.LOG_USER,
@@ -117,6 +153,12 @@ var sysLevels = []syslog.Priority{
 func sysSettingsCheck(s *SysSettings) error {
 	err := SocketConnection(s.Connection, SYS_DEFAULT_PORT_UDP, SYS_DEFAULT_PORT_TCP)
 
+	if err == nil {
+		err = sysSDIDCheck(s)
+	}
+	if err == nil {
+		err = sysFramingCheck(s)
+	}
 	if err == nil && s.Hostname == EMPTY_STRING {
 		s.Hostname, err = os.Hostname()
 	}
@@ -124,6 +166,90 @@ func sysSettingsCheck(s *SysSettings) error {
 	return err
 }
 
+// sysSDIDCheck checks enterprise id and SD-ID of entry, qualified SD-ID is
+// SD-NAME, "@" and enterprise id as digits separated by dots
+func sysSDIDCheck(s *SysSettings) error {
+	if !isSysEnterpriseID(s.EnterpriseID) && s.EnterpriseID != EMPTY_STRING {
+		return errors.New("Invalid syslog enterprise id")
+	}
+	if s.SDID == EMPTY_STRING {
+		return nil
+	}
+
+	name, number, ok := strings.Cut(s.SDID, "@")
+	if !ok {
+		number = s.EnterpriseID
+		if number == EMPTY_STRING {
+			number = SYS_SD_ENTERPRISE_ID
+		}
+	}
+	if name == EMPTY_STRING || len(name) + 1 + len(number) > SYS_SD_NAME_MAX_LENGTH || !isSysEnterpriseID(number) {
+		return errors.New("Invalid syslog SD-ID")
+	}
+	for i := 0; i < len(name); i++ {
+		if !isSysSDNameByte(name[i]) {
+			return errors.New("Invalid syslog SD-ID")
+		}
+	}
+
+	return nil
+}
+
+// sysFramingCheck sets default framing of transport, TCP and TLS use octet
+// counting and other transports have none. Framing other than none is
+// valid on stream transport only.
+func sysFramingCheck(s *SysSettings) error {
+	isStream := false
+	switch s.Connection.Scheme {
+	case URL_SCHEME_TCP, URL_SCHEME_TCP_TLS, URL_SCHEME_UNIX:
+		isStream = true
+	}
+
+	switch s.Framing {
+	case EMPTY_STRING:
+		if s.Connection.Scheme == URL_SCHEME_TCP || s.Connection.Scheme == URL_SCHEME_TCP_TLS {
+			s.Framing = SYS_FRAMING_OCTET_COUNTING
+		} else {
+			s.Framing = SYS_FRAMING_NONE
+		}
+	case SYS_FRAMING_NONE:
+	case SYS_FRAMING_OCTET_COUNTING, SYS_FRAMING_NON_TRANSPARENT:
+		if !isStream {
+			return errors.New("Syslog framing is valid on stream transport only")
+		}
+	default:
+		return errors.New("Invalid syslog framing")
+	}
+
+	if s.FramingTrailer == EMPTY_STRING {
+		s.FramingTrailer = SYS_FRAMING_TRAILER_LF
+	}
+	if _, ok := sysFramingTrailers[s.FramingTrailer]; !ok {
+		return errors.New("Invalid syslog framing trailer")
+	}
+
+	return nil
+}
+
+// isSysEnterpriseID 
+func isSysEnterpriseID(s string) bool {
+	if s == EMPTY_STRING || s[0] == '.' || s[len(s) - 1] == '.' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && s[i] != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSysSDNameByte tells if byte is printable US-ASCII allowed by SD-NAME
+func isSysSDNameByte(c byte) bool {
+	return c > ' ' && c < 127 && c != '=' && c != ']' && c != '"' && c != '@'
+}
+
 // sysFacility 
 func sysFacility(f string) (syslog.Priority, error) {
 	if f == EMPTY_STRING {
@@ -277,21 +403,8 @@ func sysFormatterRFC5424TimeUTCMilli(p syslog.Priority, hostname, appName, tag,
 
 // sysFormatterRFC5424TimeMicro provides an RFC 5424 compliant message.
 func sysFormatterRFC5424TimeMicro(p syslog.Priority, hostname, appName, tag, m string) string {
-	r := &syslog.RFC5424{
-		Facility:          syslog.LOG_DAEMON,
-		Header:            &syslog.RFC5424Header{
-			Hostname:       hostname,
-			AppName:        appName,
-			MessageID:      tag,
-			TimestampIsUTC: true,
-			TimestampLevel: "micro",
-		},
-		StructuredData:    &syslog.RFC5424StructuredData{[]*syslog.RFC5424Data{&syslog.RFC5424Data{ID: "test", Params: syslog.RFC5424DataParams{"aarg1": 123, "arg2": "ssssss"}}}},
-		StructuredDataIDs: &syslog.RFC5424DataIDs{},
-	}
-	return r.String(p, m)
-//	return fmt.Sprintf(__SYS_FORMATTER_RFC5424_STRING,
-//		p, 1, time.Now().Format(TIME_FORMAT_RFC3339_MICRO), hostname, sysAppName(appName), os.Getpid(), tag, m)
+	return fmt.Sprintf(__SYS_FORMATTER_RFC5424_STRING,
+		p, 1, time.Now().Format(TIME_FORMAT_RFC3339_MICRO), hostname, sysAppName(appName), os.Getpid(), tag, m)
 }
 
 // sysFormatterRFC5424TimeUTCMicro provides an RFC 5424 compliant message.
@@ -305,15 +418,27 @@ func sysFramerDefault(s string) string {
 	return s
 }
 
-// sysFramerRFC5425 
+// sysFramerRFC5425 prefixes message by its length, it is octet counting of
+// RFC 6587
 func sysFramerRFC5425(s string) string {
 	return fmt.Sprintf(__SYS_FRAMER_RFC5425_FORMAT, len(s), s)
 }
 
+// sysFramerNonTransparent returns framer which terminates message by trailer,
+// trailer in message is replaced by space, so receiver does not split it
+func sysFramerNonTransparent(trailer string) syslog.Framer {
+	return func(s string) string {
+		return strings.ReplaceAll(s, trailer, SPACE_STRING) + trailer
+	}
+}
+
 // sysFramer 
-func sysFramer(scheme string) syslog.Framer {
-	if scheme == URL_SCHEME_TCP_TLS {
+func sysFramer(s *SysSettings) syslog.Framer {
+	switch s.Framing {
+	case SYS_FRAMING_OCTET_COUNTING:
 		return sysFramerRFC5425
+	case SYS_FRAMING_NON_TRANSPARENT:
+		return sysFramerNonTransparent(sysFramingTrailers[s.FramingTrailer])
 	}
 
 	return sysFramerDefault
@@ -434,7 +559,7 @@ func sysWriter(s *SysSettings) (*syslog.Writer, error) {
 			break
 		}
 		writer.SetFormatter(sysFormatter(s))
-		writer.SetFramer(sysFramer(s.Connection.Scheme))
+		writer.SetFramer(sysFramer(s))
 		writer.SetAppName(s.AppName)
 	}
 
@@ -479,13 +604,16 @@ func NewSys(s *SysSettings, f ...*Formatter) (*Sys, error) {
 	if err == nil {
 		err = sysSettingsCheck(settings)
 	}
+	if err == nil {
+		err = redactCheck(format.Redact)
+	}
 	if err == nil {
 		writer, err = sysWriter(settings)
 	}
 
 	if err == nil {
 		return &Sys{
-			format,
+			newFormatState(format),
 			settings,
 			writer,
 		}, nil
@@ -497,75 +625,307 @@ func NewSys(s *SysSettings, f ...*Formatter) (*Sys, error) {
 	}
 }
 
-// timeStamp 
-func (s *Sys) timeStamp(tt time.Time) int64 {
-	if s.format.Time.IsUTC {
-		tt = tt.UTC()
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (s *Sys) write(e *Entry) {
+	s.format.write(s, e)
+}
+
+// formatter returns current snapshot of Formatter
+func (s *Sys) formatter() *Formatter {
+	return s.format.get()
+}
+
+// isRFC5424 tells if messages have structured data
+func (s *Sys) isRFC5424() bool {
+	return s.settings.Format != SYS_FORMAT_UNIX && s.settings.Format != SYS_FORMAT_RFC3164
+}
+
+// appendStructuredData appends structured data of RFC 5424 message. Labels,
+// environment, tag, logger and fields are SD-ELEMENT of SDID as
+// [logs@32473 env="prod" user="bob"], trace context is SD-ELEMENT as
+// [trace@32473 trace_id="..." span_id="..." trace_flags="01"], entry without
+// them has nil value
+func (s *Sys) appendStructuredData(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	start := len(b)
+	b = s.appendEntryElement(b, format, e, keys)
+
+	if e.Trace.IsValid() {
+		b = append(b, '[')
+		b = s.appendSDID(b, SYS_SD_ID_TRACE)
+		for i, k := range [...]string{KEY_TRACE_ID, KEY_SPAN_ID, KEY_TRACE_FLAGS} {
+			b = append(b, ' ')
+			b = append(b, k...)
+			b = append(b, '=', '"')
+			b = e.Trace.appendPart(b, i)
+			b = append(b, '"')
+		}
+		b = append(b, ']')
+	}
+
+	if len(b) == start {
+		return append(b, SYS_SD_NIL...)
 	}
 
-	return timeStampLevel(s.format.Time.StampLevel, tt)
+	return b
 }
 
-// time 
-func (s *Sys) time(tt time.Time) string {
-	if s.format.Time.IsUTC {
-		tt = tt.UTC()
+// appendSDID appends SD-ID qualified by enterprise id unless it has own
+func (s *Sys) appendSDID(b []byte, id string) []byte {
+	b = append(b, id...)
+	if strings.IndexByte(id, '@') < 0 {
+		b = append(b, '@')
+		if s.settings.EnterpriseID != EMPTY_STRING {
+			b = append(b, s.settings.EnterpriseID...)
+		} else {
+			b = append(b, SYS_SD_ENTERPRISE_ID...)
+		}
 	}
 
-	return tt.Format(s.format.Time.Format)
+	return b
 }
 
-// build 
-func (s *Sys) build(l int, m string, v Vars) {
-	buffer := &bytes.Buffer{}
-	logFmt := logfmt.NewEncoder(buffer)
+// appendEntryElement appends SD-ELEMENT of labels, environment, tag, logger
+// and fields, element without parameters is skipped. Labels as "key=value"
+// are parameters, other labels are joined under name of labels key.
+func (s *Sys) appendEntryElement(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	start := len(b)
+	b = append(b, '[')
+	if s.settings.SDID != EMPTY_STRING {
+		b = s.appendSDID(b, s.settings.SDID)
+	} else {
+		b = s.appendSDID(b, SYS_SD_ID_ENTRY)
+	}
+	open := len(b)
+
+	for _, label := range []struct {
+		key   string
+		value string
+	}{
+		{format.Keys.Names.Environment, format.Environment},
+		{format.Keys.Names.Tag, format.Tag},
+		{format.Keys.Names.Logger, e.Name},
+	} {
+		if label.value != EMPTY_STRING && !isOverwritten(format, e, label.key) {
+			b = appendSDParam(b, nil, label.key, label.value)
+		}
+	}
+	if format.Labels.String != EMPTY_STRING && !isOverwritten(format, e, format.Keys.Names.Labels) {
+		var bare []string
+		for _, label := range strings.Split(format.Labels.String, format.Labels.Separator) {
+			label = strings.TrimSpace(label)
+			if key, value, ok := strings.Cut(label, ECS_LABEL_SEPARATOR); ok && key != EMPTY_STRING {
+				b = appendSDParam(b, nil, key, value)
+			} else if label != EMPTY_STRING {
+				bare = append(bare, label)
+			}
+		}
+		if len(bare) > 0 {
+			b = appendSDParam(b, nil, format.Keys.Names.Labels, strings.Join(bare, format.Labels.Separator))
+		}
+	}
 
-	if v != nil {
-		for key, value := range v {
-			switch key {
-			case s.format.Keys.Names.Level, s.format.Keys.Names.Labels, s.format.Keys.Names.Message, s.format.Keys.Names.Timestamp, s.format.Keys.Names.Time, s.format.Keys.Names.Environment, s.format.Keys.Names.Tag:
-				logFmt.EncodeKeyval(s.format.Keys.Prefix+s.format.Keys.PrefixSeparator+key, value)
-				delete(v, key)
-			default:
-				logFmt.EncodeKeyval(key, value)
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() && isKeptKey(format, f.Key) {
+			b = appendSDField(b, format, f.Key, &f)
+		}
+	}
+	for _, key := range keys {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() && isKeptKey(format, key) {
+			b = appendSDField(b, format, key, &f)
+		}
+	}
+
+	if len(b) == open {
+		return b[:start]
+	}
+
+	return append(b, ']')
+}
+
+// appendSDField appends resolved field as SD-PARAM, objects are flattened to
+// dotted names
+func appendSDField(b []byte, format *Formatter, k string, f *Field) []byte {
+	if f.Type == FIELD_TYPE_OBJECT {
+		fields := objectFields(nil, f)
+		for i := 0; i < len(fields); i++ {
+			if !fields[i].isSkipped() {
+				b = appendSDField(b, format, k+OBJECT_KEY_SEPARATOR+fields[i].Key, &fields[i])
 			}
 		}
-		v = nil
+		return b
 	}
 
-	if s.format.Time.IsStamp {
-		logFmt.EncodeKeyval(s.format.Keys.Names.Timestamp, s.timeStamp(time.Now()))
-	} else {
-		logFmt.EncodeKeyval(s.format.Keys.Names.Time, s.time(time.Now()))
+	b, ok := appendSDName(b, format, k)
+	if !ok {
+		return b
+	}
+	value := len(b)
+	b = appendTextField(b, format, f)
+	b = escapeSDValue(b, value)
+
+	return append(b, '"')
+}
+
+// appendSDParam appends SD-PARAM of string value, name is prefixed by format
+// when it is set
+func appendSDParam(b []byte, format *Formatter, k string, v string) []byte {
+	b, ok := appendSDName(b, format, k)
+	if !ok {
+		return b
+	}
+	value := len(b)
+	b = append(b, v...)
+	b = escapeSDValue(b, value)
+
+	return append(b, '"')
+}
+
+// appendSDName appends space, SD-NAME and opening of value. Prefix is added
+// to reserved key, bytes which are not allowed by SD-NAME are dropped and
+// name is truncated to SYS_SD_NAME_MAX_LENGTH, empty name is skipped.
+func appendSDName(b []byte, format *Formatter, k string) ([]byte, bool) {
+	start := len(b)
+	b = append(b, ' ')
+	name := len(b)
+
+	if format != nil && isPrefixedKey(format, k) {
+		b = appendSDNameBody(b, name, format.Keys.Prefix)
+		b = appendSDNameBody(b, name, format.Keys.PrefixSeparator)
 	}
-	if l < PRINT_LEVEL {
-		logFmt.EncodeKeyval(s.format.Keys.Names.Level, levelNames[l])
+	b = appendSDNameBody(b, name, k)
+	if len(b) == name {
+		return b[:start], false
 	}
-	if s.format.Environment != EMPTY_STRING {
-		logFmt.EncodeKeyval(s.format.Keys.Names.Environment, s.format.Environment)
+
+	return append(b, '=', '"'), true
+}
+
+// appendSDNameBody appends allowed bytes of name until it is full
+func appendSDNameBody(b []byte, start int, s string) []byte {
+	for i := 0; i < len(s) && len(b) - start < SYS_SD_NAME_MAX_LENGTH; i++ {
+		if isSysSDNameByte(s[i]) {
+			b = append(b, s[i])
+		}
 	}
-	if s.format.Tag != EMPTY_STRING {
-		logFmt.EncodeKeyval(s.format.Keys.Names.Tag, s.format.Tag)
+
+	return b
+}
+
+// escapeSDValue escapes quote, backslash and ']' of PARAM-VALUE appended
+// from start by backslash, control characters are escaped like by text
+// format
+func escapeSDValue(b []byte, start int) []byte {
+	n := 0
+	for i := start; i < len(b); i++ {
+		n += sdEscapeSize(b[i]) - 1
+	}
+	if n == 0 {
+		return b
+	}
+
+	end := len(b)
+	b = append(b, make([]byte, n)...)
+	for i, j := end - 1, len(b); i >= start; i-- {
+		c := b[i]
+		switch sdEscapeSize(c) {
+		case 1:
+			j--
+			b[j] = c
+		case 2:
+			j -= 2
+			b[j], b[j + 1] = '\\', c
+			if c == '\n' {
+				b[j + 1] = 'n'
+			} else if c == '\r' {
+				b[j + 1] = 'r'
+			}
+		default:
+			j -= 6
+			copy(b[j:], []byte{'\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf]})
+		}
 	}
-	if s.format.Labels.String != EMPTY_STRING {
-		logFmt.EncodeKeyval(s.format.Keys.Names.Labels, s.format.Labels.String)
+
+	return b
+}
+
+// sdEscapeSize returns size of escaped byte of PARAM-VALUE
+func sdEscapeSize(c byte) int {
+	switch {
+	case c == '"' || c == '\\' || c == ']' || c == '\n' || c == '\r':
+		return 2
+	case c < 0x20 && c != '\t' || c == 0x7f:
+		return 6
 	}
-	logFmt.EncodeKeyval(s.format.Keys.Names.Message, m)
 
-	logFmt = nil
-	priority := (sysFacilities[s.settings.Facility] & syslog.FACILITY_MASK) | (sysLevels[l] & syslog.SEVERITY_MASK)
-	_, err := s.writer.WriteWithPriority(priority, buffer.Bytes())
-	buffer = nil
-	priority = 0
+	return 1
+}
+
+// appendSysMessage appends MSG, control characters are escaped like by text
+// format, so message can not forge records or terminal sequences
+func appendSysMessage(b []byte, s string) []byte {
+	start := 0
 
-	if err != nil && s.format.Stderr.IsPrintable {
-		switch l {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != 0x7f || c == '\t' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r >= 0x80 && r <= 0x9f || r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+			start = i + size
+		}
+		i += size
+	}
+
+	return append(b, s[start:]...)
+}
+
+// record encodes entry into pooled buffer, syslog writer sends it by one
+// call. RFC 5424 message has fields as structured data and message as MSG
+// with escaped control characters, other formats have logfmt entry.
+func (s *Sys) record(e *Entry) {
+	format := s.format.get()
+	buffer := newBuffer()
+	if s.isRFC5424() {
+		buffer.bytes = s.appendStructuredData(buffer.bytes, format, e, buffer.sortKeys(e.Vars))
+		if e.Message != EMPTY_STRING && !isOverwritten(format, e, format.Keys.Names.Message) {
+			buffer.bytes = append(buffer.bytes, ' ')
+			buffer.bytes = appendSysMessage(buffer.bytes, e.Message)
+		}
+	} else {
+		buffer.bytes = appendLogfmtEntry(buffer.bytes, format, e, buffer.sortKeys(e.Vars))
+	}
+	priority := (sysFacilities[s.settings.Facility] & syslog.FACILITY_MASK) | (sysLevels[e.Level] & syslog.SEVERITY_MASK)
+	_, err := s.writer.WriteWithPriority(priority, buffer.bytes)
+	buffer.free()
+
+	if err != nil && format.Stderr.IsPrintable {
+		switch e.Level {
 		case PANIC_LEVEL:
-			s.format.Stderr.Logger.Panic(err.Error())
+			format.Stderr.Logger.Panic(err.Error())
 		case FATAL_LEVEL:
-			s.format.Stderr.Logger.Fatal(err.Error())
+			format.Stderr.Logger.Fatal(err.Error())
 		default:
-			s.format.Stderr.Logger.Print(err.Error())
+			format.Stderr.Logger.Print(err.Error())
 		}
 	}
 }
@@ -587,7 +947,7 @@ func (s *Sys) Levels() []int {
 
 // Level 
 func (s *Sys) Level() int {
-	return s.format.Level
+	return s.format.get().Level
 }
 
 // IsLevel 
@@ -600,7 +960,9 @@ func (s *Sys) SetLevel(l int) error {
 	var err error
 
 	if s.IsLevel(l) {
-		s.format.Level = l
+		s.format.set(func(format *Formatter) {
+			format.Level = l
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL)
 		s.Errorv(err, Vars{
@@ -618,7 +980,7 @@ func (s *Sys) LevelNames() []string {
 
 // LevelName 
 func (s *Sys) LevelName() string {
-	return levelNames[s.format.Level]
+	return levelNames[s.format.get().Level]
 }
 
 // IsLevelName 
@@ -632,7 +994,9 @@ func (s *Sys) SetLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if s.IsLevelName(l) {
-		s.format.Level = sliceIndex(levelNames, l)
+		s.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL_NAME)
 		s.Errorv(err, Vars{
@@ -645,74 +1009,86 @@ func (s *Sys) SetLevelName(l string) error {
 
 // Labels 
 func (s *Sys) Labels() string {
-	return s.format.Labels.String
+	return s.format.get().Labels.String
 }
 
 // SetLabels 
 func (s *Sys) SetLabels(l string) {
-	s.format.Labels.String = l
+	s.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
 }
 
 // LabelsSeparator 
 func (s *Sys) LabelsSeparator() string {
-	return s.format.Labels.Separator
+	return s.format.get().Labels.Separator
 }
 
 // SetLabelsSeparator 
 func (s *Sys) SetLabelsSeparator(spr string) {
-	s.format.Labels.Separator = spr
+	s.format.set(func(format *Formatter) {
+		format.Labels.Separator = spr
+	})
 }
 
 // LabelsToString 
 func (s *Sys) LabelsToString(l []string) string {
-	return strings.Join(l, s.format.Labels.Separator)
+	return strings.Join(l, s.format.get().Labels.Separator)
 }
 
 // LabelsToSlice 
 func (s *Sys) LabelsToSlice(l string) []string {
-	return strings.Split(l, s.format.Labels.Separator)
+	return strings.Split(l, s.format.get().Labels.Separator)
 }
 
 // Environment 
 func (s *Sys) Environment() string {
-	return s.format.Environment
+	return s.format.get().Environment
 }
 
 // SetEnvironment 
 func (s *Sys) SetEnvironment(e string) {
-	s.format.Environment = strings.TrimSpace(e)
+	s.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
 }
 
 // Tag 
 func (s *Sys) Tag() string {
-	return s.format.Tag
+	return s.format.get().Tag
 }
 
 // SetTag 
 func (s *Sys) SetTag(t string) {
-	s.format.Tag = strings.TrimSpace(t)
+	s.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(t)
+	})
 }
 
 // IsTimeUTC 
 func (s *Sys) IsTimeUTC() bool {
-	return s.format.Time.IsUTC
+	return s.format.get().Time.IsUTC
 }
 
 // SetTimeUTC 
 func (s *Sys) SetTimeUTC(u bool) {
-	s.format.Time.IsUTC = u
+	s.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
 	s.writer.SetFormatter(sysFormatter(s.settings))
 }
 
 // IsTimeStamp 
 func (s *Sys) IsTimeStamp() bool {
-	return s.format.Time.IsStamp
+	return s.format.get().Time.IsStamp
 }
 
 // SetTimeStamp 
 func (s *Sys) SetTimeStamp(t bool) {
 	t = false
-	s.format.Time.IsStamp = t
+	s.format.set(func(format *Formatter) {
+		format.Time.IsStamp = t
+	})
 }
 
 // TimeStampLevels 
@@ -722,7 +1098,7 @@ func (s *Sys) TimeStampLevels() []int {
 
 // TimeStampLevel 
 func (s *Sys) TimeStampLevel() int {
-	return s.format.Time.StampLevel
+	return s.format.get().Time.StampLevel
 }
 
 // IsTimeStampLevel 
@@ -735,7 +1111,10 @@ func (s *Sys) SetTimeStampLevel(l int) error {
 	var err error
 
 	if s.IsTimeStampLevel(l) {
-		s.format.Time.StampLevel = l
+		s.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
 		s.writer.SetFormatter(sysFormatter(s.settings))
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
@@ -754,7 +1133,7 @@ func (s *Sys) TimeStampLevelNames() []string {
 
 // TimeStampLevelName 
 func (s *Sys) TimeStampLevelName() string {
-	return timeStampLevelNames[s.format.Level]
+	return timeStampLevelNames[s.format.get().Time.StampLevel]
 }
 
 // IsTimeStampLevelName 
@@ -768,7 +1147,10 @@ func (s *Sys) SetTimeStampLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if s.IsTimeStampLevelName(l) {
-		s.format.Level = sliceIndex(timeStampLevelNames, l)
+		s.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
 		s.writer.SetFormatter(sysFormatter(s.settings))
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
@@ -782,246 +1164,243 @@ func (s *Sys) SetTimeStampLevelName(l string) error {
 
 // TimeFormat 
 func (s *Sys) TimeFormat() string {
-	return s.format.Time.Format
+	return s.format.get().Time.Format
 }
 
 // SetTimeFormat 
 func (s *Sys) SetTimeFormat(f string) {
-	s.format.Time.Format = f
+	s.format.set(func(format *Formatter) {
+		format.Time.Format = f
+	})
 }
 
 // Panic 
 func (s *Sys) Panic(e error) {
-	if s.format.Level >= PANIC_LEVEL {
-		s.build(PANIC_LEVEL, e.Error(), nil)
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Panicv 
 func (s *Sys) Panicv(e error, v Vars) {
-	if s.format.Level >= PANIC_LEVEL {
-		s.build(PANIC_LEVEL, e.Error(), v)
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Panicf 
 func (s *Sys) Panicf(e error, i ...interface{}) {
-	if s.format.Level >= PANIC_LEVEL {
-		s.build(PANIC_LEVEL, fmt.Sprintf(e.Error(), i...), nil)
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Panicln 
 func (s *Sys) Panicln(i ...interface{}) {
-	if s.format.Level >= PANIC_LEVEL {
-		s.build(PANIC_LEVEL, fmt.Sprintln(i...), nil)
+	if s.format.get().Level >= PANIC_LEVEL {
+		s.write(newEntry(PANIC_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Fatal 
 func (s *Sys) Fatal(e error) {
-	if s.format.Level >= FATAL_LEVEL {
-		s.build(FATAL_LEVEL, e.Error(), nil)
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Fatalv 
 func (s *Sys) Fatalv(e error, v Vars) {
-	if s.format.Level >= FATAL_LEVEL {
-		s.build(FATAL_LEVEL, e.Error(), v)
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Fatalf 
 func (s *Sys) Fatalf(e error, i ...interface{}) {
-	if s.format.Level >= FATAL_LEVEL {
-		s.build(FATAL_LEVEL, fmt.Sprintf(e.Error(), i...), nil)
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Fatalln 
 func (s *Sys) Fatalln(i ...interface{}) {
-	if s.format.Level >= FATAL_LEVEL {
-		s.build(FATAL_LEVEL, fmt.Sprintln(i...), nil)
+	if s.format.get().Level >= FATAL_LEVEL {
+		s.write(newEntry(FATAL_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Error 
 func (s *Sys) Error(e error) {
-	if s.format.Level >= ERROR_LEVEL {
-		s.build(ERROR_LEVEL, e.Error(), nil)
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Errorv 
 func (s *Sys) Errorv(e error, v Vars) {
-	if s.format.Level >= ERROR_LEVEL {
-		s.build(ERROR_LEVEL, e.Error(), v)
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Errorf 
 func (s *Sys) Errorf(e error, i ...interface{}) {
-	if s.format.Level >= ERROR_LEVEL {
-		s.build(ERROR_LEVEL, fmt.Sprintf(e.Error(), i...), nil)
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, fmt.Sprintf(e.Error(), i...), nil))
 	}
 }
 
 // Errorln 
 func (s *Sys) Errorln(i ...interface{}) {
-	if s.format.Level >= ERROR_LEVEL {
-		s.build(ERROR_LEVEL, fmt.Sprintln(i...), nil)
+	if s.format.get().Level >= ERROR_LEVEL {
+		s.write(newEntry(ERROR_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Warn 
 func (g *Sys) Warn(s string) {
-	if g.format.Level >= WARN_LEVEL {
-		g.build(WARN_LEVEL, s, nil)
+	if g.format.get().Level >= WARN_LEVEL {
+		g.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Warnv 
 func (s *Sys) Warnv(m string, v Vars) {
-	if s.format.Level >= WARN_LEVEL {
-		s.build(WARN_LEVEL, m, v)
+	if s.format.get().Level >= WARN_LEVEL {
+		s.write(newEntry(WARN_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Warnf 
 func (s *Sys) Warnf(m string, i ...interface{}) {
-	if s.format.Level >= WARN_LEVEL {
-		s.build(WARN_LEVEL, fmt.Sprintf(m, i...), nil)
+	if s.format.get().Level >= WARN_LEVEL {
+		s.write(newEntry(WARN_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Warnln 
 func (s *Sys) Warnln(i ...interface{}) {
-	if s.format.Level >= WARN_LEVEL {
-		s.build(WARN_LEVEL, fmt.Sprintln(i...), nil)
+	if s.format.get().Level >= WARN_LEVEL {
+		s.write(newEntry(WARN_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Info 
 func (s *Sys) Info(m string) {
-	if s.format.Level >= INFO_LEVEL {
-		s.build(INFO_LEVEL, m, nil)
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, nil))
 	}
 }
 
 // Infov 
 func (s *Sys) Infov(m string, v Vars) {
-	if s.format.Level >= INFO_LEVEL {
-		s.build(INFO_LEVEL, m, v)
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Infof 
 func (s *Sys) Infof(m string, i ...interface{}) {
-	if s.format.Level >= INFO_LEVEL {
-		s.build(INFO_LEVEL, fmt.Sprintf(m, i...), nil)
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Infoln 
 func (s *Sys) Infoln(i ...interface{}) {
-	if s.format.Level >= INFO_LEVEL {
-		s.build(INFO_LEVEL, fmt.Sprintln(i...), nil)
+	if s.format.get().Level >= INFO_LEVEL {
+		s.write(newEntry(INFO_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Debug 
 func (s *Sys) Debug(m string) {
-	if s.format.Level >= DEBUG_LEVEL {
-		s.build(DEBUG_LEVEL, m, nil)
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, nil))
 	}
 }
 
 // Debugv 
 func (s *Sys) Debugv(m string, v Vars) {
-	if s.format.Level >= DEBUG_LEVEL {
-		s.build(DEBUG_LEVEL, m, v)
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Debugf 
 func (s *Sys) Debugf(m string, i ...interface{}) {
-	if s.format.Level >= DEBUG_LEVEL {
-		s.build(DEBUG_LEVEL, fmt.Sprintf(m, i...), nil)
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Debugln 
 func (s *Sys) Debugln(i ...interface{}) {
-	if s.format.Level >= DEBUG_LEVEL {
-		s.build(DEBUG_LEVEL, fmt.Sprintln(i...), nil)
+	if s.format.get().Level >= DEBUG_LEVEL {
+		s.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Trace 
 func (s *Sys) Trace(m string) {
-	if s.format.Level >= TRACE_LEVEL {
-		s.build(TRACE_LEVEL, m, nil)
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, nil))
 	}
 }
 
 // Tracev 
 func (s *Sys) Tracev(m string, v Vars) {
-	if s.format.Level >= TRACE_LEVEL {
-		s.build(TRACE_LEVEL, m, v)
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, m, v))
 	}
 }
 
 // Tracef 
 func (s *Sys) Tracef(m string, i ...interface{}) {
-	if s.format.Level >= TRACE_LEVEL {
-		s.build(TRACE_LEVEL, fmt.Sprintf(m, i...), nil)
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 	}
 }
 
 // Traceln 
 func (s *Sys) Traceln(i ...interface{}) {
-	if s.format.Level >= TRACE_LEVEL {
-		s.build(TRACE_LEVEL, fmt.Sprintln(i...), nil)
+	if s.format.get().Level >= TRACE_LEVEL {
+		s.write(newEntry(TRACE_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 	}
 }
 
 // Print 
 func (s *Sys) Print(m string) {
-	s.build(PRINT_LEVEL, m, nil)
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, nil))
 }
 
 // Printv 
 func (s *Sys) Printv(m string, v Vars) {
-	s.build(PRINT_LEVEL, m, v)
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, m, v))
 }
 
 // Printf 
 func (s *Sys) Printf(m string, i ...interface{}) {
-	s.build(PRINT_LEVEL, fmt.Sprintf(m, i...), nil)
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, fmt.Sprintf(m, i...), nil))
 }
 
 // Println 
 func (s *Sys) Println(i ...interface{}) {
-	s.build(PRINT_LEVEL, fmt.Sprintln(i...), nil)
+	s.write(newEntry(PRINT_LEVEL, EMPTY_STRING, sprintln(i...), nil))
 }
 
-// Close 
+// Close closes writer, state is kept for callers still holding logger
 func (s *Sys) Close() error {
 	var err error
 
-	if s != nil {
-		if s.writer != nil {
-			err = s.writer.Close()
-			if err != nil && s.format.Stderr.IsPrintable {
-				s.format.Stderr.Logger.Print(err.Error())
-				err = nil
-			}
-			s.writer = nil
+	if s != nil && s.writer != nil {
+		s.format.flush()
+		err = s.writer.Close()
+		if err != nil && s.format.get().Stderr.IsPrintable {
+			s.format.get().Stderr.Logger.Print(err.Error())
+			err = nil
 		}
-		s.format = nil
-		s.settings = nil
-		s = nil
 	}
 
 	return err
@@ -1,2 +1,165 @@
 package logs
 
+import (
+	"time"
+	"strings"
+	"testing"
+
+	"bctrader/logs/syslog"
+	"bctrader/logs/logstest/receivers"
+)
+
+func TestSysStructuredData(t *testing.T) {
+	format := &Formatter{Tag: "api", Environment: "prod", Labels: &Labels{"region=eu,canary", LABELS_SEPARATOR}}
+	defaultFormatter(format, false, true)
+	format.Keys.Prefix = SYS_KEYS_PREFIX
+	format.Keys.PrefixSeparator = SYS_KEYS_PREFIX_SEPARATOR
+
+	e := newEntry(INFO_LEVEL, "db", "query done", Vars{
+		"query": `a"b\c]d`,
+		"rows": 3,
+		"env": "field",
+		"bad key=\"]": true,
+		strings.Repeat("k", 40): "long",
+		"user": map[string]interface{}{"id": 7},
+		"note": "a\r\nb\x1b",
+	})
+	defer e.free()
+	e.Trace, _ = ParseTraceparent(testTraceparent)
+
+	buffer := newBuffer()
+	defer buffer.free()
+	s := &Sys{settings: &SysSettings{}}
+	sd := string(s.appendStructuredData(nil, format, e, buffer.sortKeys(e.Vars)))
+	if !strings.HasPrefix(sd, `[logs@32473 env="prod" tag="api" logger="db" region="eu" labels="canary" badkey="true" fields.env="field"`) || !strings.Contains(sd, ` query="a\"b\\c\]d" `) {
+		t.Errorf("unexpected structured data %s", sd)
+	}
+
+	m, err := receivers.ParseSyslog([]byte(sysFormatterRFC5424TimeUTC(syslog.LOG_INFO, "host", "app", "msgid", sd + " query done")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.StructuredData) != 2 || m.StructuredData[0].ID != "logs@32473" || m.StructuredData[1].ID != "trace@32473" || m.Message != "query done" {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	params := map[string]string{}
+	for _, p := range m.StructuredData[0].Params {
+		params[p.Name] = p.Value
+	}
+	for k, v := range map[string]string{
+		"env": "prod",
+		"tag": "api",
+		"logger": "db",
+		"region": "eu",
+		"labels": "canary",
+		"fields.env": "field",
+		"query": `a"b\c]d`,
+		"rows": "3",
+		"badkey": "true",
+		strings.Repeat("k", SYS_SD_NAME_MAX_LENGTH): "long",
+		"user.id": "7",
+		"note": `a\r\nb\u001b`,
+	} {
+		if params[k] != v {
+			t.Errorf("unexpected %s=%q of %+v", k, params[k], params)
+		}
+	}
+	if len(params) != 12 {
+		t.Errorf("unexpected params %+v", params)
+	}
+
+	s.settings = &SysSettings{SDID: "meta@123.4"}
+	e.Trace, e.Vars, e.Name = TraceContext{}, Vars{"a": 1}, EMPTY_STRING
+	format.Tag, format.Environment, format.Labels.String = EMPTY_STRING, EMPTY_STRING, EMPTY_STRING
+	if sd := string(s.appendStructuredData(nil, format, e, []string{"a"})); sd != `[meta@123.4 a="1"]` {
+		t.Errorf("unexpected structured data %s", sd)
+	}
+	if msg := string(appendSysMessage(nil, "a\r\n<13>1 forged\x1b]0;t\x07 \u0085 é")); msg != `a\r\n<13>1 forged\u001b]0;t\u0007 \u0085 é` {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestSysSDIDCheck(t *testing.T) {
+	for _, s := range []SysSettings{
+		{},
+		{SDID: "logs"},
+		{SDID: "logs", EnterpriseID: "32473.1"},
+		{SDID: "app@1"},
+	} {
+		if err := sysSDIDCheck(&s); err != nil {
+			t.Errorf("valid settings %+v: %v", s, err)
+		}
+	}
+	for _, s := range []SysSettings{
+		{EnterpriseID: "x"},
+		{EnterpriseID: "1."},
+		{SDID: "a b"},
+		{SDID: "a@"},
+		{SDID: "@1"},
+		{SDID: "a@b"},
+		{SDID: "a]"},
+		{SDID: strings.Repeat("a", 30)},
+	} {
+		if err := sysSDIDCheck(&s); err == nil {
+			t.Errorf("invalid settings %+v are accepted", s)
+		}
+	}
+}
+
+func TestSysFraming(t *testing.T) {
+	for _, c := range []struct {
+		settings SysSettings
+		framing  string
+	}{
+		{SysSettings{}, receivers.SYSLOG_FRAMING_OCTET_COUNTING},
+		{SysSettings{Framing: SYS_FRAMING_OCTET_COUNTING}, receivers.SYSLOG_FRAMING_OCTET_COUNTING},
+		{SysSettings{Framing: SYS_FRAMING_NON_TRANSPARENT}, receivers.SYSLOG_FRAMING_NON_TRANSPARENT},
+		{SysSettings{Framing: SYS_FRAMING_NON_TRANSPARENT, FramingTrailer: SYS_FRAMING_TRAILER_NUL}, receivers.SYSLOG_FRAMING_NON_TRANSPARENT},
+	} {
+		r, err := receivers.NewSyslogReceiver(receivers.SYSLOG_NETWORK_TCP, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c.settings.Connection = &Connection{URL: "tcp://" + r.Addr()}
+		c.settings.Format = SYS_FORMAT_RFC5424
+		s, err := NewSys(&c.settings, &Formatter{Level: INFO_LEVEL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Info("first line")
+		s.Info("second\nline")
+		s.Close()
+
+		messages, err := r.Wait(2, 5 * time.Second)
+		r.Close()
+		if err != nil {
+			t.Fatal(err, r.Errors())
+		}
+		for i, m := range messages {
+			if m.Framing != c.framing || m.Version != 1 || m.Message != [...]string{"first line", `second\nline`}[i] {
+				t.Errorf("unexpected message of %s framing %+v", c.settings.Framing, m)
+			}
+		}
+	}
+
+	for _, s := range []SysSettings{
+		{Connection: &Connection{Scheme: URL_SCHEME_UDP}, Framing: SYS_FRAMING_OCTET_COUNTING},
+		{Connection: &Connection{Scheme: URL_SCHEME_TCP}, Framing: "lf"},
+		{Connection: &Connection{Scheme: URL_SCHEME_TCP}, Framing: SYS_FRAMING_NON_TRANSPARENT, FramingTrailer: "crlf"},
+	} {
+		if err := sysFramingCheck(&s); err == nil {
+			t.Errorf("invalid framing %+v is accepted", s)
+		}
+	}
+	s := SysSettings{Connection: &Connection{Scheme: URL_SCHEME_UDP}}
+	if err := sysFramingCheck(&s); err != nil || s.Framing != SYS_FRAMING_NONE {
+		t.Errorf("unexpected framing of UDP %q: %v", s.Framing, err)
+	}
+	if framed := sysFramer(&SysSettings{Framing: SYS_FRAMING_NON_TRANSPARENT, FramingTrailer: SYS_FRAMING_TRAILER_NUL})("<14>1 - - - - - - m"); framed != "<14>1 - - - - - - m\x00" {
+		t.Errorf("unexpected frame %q", framed)
+	}
+	if framed := sysFramer(&SysSettings{Framing: SYS_FRAMING_NON_TRANSPARENT, FramingTrailer: SYS_FRAMING_TRAILER_LF})("<14>1 - - - - - - a\nb"); framed != "<14>1 - - - - - - a b\n" {
+		t.Errorf("unexpected frame %q", framed)
+	}
+}
@@ -2,11 +2,15 @@ package logs
 
 import (
 	"io"
+	"os"
 	"log"
 	"fmt"
-	"time"
 	"errors"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-isatty"
+	"github.com/mitchellh/colorstring"
 )
 
 // TEXT_NAME 
@@ -17,19 +21,61 @@ const (
 	TEXT_VARS_SEPARATOR = ":" + SPACE_STRING
 	TEXT_VAR_SEPARATOR  = LABELS_SEPARATOR + SPACE_STRING
 	TEXT_VAR_EQUALLY    = "="
+	TEXT_COLOR_RESET    = "\033[0m"
+	TEXT_COLOR_DIM      = "\033[2m"
+)
+
+// Modes of colorizing, auto mode colors records only when they are written
+// to terminals and honors NO_COLOR, FORCE_COLOR and TERM=dumb
+const (
+	TEXT_COLOR_AUTO   = int(0)
+	TEXT_COLOR_ALWAYS = int(1)
+	TEXT_COLOR_NEVER  = int(2)
+)
+
+// Variants of text format, pretty variant is developer console with aligned
+// columns, time since start of process and fields on separate lines
+const (
+	TEXT_VARIANT_COMPACT = int(0)
+	TEXT_VARIANT_PRETTY  = int(1)
 )
 
-// TextSettings 
+// Environment variables of colorizing
+const (
+	TEXT_ENV_NO_COLOR    = "NO_COLOR"
+	TEXT_ENV_FORCE_COLOR = "FORCE_COLOR"
+	TEXT_ENV_TERM        = "TERM"
+	TEXT_TERM_DUMB       = "dumb"
+)
+
+// Error string messages
+const (
+	__ERROR_STR_TEXT_COLORS = "Invalid text colors"
+)
+
+// TextSettings enables colorizing by IsColorize, ColorMode chooses when it is
+// applied. Colors replace colors of levels by names of colorstring, e.g.
+// {"info": "green", "meta": "dim"}, print and meta levels are colors of
+// message and separators. Layout is used by compact variant only. Writer
+// replaces stdout and stderr, e.g. by writer of tests.
 type TextSettings struct {
-	IsColorize bool `json:"is_colorize" yaml:"is_colorize" xml:"is_colorize" toml:"is_colorize"`
+	IsColorize bool              `json:"is_colorize" yaml:"is_colorize" xml:"is_colorize" toml:"is_colorize"`
+	ColorMode  int               `json:"color_mode" yaml:"color_mode" xml:"color_mode" toml:"color_mode"`
+	Colors     map[string]string `json:"colors" yaml:"colors" xml:"colors" toml:"colors"`
+	Variant    int               `json:"variant" yaml:"variant" xml:"variant" toml:"variant"`
+	Layout     string            `json:"layout" yaml:"layout" xml:"layout" toml:"layout"`
+	Writer     io.Writer         `json:"-" yaml:"-" xml:"-" toml:"-"`
 }
 
 // Text 
 type Text struct {
-	format   *Formatter
-	settings *TextSettings
-	stdout   *log.Logger
-	stderr   *log.Logger
+	format     *formatState
+	settings   *TextSettings
+	stdout     *log.Logger
+	stderr     *log.Logger
+	layout     []textSegment
+	colors     []string
+	isColorize bool
 }
 
 // textPrefix 
@@ -37,6 +83,24 @@ func textPrefix(level int) string {
 	return fmt.Sprintf("[%s]", strings.ToUpper(levelNames[level]))
 }
 
+// textColor is escape code of color from colors list
+func textColor(level int) string {
+	return "\033[" + colorstring.DefaultColors[strings.Trim(colors[level], "[]")] + "m"
+}
+
+// textColors 
+var textColors = []string{
+	textColor(PANIC_LEVEL),
+	textColor(FATAL_LEVEL),
+	textColor(ERROR_LEVEL),
+	textColor(WARN_LEVEL),
+	textColor(INFO_LEVEL),
+	textColor(DEBUG_LEVEL),
+	textColor(TRACE_LEVEL),
+	textColor(PRINT_LEVEL),
+	textColor(META_LEVEL),
+}
+
 // textPrefixes 
 var textPrefixes = []string{
 	textPrefix(PANIC_LEVEL),
@@ -49,6 +113,65 @@ var textPrefixes = []string{
 	EMPTY_STRING,
 }
 
+// textPalette returns escape codes of levels with replaced colors
+func textPalette(c map[string]string) ([]string, error) {
+	palette := append([]string(nil), textColors...)
+
+	for level, color := range c {
+		l := sliceIndex(levelNames, strings.ToLower(level))
+		if strings.ToLower(level) == "meta" {
+			l = META_LEVEL
+		}
+		code, ok := colorstring.DefaultColors[strings.ToLower(color)]
+		if l < 0 || !ok {
+			return nil, errors.New(__ERROR_STR_TEXT_COLORS + ": " + level + "=" + color)
+		}
+		palette[l] = "\033[" + code + "m"
+	}
+
+	return palette, nil
+}
+
+// textIsTerminal 
+func textIsTerminal(w io.Writer) bool {
+	if f, ok := w.(*os.File); ok {
+		return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+
+	return false
+}
+
+// textIsColorize resolves colorizing of settings. Auto mode colors records
+// only when both streams are terminals, so escape codes never reach files
+// and pipes. NO_COLOR disables colors, FORCE_COLOR enables them unless it is
+// "0" or "false".
+func textIsColorize(s *TextSettings, stdout io.Writer, stderr io.Writer) bool {
+	if !s.IsColorize {
+		return false
+	}
+
+	switch s.ColorMode {
+	case TEXT_COLOR_ALWAYS:
+		return true
+	case TEXT_COLOR_NEVER:
+		return false
+	}
+
+	force, isForced := os.LookupEnv(TEXT_ENV_FORCE_COLOR)
+	switch {
+	case isForced && (force == "0" || strings.ToLower(force) == "false"):
+		return false
+	case os.Getenv(TEXT_ENV_NO_COLOR) != EMPTY_STRING:
+		return false
+	case isForced:
+		return true
+	case os.Getenv(TEXT_ENV_TERM) == TEXT_TERM_DUMB:
+		return false
+	}
+
+	return textIsTerminal(stdout) && textIsTerminal(stderr)
+}
+
 // NewText 
 func NewText(s *TextSettings, f ...*Formatter) (*Text, error) {
 	var (
@@ -71,120 +194,297 @@ func NewText(s *TextSettings, f ...*Formatter) (*Text, error) {
 	}
 	defaultFormatter(format, false, true)
 
-	newOE(&stdout, &stderr, settings.IsColorize)
-
-	return &Text{
-		format,
-		settings,
-		newSystemLogger(stdout),
-		newSystemLogger(stderr),
-	}, nil
+	isColorize := textIsColorize(settings, os.Stdout, os.Stderr)
+	newOE(&stdout, &stderr, isColorize)
+	if settings.Writer != nil {
+		stdout, stderr = settings.Writer, settings.Writer
+		isColorize = textIsColorize(settings, stdout, stderr)
+	}
+	layout, err := newTextLayout(settings.Layout)
+	palette := textColors
+	if err == nil && settings.Colors != nil {
+		palette, err = textPalette(settings.Colors)
+	}
+	if err == nil {
+		err = redactCheck(format.Redact)
+	}
+
+	if err == nil {
+		return &Text{
+			newFormatState(format),
+			settings,
+			newSystemLogger(stdout),
+			newSystemLogger(stderr),
+			layout,
+			palette,
+			isColorize,
+		}, nil
+	} else {
+		if format.Stderr.IsPrintable {
+			format.Stderr.Logger.Print(err.Error())
+		}
+		return nil, err
+	}
 }
 
-// time 
-func (t *Text) time(tt time.Time) string {
-	var tTime string
-
-	if t.format.Time.IsUTC {
-		tt = tt.UTC()
+// colorString wraps string by color of level and reset code like
+// ColorString does, but by palette of logger
+func (t *Text) colorString(l int, s string) string {
+	if t.isColorize && l >= PANIC_LEVEL && l <= META_LEVEL {
+		s = t.colors[l] + s + TEXT_COLOR_RESET
 	}
 
-	if t.format.Time.IsStamp {
-		tTime = timeStampLevelStr(t.format.Time.StampLevel, tt)
-	} else {
-		tTime = tt.Format(t.format.Time.Format)
+	return s
+}
+
+// params 
+func (t *Text) params(l int, v ...interface{}) []interface{} {
+	for i := 0; i < len(v); i++ {
+		switch v[i].(type) {
+		case string:
+			v[i] = t.colorString(l, v[i].(string))
+		}
 	}
 
-	return SPACE_STRING + tTime
+	return v
 }
 
-// labels 
-func (t *Text) labels() string {
-	var tLabels string
+// words 
+func (t *Text) words(l int, s string) string {
+	list := strings.Split(s, SPACE_STRING)
 
-	if len(t.format.Labels.String) > 0 {
-		tLabels = SPACE_STRING + t.format.Labels.String
+	if len(list) > 1 {
+		for i := 0; i < len(list); i++ {
+			list[i] = t.colorString(l, list[i])
+		}
+
+		s = strings.Join(list, t.colorString(l, SPACE_STRING))
 	}
+	list = nil
 
-	return tLabels
+	return s
 }
 
-// env 
-func (t *Text) env() string {
-	var e string
+// sprintf 
+func (t *Text) sprintf(l int, s string, i ...interface{}) string {
+	if l >= PRINT_LEVEL {
+		return fmt.Sprintf(s, i...)
+	}
+
+	return fmt.Sprintf(t.words(PRINT_LEVEL, s), t.params(l, i...)...)
+}
 
-	if len(t.format.Environment) > 0 {
-		e = SPACE_STRING + t.format.Environment
+// sprintln 
+func (t *Text) sprintln(l int, i ...interface{}) string {
+	if l >= PRINT_LEVEL {
+		return sprintln(i...)
 	}
 
-	return e
+	return sprintln(t.params(l, i...)...)
 }
 
-// tag 
-func (t *Text) tag() string {
-	var tt string
+// color appends escape code of level color
+func (t *Text) color(b []byte, l int) []byte {
+	if t.isColorize {
+		b = append(b, t.colors[l]...)
+	}
+
+	return b
+}
 
-	if len(t.format.Tag) > 0 {
-		tt = SPACE_STRING + t.format.Tag
+// reset appends escape code which resets color
+func (t *Text) reset(b []byte) []byte {
+	if t.isColorize {
+		b = append(b, TEXT_COLOR_RESET...)
 	}
 
-	return tt
+	return b
 }
 
-// vars 
-func (t *Text) vars(l int, vars Vars) string {
-	var s string
+// appendVar appends key colored by level and value
+func (t *Text) appendVar(b []byte, l int, k string) []byte {
+	b = t.reset(b)
+	b = t.color(b, l)
+	b = append(b, k...)
+	b = t.reset(b)
+	b = t.color(b, META_LEVEL)
+	b = append(b, TEXT_VAR_EQUALLY...)
+	b = t.reset(b)
 
-	if len(vars) > 0 {
-		s = ColorString(PRINT_LEVEL, TEXT_VARS_SEPARATOR, t.settings.IsColorize)
-		suffix := ColorString(PRINT_LEVEL, TEXT_VAR_SEPARATOR, t.settings.IsColorize)
-		equally := ColorString(META_LEVEL, TEXT_VAR_EQUALLY, t.settings.IsColorize)
-		var list []string
+	return t.color(b, PRINT_LEVEL)
+}
+
+// appendString appends message or value with neutralized control
+// characters, so they can not forge records or terminal output. New lines
+// follow multiline policy. Escape character is kept by colorizing only when
+// it starts color code of palette, because colored arguments of formatted
+// messages carry them, other escape sequences are escaped.
+func (t *Text) appendString(b []byte, format *Formatter, s string) []byte {
+	start := 0
 
-		for k, v := range vars {
-			list = append(list, ColorString(l, k, t.settings.IsColorize)+equally+ColorString(PRINT_LEVEL, fmt.Sprintf(STR_V, v), t.settings.IsColorize))
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != 0x7f || c == '\t' {
+				i++
+				continue
+			}
+			if n := t.paletteCode(s[i:]); n > 0 {
+				i += n
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch {
+			case c == '\n' && format.Multiline == MULTILINE_KEEP:
+				b = append(b, '\n')
+			case c == '\n' && format.Multiline == MULTILINE_INDENT:
+				b = append(b, '\n', '\t')
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
 		}
-		s = s + strings.Join(list, suffix)
 
-		list = nil
-		suffix = EMPTY_STRING
-		equally = EMPTY_STRING
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r >= 0x80 && r <= 0x9f || r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+			start = i + size
+		}
+		i += size
 	}
 
-	return s
+	return append(b, s[start:]...)
 }
 
-// params 
-func (t *Text) params(l int, v ...interface{}) []interface{} {
-	for i := 0; i < len(v); i++ {
-		switch v[i].(type) {
-		case string:
-			v[i] = ColorString(l, v[i].(string), t.settings.IsColorize)
+// paletteCode returns length of color code of palette which string starts
+// by, it is zero when colorizing is off
+func (t *Text) paletteCode(s string) int {
+	if !t.isColorize || s[0] != 0x1b {
+		return 0
+	}
+	if strings.HasPrefix(s, TEXT_COLOR_RESET) {
+		return len(TEXT_COLOR_RESET)
+	}
+	for _, code := range t.colors {
+		if strings.HasPrefix(s, code) {
+			return len(code)
 		}
 	}
 
-	return v
+	return 0
 }
 
-// words 
-func (t *Text) words(l int, s string) string {
-	list := strings.Split(s, SPACE_STRING)
-
-	if len(list) > 1 {
-		for i := 0; i < len(list); i++ {
-			list[i] = ColorString(l, list[i], t.settings.IsColorize)
+// appendField appends resolved field after separator, objects are flattened
+// to dotted keys
+func (t *Text) appendField(b []byte, format *Formatter, l int, separator string, k string, f *Field) ([]byte, string) {
+	if f.Type == FIELD_TYPE_OBJECT {
+		fields := objectFields(nil, f)
+		for i := 0; i < len(fields); i++ {
+			if !fields[i].isSkipped() {
+				b, separator = t.appendField(b, format, l, separator, k+OBJECT_KEY_SEPARATOR+fields[i].Key, &fields[i])
+			}
 		}
+		return b, separator
+	}
+	b = append(b, separator...)
+	b = t.appendVar(b, l, k)
+	switch f.Type {
+	case FIELD_TYPE_STRING:
+		b = t.appendString(b, format, f.String)
+	case FIELD_TYPE_ERROR:
+		b = t.appendString(b, format, f.Interface.(error).Error())
+	default:
+		b = appendTextField(b, format, f)
+	}
 
-		s = strings.Join(list, ColorString(l, SPACE_STRING, t.settings.IsColorize))
+	return b, TEXT_VAR_SEPARATOR
+}
+
+// appendFixed appends time, environment, labels, tag and logger name
+func (t *Text) appendFixed(b []byte, format *Formatter, e *Entry) []byte {
+	b = append(b, ' ')
+	if format.Time.IsStamp {
+		b = appendTimeStamp(b, format, e.Time)
+	} else {
+		b = appendTime(b, format, e.Time)
 	}
-	list = nil
+	b = appendTextWord(b, format.Environment)
+	b = appendTextWord(b, format.Labels.String)
+	b = appendTextWord(b, format.Tag)
 
-	return s
+	return appendTextWord(b, e.Name)
 }
 
-// build 
-func (t *Text) build(l int, s string) string {
-	return ColorString(l, textPrefixes[l], t.settings.IsColorize) + ColorString(PRINT_LEVEL, t.time(time.Now())+t.env()+t.labels()+t.tag()+SPACE_STRING+s, t.settings.IsColorize)
+// append appends entry as text line, fixed words go before message unless
+// they are placed last. Fields keep their order, vars are written in order
+// of keys.
+func (t *Text) append(b []byte, format *Formatter, e *Entry, keys []string) []byte {
+	isFirst := isKeysFirst(format, true)
+
+	if e.Level < PRINT_LEVEL {
+		b = t.color(b, e.Level)
+		b = append(b, textPrefixes[e.Level]...)
+		b = t.reset(b)
+	}
+	b = t.color(b, PRINT_LEVEL)
+	if isFirst {
+		b = t.appendFixed(b, format, e)
+	}
+	b = append(b, ' ')
+	b = t.appendString(b, format, e.Message)
+
+	separator := TEXT_VARS_SEPARATOR
+	for i := 0; i < len(e.Fields); i++ {
+		if f := e.Fields[i].resolve(); !f.isSkipped() {
+			b, separator = t.appendField(b, format, e.Level, separator, f.Key, &f)
+		}
+	}
+	for _, key := range keys {
+		if f := valueField(key, e.Vars[key], 0); !f.isSkipped() {
+			b, separator = t.appendField(b, format, e.Level, separator, key, &f)
+		}
+	}
+	for _, f := range traceFields(format, e) {
+		b, separator = t.appendField(b, format, e.Level, separator, f.Key, &f)
+	}
+	if !isFirst {
+		b = t.appendFixed(b, format, e)
+	}
+
+	return t.reset(b)
+}
+
+// write passes entry through redaction, multiline policy and deduplication
+// of backend
+func (t *Text) write(e *Entry) {
+	t.format.write(t, e)
+}
+
+// formatter returns current snapshot of Formatter
+func (t *Text) formatter() *Formatter {
+	return t.format.get()
+}
+
+// record encodes entry by variant or layout of settings into pooled buffer
+// and writes it by one call
+func (t *Text) record(e *Entry) {
+	buffer := newBuffer()
+	switch {
+	case t.settings.Variant == TEXT_VARIANT_PRETTY:
+		buffer.bytes = t.appendPretty(buffer.bytes, t.format.get(), e, buffer.sortKeys(e.Vars))
+	case t.layout != nil:
+		buffer.bytes = t.appendLayout(buffer.bytes, t.format.get(), e, buffer.sortKeys(e.Vars))
+	default:
+		buffer.bytes = t.append(buffer.bytes, t.format.get(), e, buffer.sortKeys(e.Vars))
+	}
+	writeRecord(e.Level, t.stdout, t.stderr, buffer)
 }
 
 // Format 
@@ -204,7 +504,7 @@ func (t *Text) Levels() []int {
 
 // Level 
 func (t *Text) Level() int {
-	return t.format.Level
+	return t.format.get().Level
 }
 
 // IsLevel 
@@ -217,7 +517,9 @@ func (t *Text) SetLevel(l int) error {
 	var err error
 
 	if t.IsLevel(l) {
-		t.format.Level = l
+		t.format.set(func(format *Formatter) {
+			format.Level = l
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL)
 		t.Errorv(err, Vars{
@@ -235,7 +537,7 @@ func (t *Text) LevelNames() []string {
 
 // LevelName 
 func (t *Text) LevelName() string {
-	return levelNames[t.format.Level]
+	return levelNames[t.format.get().Level]
 }
 
 // IsLevelName
@@ -249,7 +551,9 @@ func (t *Text) SetLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if t.IsLevelName(l) {
-		t.format.Level = sliceIndex(levelNames, l)
+		t.format.set(func(format *Formatter) {
+			format.Level = sliceIndex(levelNames, l)
+		})
 	} else {
 		err = errors.New(__ERROR_STR_LEVEL_NAME)
 		t.Errorv(err, Vars{
@@ -262,72 +566,84 @@ func (t *Text) SetLevelName(l string) error {
 
 // Labels 
 func (t *Text) Labels() string {
-	return t.format.Labels.String
+	return t.format.get().Labels.String
 }
 
 // SetLabels 
 func (t *Text) SetLabels(l string) {
-	t.format.Labels.String = l
+	t.format.set(func(format *Formatter) {
+		format.Labels.String = l
+	})
 }
 
 // LabelsSeparator 
 func (t *Text) LabelsSeparator() string {
-	return t.format.Labels.Separator
+	return t.format.get().Labels.Separator
 }
 
 // SetLabelsSeparator 
 func (t *Text) SetLabelsSeparator(s string) {
-	t.format.Labels.Separator = strings.TrimSpace(s)
+	t.format.set(func(format *Formatter) {
+		format.Labels.Separator = strings.TrimSpace(s)
+	})
 }
 
 // LabelsToString 
 func (t *Text) LabelsToString(l []string) string {
-	return strings.Join(l, t.format.Labels.Separator)
+	return strings.Join(l, t.format.get().Labels.Separator)
 }
 
 // LabelsToSlice 
 func (t *Text) LabelsToSlice(l string) []string {
-	return strings.Split(l, t.format.Labels.Separator)
+	return strings.Split(l, t.format.get().Labels.Separator)
 }
 
 // Environment 
 func (t *Text) Environment() string {
-	return t.format.Environment
+	return t.format.get().Environment
 }
 
 // SetEnvironment 
 func (t *Text) SetEnvironment(e string) {
-	t.format.Environment = strings.TrimSpace(e)
+	t.format.set(func(format *Formatter) {
+		format.Environment = strings.TrimSpace(e)
+	})
 }
 
 // Tag 
 func (t *Text) Tag() string {
-	return t.format.Tag
+	return t.format.get().Tag
 }
 
 // SetTag 
 func (t *Text) SetTag(s string) {
-	t.format.Tag = strings.TrimSpace(s)
+	t.format.set(func(format *Formatter) {
+		format.Tag = strings.TrimSpace(s)
+	})
 }
 
 // IsTimeUTC 
 func (t *Text) IsTimeUTC() bool {
-	return t.format.Time.IsUTC
+	return t.format.get().Time.IsUTC
 }
 
 // SetTimeUTC 
 func (t *Text) SetTimeUTC(u bool) {
-	t.format.Time.IsUTC = u
+	t.format.set(func(format *Formatter) {
+		format.Time.IsUTC = u
+	})
 }
 
 // IsTimeStamp 
 func (t *Text) IsTimeStamp() bool {
-	return t.format.Time.IsStamp
+	return t.format.get().Time.IsStamp
 }
 
 // SetTimeStamp 
 func (t *Text) SetTimeStamp(s bool) {
-	t.format.Time.IsStamp = s
+	t.format.set(func(format *Formatter) {
+		format.Time.IsStamp = s
+	})
 }
 
 // TimeStampLevels 
@@ -337,7 +653,7 @@ func (t *Text) TimeStampLevels() []int {
 
 // TimeStampLevel 
 func (t *Text) TimeStampLevel() int {
-	return t.format.Time.StampLevel
+	return t.format.get().Time.StampLevel
 }
 
 // IsTimeStampLevel 
@@ -350,7 +666,10 @@ func (t *Text) SetTimeStampLevel(l int) error {
 	var err error
 
 	if t.IsTimeStampLevel(l) {
-		t.format.Time.StampLevel = l
+		t.format.set(func(format *Formatter) {
+			format.Time.StampLevel = l
+			format.Time.StampLevelName = timeStampLevelNames[l]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL)
 		t.Errorv(err, Vars{
@@ -368,7 +687,7 @@ func (t *Text) TimeStampLevelNames() []string {
 
 // TimeStampLevelName 
 func (t *Text) TimeStampLevelName() string {
-	return timeStampLevelNames[t.format.Level]
+	return timeStampLevelNames[t.format.get().Time.StampLevel]
 }
 
 // IsTimeStampLevelName 
@@ -382,7 +701,10 @@ func (t *Text) SetTimeStampLevelName(l string) error {
 
 	l = strings.ToLower(strings.TrimSpace(l))
 	if t.IsTimeStampLevelName(l) {
-		t.format.Level = sliceIndex(timeStampLevelNames, l)
+		t.format.set(func(format *Formatter) {
+			format.Time.StampLevel = sliceIndex(timeStampLevelNames, l)
+			format.Time.StampLevelName = timeStampLevelNames[format.Time.StampLevel]
+		})
 	} else {
 		err = errors.New(__ERROR_STR_TIME_STAMP_LEVEL_NAME)
 		t.Errorv(err, Vars{
@@ -395,239 +717,235 @@ func (t *Text) SetTimeStampLevelName(l string) error {
 
 // TimeFormat 
 func (t *Text) TimeFormat() string {
-	return t.format.Time.Format
+	return t.format.get().Time.Format
 }
 
 // SetTimeFormat 
 func (t *Text) SetTimeFormat(f string) {
-	t.format.Time.Format = strings.TrimSpace(f)
+	t.format.set(func(format *Formatter) {
+		format.Time.Format = strings.TrimSpace(f)
+	})
 }
 
 // Panic 
 func (t *Text) Panic(e error) {
-	if t.format.Level >= PANIC_LEVEL {
-		t.stderr.Panic(t.build(PANIC_LEVEL, e.Error()))
+	if t.format.get().Level >= PANIC_LEVEL {
+		t.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Panicv 
 func (t *Text) Panicv(e error, v Vars) {
-	if t.format.Level >= PANIC_LEVEL {
-		t.stderr.Panic(t.build(PANIC_LEVEL, e.Error()+t.vars(PANIC_LEVEL, v)))
+	if t.format.get().Level >= PANIC_LEVEL {
+		t.write(newEntry(PANIC_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Panicf 
 func (t *Text) Panicf(e error, i ...interface{}) {
-	if t.format.Level >= PANIC_LEVEL {
-		t.stderr.Panic(t.build(PANIC_LEVEL, fmt.Sprintf(t.words(PRINT_LEVEL, e.Error()), t.params(PANIC_LEVEL, i...)...)))
+	if t.format.get().Level >= PANIC_LEVEL {
+		t.write(newEntry(PANIC_LEVEL, EMPTY_STRING, t.sprintf(PANIC_LEVEL, e.Error(), i...), nil))
 	}
 }
 
 // Panicln 
 func (t *Text) Panicln(i ...interface{}) {
-	if t.format.Level >= PANIC_LEVEL {
-		t.stderr.Panic(t.build(PANIC_LEVEL, fmt.Sprintln(t.params(PANIC_LEVEL, i...)...)))
+	if t.format.get().Level >= PANIC_LEVEL {
+		t.write(newEntry(PANIC_LEVEL, EMPTY_STRING, t.sprintln(PANIC_LEVEL, i...), nil))
 	}
 }
 
 // Fatal 
 func (t *Text) Fatal(e error) {
-	if t.format.Level >= FATAL_LEVEL {
-		t.stderr.Fatal(t.build(FATAL_LEVEL, e.Error()))
+	if t.format.get().Level >= FATAL_LEVEL {
+		t.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Fatalv 
 func (t *Text) Fatalv(e error, v Vars) {
-	if t.format.Level >= FATAL_LEVEL {
-		t.stderr.Fatal(t.build(FATAL_LEVEL, e.Error()+t.vars(FATAL_LEVEL, v)))
+	if t.format.get().Level >= FATAL_LEVEL {
+		t.write(newEntry(FATAL_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Fatalf 
 func (t *Text) Fatalf(e error, i ...interface{}) {
-	if t.format.Level >= FATAL_LEVEL {
-		t.stderr.Fatal(t.build(FATAL_LEVEL, fmt.Sprintf(t.words(PRINT_LEVEL, e.Error()), t.params(FATAL_LEVEL, i...)...)))
+	if t.format.get().Level >= FATAL_LEVEL {
+		t.write(newEntry(FATAL_LEVEL, EMPTY_STRING, t.sprintf(FATAL_LEVEL, e.Error(), i...), nil))
 	}
 }
 
 // Fatalln 
 func (t *Text) Fatalln(i ...interface{}) {
-	if t.format.Level >= FATAL_LEVEL {
-		t.stderr.Fatal(t.build(FATAL_LEVEL, fmt.Sprintln(t.params(FATAL_LEVEL, i...)...)))
+	if t.format.get().Level >= FATAL_LEVEL {
+		t.write(newEntry(FATAL_LEVEL, EMPTY_STRING, t.sprintln(FATAL_LEVEL, i...), nil))
 	}
 }
 
 // Error 
 func (t *Text) Error(e error) {
-	if t.format.Level >= ERROR_LEVEL {
-		t.stderr.Print(t.build(ERROR_LEVEL, e.Error()))
+	if t.format.get().Level >= ERROR_LEVEL {
+		t.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), nil))
 	}
 }
 
 // Errorv 
 func (t *Text) Errorv(e error, v Vars) {
-	if t.format.Level >= ERROR_LEVEL {
-		t.stderr.Print(t.build(ERROR_LEVEL, e.Error()+t.vars(ERROR_LEVEL, v)))
+	if t.format.get().Level >= ERROR_LEVEL {
+		t.write(newEntry(ERROR_LEVEL, EMPTY_STRING, e.Error(), v))
 	}
 }
 
 // Errorf 
 func (t *Text) Errorf(e error, i ...interface{}) {
-	if t.format.Level >= ERROR_LEVEL {
-		t.stderr.Print(t.build(ERROR_LEVEL, fmt.Sprintf(t.words(PRINT_LEVEL, e.Error()), t.params(ERROR_LEVEL, i...)...)))
+	if t.format.get().Level >= ERROR_LEVEL {
+		t.write(newEntry(ERROR_LEVEL, EMPTY_STRING, t.sprintf(ERROR_LEVEL, e.Error(), i...), nil))
 	}
 }
 
 // Errorln
 func (t *Text) Errorln(i ...interface{}) {
-	if t.format.Level >= ERROR_LEVEL {
-		t.stderr.Print(t.build(ERROR_LEVEL, fmt.Sprintln(t.params(ERROR_LEVEL, i...)...)))
+	if t.format.get().Level >= ERROR_LEVEL {
+		t.write(newEntry(ERROR_LEVEL, EMPTY_STRING, t.sprintln(ERROR_LEVEL, i...), nil))
 	}
 }
 
 // Warn 
 func (t *Text) Warn(s string) {
-	if t.format.Level >= WARN_LEVEL {
-		t.stdout.Print(t.build(WARN_LEVEL, s))
+	if t.format.get().Level >= WARN_LEVEL {
+		t.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Warnv 
 func (t *Text) Warnv(s string, v Vars) {
-	if t.format.Level >= WARN_LEVEL {
-		t.stdout.Print(t.build(WARN_LEVEL, s+t.vars(WARN_LEVEL, v)))
+	if t.format.get().Level >= WARN_LEVEL {
+		t.write(newEntry(WARN_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Warnf 
 func (t *Text) Warnf(s string, i ...interface{}) {
-	if t.format.Level >= WARN_LEVEL {
-		t.stdout.Print(t.build(WARN_LEVEL, fmt.Sprintf(t.words(PRINT_LEVEL, s), t.params(WARN_LEVEL, i...)...)))
+	if t.format.get().Level >= WARN_LEVEL {
+		t.write(newEntry(WARN_LEVEL, EMPTY_STRING, t.sprintf(WARN_LEVEL, s, i...), nil))
 	}
 }
 
 // Warnln 
 func (t *Text) Warnln(i ...interface{}) {
-	if t.format.Level >= WARN_LEVEL {
-		t.stdout.Print(t.build(WARN_LEVEL, fmt.Sprintln(t.params(WARN_LEVEL, i...)...)))
+	if t.format.get().Level >= WARN_LEVEL {
+		t.write(newEntry(WARN_LEVEL, EMPTY_STRING, t.sprintln(WARN_LEVEL, i...), nil))
 	}
 }
 
 // Info 
 func (t *Text) Info(s string) {
-	if t.format.Level >= INFO_LEVEL {
-		t.stdout.Print(t.build(INFO_LEVEL, s))
+	if t.format.get().Level >= INFO_LEVEL {
+		t.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Infov 
 func (t *Text) Infov(s string, v Vars) {
-	if t.format.Level >= INFO_LEVEL {
-		t.stdout.Print(t.build(INFO_LEVEL, s+t.vars(INFO_LEVEL, v)))
+	if t.format.get().Level >= INFO_LEVEL {
+		t.write(newEntry(INFO_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Infof 
 func (t *Text) Infof(s string, i ...interface{}) {
-	if t.format.Level >= INFO_LEVEL {
-		t.stdout.Print(t.build(INFO_LEVEL, fmt.Sprintf(t.words(PRINT_LEVEL, s), t.params(INFO_LEVEL, i...)...)))
+	if t.format.get().Level >= INFO_LEVEL {
+		t.write(newEntry(INFO_LEVEL, EMPTY_STRING, t.sprintf(INFO_LEVEL, s, i...), nil))
 	}
 }
 
 // Infoln 
 func (t *Text) Infoln(i ...interface{}) {
-	if t.format.Level >= INFO_LEVEL {
-		t.stdout.Print(t.build(INFO_LEVEL, fmt.Sprintln(t.params(INFO_LEVEL, i...)...)))
+	if t.format.get().Level >= INFO_LEVEL {
+		t.write(newEntry(INFO_LEVEL, EMPTY_STRING, t.sprintln(INFO_LEVEL, i...), nil))
 	}
 }
 
 // Debug 
 func (t *Text) Debug(s string) {
-	if t.format.Level >= DEBUG_LEVEL {
-		t.stdout.Print(t.build(DEBUG_LEVEL, s))
+	if t.format.get().Level >= DEBUG_LEVEL {
+		t.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Debugv 
 func (t *Text) Debugv(s string, v Vars) {
-	if t.format.Level >= DEBUG_LEVEL {
-		t.stdout.Print(t.build(DEBUG_LEVEL, s+t.vars(DEBUG_LEVEL, v)))
+	if t.format.get().Level >= DEBUG_LEVEL {
+		t.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Debugf 
 func (t *Text) Debugf(s string, i ...interface{}) {
-	if t.format.Level >= DEBUG_LEVEL {
-		t.stdout.Print(t.build(DEBUG_LEVEL, fmt.Sprintf(t.words(PRINT_LEVEL, s), t.params(DEBUG_LEVEL, i...)...)))
+	if t.format.get().Level >= DEBUG_LEVEL {
+		t.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, t.sprintf(DEBUG_LEVEL, s, i...), nil))
 	}
 }
 
 // Debugln 
 func (t *Text) Debugln(i ...interface{}) {
-	if t.format.Level >= DEBUG_LEVEL {
-		t.stdout.Print(t.build(DEBUG_LEVEL, fmt.Sprintln(t.params(DEBUG_LEVEL, i)...)))
+	if t.format.get().Level >= DEBUG_LEVEL {
+		t.write(newEntry(DEBUG_LEVEL, EMPTY_STRING, t.sprintln(DEBUG_LEVEL, i...), nil))
 	}
 }
 
 // Trace 
 func (t *Text) Trace(s string) {
-	if t.format.Level >= TRACE_LEVEL {
-		t.stdout.Print(t.build(TRACE_LEVEL, s))
+	if t.format.get().Level >= TRACE_LEVEL {
+		t.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, nil))
 	}
 }
 
 // Tracev 
 func (t *Text) Tracev(s string, v Vars) {
-	if t.format.Level >= TRACE_LEVEL {
-		t.stdout.Print(t.build(TRACE_LEVEL, s+t.vars(TRACE_LEVEL, v)))
+	if t.format.get().Level >= TRACE_LEVEL {
+		t.write(newEntry(TRACE_LEVEL, EMPTY_STRING, s, v))
 	}
 }
 
 // Tracef 
 func (t *Text) Tracef(s string, i ...interface{}) {
-	if t.format.Level >= TRACE_LEVEL {
-		t.stdout.Print(t.build(TRACE_LEVEL, fmt.Sprintf(t.words(PRINT_LEVEL, s), t.params(TRACE_LEVEL, i...)...)))
+	if t.format.get().Level >= TRACE_LEVEL {
+		t.write(newEntry(TRACE_LEVEL, EMPTY_STRING, t.sprintf(TRACE_LEVEL, s, i...), nil))
 	}
 }
 
 // Traceln 
 func (t *Text) Traceln(i ...interface{}) {
-	if t.format.Level >= TRACE_LEVEL {
-		t.stdout.Print(t.build(TRACE_LEVEL, fmt.Sprintln(t.params(TRACE_LEVEL, i...)...)))
+	if t.format.get().Level >= TRACE_LEVEL {
+		t.write(newEntry(TRACE_LEVEL, EMPTY_STRING, t.sprintln(TRACE_LEVEL, i...), nil))
 	}
 }
 
 // Print 
 func (t *Text) Print(s string) {
-	t.stdout.Print(t.build(PRINT_LEVEL, s))
+	t.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, nil))
 }
 
 // Printv 
 func (t *Text) Printv(s string, v Vars) {
-	t.stdout.Print(t.build(PRINT_LEVEL, s+t.vars(PRINT_LEVEL, v)))
+	t.write(newEntry(PRINT_LEVEL, EMPTY_STRING, s, v))
 }
 
 // Printf 
 func (t *Text) Printf(s string, i ...interface{}) {
-	t.stdout.Print(t.build(PRINT_LEVEL, fmt.Sprintf(s, i...)))
+	t.write(newEntry(PRINT_LEVEL, EMPTY_STRING, t.sprintf(PRINT_LEVEL, s, i...), nil))
 }
 
 // Println 
 func (t *Text) Println(i ...interface{}) {
-	t.stdout.Print(t.build(PRINT_LEVEL, fmt.Sprintln(i...)))
+	t.write(newEntry(PRINT_LEVEL, EMPTY_STRING, t.sprintln(PRINT_LEVEL, i...), nil))
 }
 
-// Close 
+// Close keeps state, because logger can still be used by concurrent callers
 func (t *Text) Close() error {
-	if t != nil {
-		t.format = nil
-		t.settings = nil
-		t.stdout = nil
-		t.stderr = nil
-		t = nil
-	}
+	t.format.flush()
 
 	return nil
 }
@@ -1,2 +1,213 @@
 package logs
 
+import (
+	"os"
+	"fmt"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTextWrite(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL, Environment: "env", Tag: "tag", Time: &Time{IsStamp: true, StampLevel: TIME_STAMP_LEVEL_DEFAULT}}, w, false))
+
+	ls.Named("http").Infow("message", String("k", "v"), Int("n", 1))
+	ls.Print("print")
+
+	lines := bytes.Split(bytes.TrimSuffix(w.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output %q", w.Bytes())
+	}
+	if s := string(lines[0]); !bytes.HasPrefix(lines[0], []byte("[INFO] ")) || !bytes.HasSuffix(lines[0], []byte(" env tag http message: k=v, n=1")) {
+		t.Errorf("unexpected line %q", s)
+	}
+	if s := string(lines[1]); s[0] != ' ' || !bytes.HasSuffix(lines[1], []byte(" env tag print")) {
+		t.Errorf("unexpected line %q", s)
+	}
+}
+
+func TestTextOrder(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL, Tag: "tag", Keys: &Keys{Position: KEYS_POSITION_LAST}}, w, false))
+
+	ls.Infov("message", Vars{"c": 3, "a": 1, "b": 2})
+
+	line := string(bytes.TrimSuffix(w.Bytes(), []byte("\n")))
+	if !bytes.HasPrefix(w.Bytes(), []byte("[INFO] message: a=1, b=2, c=3 ")) || !bytes.HasSuffix(w.Bytes(), []byte(" tag\n")) {
+		t.Errorf("unexpected line %q", line)
+	}
+}
+
+func TestTextMultiline(t *testing.T) {
+	for _, c := range []struct {
+		multiline int
+		lines     []string
+	}{
+		{MULTILINE_DEFAULT, []string{`[INFO] a\r\nb\u001b[31m: k=c\nd `}},
+		{MULTILINE_INDENT, []string{`[INFO] a\r`, "\tb\\u001b[31m: k=c", "\td "}},
+		{MULTILINE_KEEP, []string{`[INFO] a\r`, `b\u001b[31m: k=c`, `d `}},
+		{MULTILINE_SPLIT, []string{`[INFO] a: k=c\nd `, `[INFO] b\u001b[31m: k=c\nd `}},
+	} {
+		w := &bytes.Buffer{}
+		ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL, Multiline: c.multiline, Keys: &Keys{Position: KEYS_POSITION_LAST}}, w, false))
+
+		ls.Infow("a\r\nb\x1b[31m", String("k", "c\nd"))
+
+		lines := bytes.Split(bytes.TrimSuffix(w.Bytes(), []byte("\n")), []byte("\n"))
+		if len(lines) != len(c.lines) {
+			t.Fatalf("%d: unexpected output %q", c.multiline, w.Bytes())
+		}
+		for i := 0; i < len(lines); i++ {
+			if !bytes.HasPrefix(lines[i], []byte(c.lines[i])) {
+				t.Errorf("%d: unexpected line %q", c.multiline, lines[i])
+			}
+		}
+	}
+
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL, Multiline: MULTILINE_SPLIT, Keys: &Keys{Position: KEYS_POSITION_LAST}}, w, false))
+	ls.Infof("done\n")
+	ls.Printv("a\r\n\nb\n", Vars{"k": 1})
+	ls.Info("\n")
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "[INFO] done ") || !strings.HasPrefix(lines[1], " a: k=1 ") || !strings.HasPrefix(lines[2], " b: k=1 ") || !strings.HasPrefix(lines[3], "[INFO] ") {
+		t.Errorf("empty lines are split to records %q", lines)
+	}
+}
+
+func TestTextPrintln(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL}, w, false))
+
+	ls.Infoln("a", 1)
+	ls.Println("b")
+
+	lines := bytes.Split(bytes.TrimSuffix(w.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) != 2 || !bytes.HasSuffix(lines[0], []byte(" a 1")) || !bytes.HasSuffix(lines[1], []byte(" b")) {
+		t.Errorf("unexpected output %q", w.Bytes())
+	}
+}
+
+func TestTextLayout(t *testing.T) {
+	w := &bytes.Buffer{}
+	l := writerText(&Formatter{Level: INFO_LEVEL, Tag: "tag"}, w, false)
+	l.layout, _ = newTextLayout("{level:5|short}|{logger:>6.4}|{tag}|{caller}|{msg} {fields}")
+	ls := newTestLogs(l)
+
+	ls.Named("http-server").Infow("message", String("k", "v"), Int("n", 1))
+	ls.Print("print")
+
+	lines := bytes.Split(bytes.TrimSuffix(w.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output %q", w.Bytes())
+	}
+	if s := string(lines[0]); !strings.HasPrefix(s, "INF  |  http|tag|") || !strings.Contains(s, "/text_test.go:") || !strings.HasSuffix(s, "|message k=v, n=1") {
+		t.Errorf("unexpected line %q", s)
+	}
+	if s := string(lines[1]); !strings.HasPrefix(s, "     |      |tag|") || !strings.HasSuffix(s, "|print ") {
+		t.Errorf("unexpected line %q", s)
+	}
+}
+
+func TestTextLayoutInvalid(t *testing.T) {
+	for _, layout := range []string{"{unknown}", "{msg", "msg}", "{level:x}", "{msg:.0}", "{msg|short}", "{msg|nocolor}"} {
+		if _, err := newTextLayout(layout); err == nil {
+			t.Errorf("layout %q is accepted", layout)
+		}
+	}
+	if layout, err := newTextLayout("{{{msg|red}}}"); err != nil || len(layout) != 3 || layout[0].literal != "{" || layout[2].literal != "}" {
+		t.Errorf("unexpected layout %v: %v", layout, err)
+	}
+}
+
+func TestTextColorize(t *testing.T) {
+	w := &bytes.Buffer{}
+	t.Setenv(TEXT_ENV_NO_COLOR, EMPTY_STRING)
+	t.Setenv(TEXT_ENV_TERM, "xterm")
+	t.Setenv(TEXT_ENV_FORCE_COLOR, EMPTY_STRING)
+	os.Unsetenv(TEXT_ENV_FORCE_COLOR)
+
+	for _, c := range []struct {
+		settings   *TextSettings
+		env        []string
+		isColorize bool
+	}{
+		{&TextSettings{}, []string{TEXT_ENV_FORCE_COLOR, "1"}, false},
+		{&TextSettings{IsColorize: true, ColorMode: TEXT_COLOR_ALWAYS}, nil, true},
+		{&TextSettings{IsColorize: true, ColorMode: TEXT_COLOR_NEVER}, []string{TEXT_ENV_FORCE_COLOR, "1"}, false},
+		{&TextSettings{IsColorize: true}, nil, false},
+		{&TextSettings{IsColorize: true}, []string{TEXT_ENV_FORCE_COLOR, "1"}, true},
+		{&TextSettings{IsColorize: true}, []string{TEXT_ENV_FORCE_COLOR, "1", TEXT_ENV_TERM, TEXT_TERM_DUMB}, true},
+		{&TextSettings{IsColorize: true}, []string{TEXT_ENV_FORCE_COLOR, "0"}, false},
+		{&TextSettings{IsColorize: true}, []string{TEXT_ENV_FORCE_COLOR, "1", TEXT_ENV_NO_COLOR, "1"}, false},
+	} {
+		for i := 0; i < len(c.env); i += 2 {
+			os.Setenv(c.env[i], c.env[i+1])
+		}
+		if isColorize := textIsColorize(c.settings, w, w); isColorize != c.isColorize {
+			t.Errorf("%+v with %v: colorize is %t", c.settings, c.env, isColorize)
+		}
+		for i := 0; i < len(c.env); i += 2 {
+			os.Unsetenv(c.env[i])
+		}
+	}
+}
+
+func TestTextPalette(t *testing.T) {
+	palette, err := textPalette(map[string]string{"info": "green", "META": "dim"})
+	if err != nil || palette[INFO_LEVEL] != "\033[32m" || palette[META_LEVEL] != "\033[2m" || palette[ERROR_LEVEL] != textColors[ERROR_LEVEL] {
+		t.Errorf("unexpected palette %q: %v", palette, err)
+	}
+	for _, c := range []map[string]string{{"info": "unknown"}, {"unknown": "red"}} {
+		if _, err := textPalette(c); err == nil {
+			t.Errorf("colors %v are accepted", c)
+		}
+	}
+
+	w := &bytes.Buffer{}
+	l := writerText(&Formatter{Level: INFO_LEVEL}, w, true)
+	l.colors = palette
+	newTestLogs(l).Info("message")
+	if !bytes.HasPrefix(w.Bytes(), []byte("\033[32m[INFO]")) {
+		t.Errorf("unexpected output %q", w.Bytes())
+	}
+}
+
+func TestTextEscape(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerText(&Formatter{Level: INFO_LEVEL}, w, true))
+
+	ls.Infov("\x1b]0;title\x07", Vars{"k": "\x1b[2J"})
+	ls.Infof("user %s", "joe")
+	out := w.String()
+	if strings.Contains(out, "\x1b]") || strings.Contains(out, "\x1b[2J") || !strings.Contains(out, `\u001b]0;title\u0007`) || !strings.Contains(out, `\u001b[2J`) {
+		t.Errorf("escape sequences are not neutralized in %q", out)
+	}
+	if !strings.Contains(out, textColors[INFO_LEVEL] + "joe" + TEXT_COLOR_RESET) {
+		t.Errorf("colored argument is escaped in %q", out)
+	}
+}
+
+func TestTextPretty(t *testing.T) {
+	w := &bytes.Buffer{}
+	l := writerText(&Formatter{Level: INFO_LEVEL, Tag: "tag"}, w, false)
+	l.settings.Variant = TEXT_VARIANT_PRETTY
+	ls := newTestLogs(l).Named("http")
+
+	ls.Infow("message", String("k", "v"), Int("n", 1))
+	line := w.String()
+	if !strings.HasPrefix(line[PRETTY_TIME_WIDTH:], " INFO  http         message                                  k=v n=1 tag ") || !strings.Contains(line, "/text_test.go:") || strings.Count(line, "\n") != 1 {
+		t.Errorf("unexpected line %q", line)
+	}
+
+	w.Reset()
+	ls.Infow("failed", Err(fmt.Errorf("request: %w", errors.New("timeout"))), String("query", "select\n1"))
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0][PRETTY_TIME_WIDTH:], " INFO  http         failed tag ") ||
+		lines[1] != PRETTY_INDENT+"error = request: timeout" || lines[2] != PRETTY_INDENT+"        "+PRETTY_CAUSE+"timeout" ||
+		lines[3] != PRETTY_INDENT+"query = select" || lines[4] != PRETTY_INDENT+"        1" {
+		t.Errorf("unexpected lines %q", lines)
+	}
+}
@@ -0,0 +1,226 @@
+package logs
+
+import (
+	"errors"
+	"context"
+	"net/http"
+	"sync/atomic"
+	"encoding/hex"
+)
+
+// Keys of trace context
+const (
+	KEY_TRACE_ID    = "trace_id"
+	KEY_SPAN_ID     = "span_id"
+	KEY_TRACE_FLAGS = "trace_flags"
+)
+
+// W3C trace context
+const (
+	TRACE_HEADER       = "traceparent"
+	TRACE_VERSION      = "00"
+	TRACE_VERSION_MAX  = "ff"
+	TRACE_PARENT_SIZE  = int(55)
+	TRACE_FLAG_SAMPLED = byte(1)
+)
+
+// Error string messages
+const (
+	__ERROR_STR_TRACE_PARENT = "Invalid traceparent"
+)
+
+// TraceContext identifies span of trace which record belongs to
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+}
+
+// TraceExtractor returns trace context of span carried by context, e.g. of
+// OpenTelemetry:
+//
+//	logs.SetTraceExtractor(func(ctx context.Context) (logs.TraceContext, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return logs.TraceContext{
+//			TraceID: [16]byte(sc.TraceID()),
+//			SpanID:  [8]byte(sc.SpanID()),
+//			Flags:   byte(sc.TraceFlags()),
+//		}, sc.IsValid()
+//	})
+type TraceExtractor func(ctx context.Context) (TraceContext, bool)
+
+// traceContextKey is key of trace context stored by ContextWithTrace
+type traceContextKey struct{}
+
+// traceExtractor is extractor of tracer, it is asked before stored value
+var traceExtractor atomic.Pointer[TraceExtractor]
+
+// SetTraceExtractor sets extractor of tracer, nil drops it
+func SetTraceExtractor(fn TraceExtractor) {
+	if fn == nil {
+		traceExtractor.Store(nil)
+	} else {
+		traceExtractor.Store(&fn)
+	}
+}
+
+// ParseTraceparent parses W3C traceparent as "00-<trace id>-<span id>-<flags>",
+// fields added by later versions are ignored
+func ParseTraceparent(s string) (TraceContext, error) {
+	var t TraceContext
+
+	if len(s) < TRACE_PARENT_SIZE || s[2] != '-' || s[35] != '-' || s[52] != '-' || s[:2] == TRACE_VERSION_MAX ||
+		(s[:2] == TRACE_VERSION && len(s) != TRACE_PARENT_SIZE) || (len(s) > TRACE_PARENT_SIZE && s[TRACE_PARENT_SIZE] != '-') {
+		return t, errors.New(__ERROR_STR_TRACE_PARENT + ": " + s)
+	}
+
+	var version, flags [1]byte
+	for _, part := range []struct {
+		dst []byte
+		src string
+	}{
+		{version[:], s[:2]},
+		{t.TraceID[:], s[3:35]},
+		{t.SpanID[:], s[36:52]},
+		{flags[:], s[53:55]},
+	} {
+		if !isLowerHex(part.src) {
+			return TraceContext{}, errors.New(__ERROR_STR_TRACE_PARENT + ": " + s)
+		}
+		if _, err := hex.Decode(part.dst, []byte(part.src)); err != nil {
+			return TraceContext{}, errors.New(__ERROR_STR_TRACE_PARENT + ": " + s)
+		}
+	}
+	t.Flags = flags[0]
+	if !t.IsValid() {
+		return TraceContext{}, errors.New(__ERROR_STR_TRACE_PARENT + ": " + s)
+	}
+
+	return t, nil
+}
+
+// isLowerHex is true when s has lowercase hex digits only, traceparent
+// does not allow uppercase ones
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsValid is false when trace or span id is zero
+func (t TraceContext) IsValid() bool {
+	return t.TraceID != [16]byte{} && t.SpanID != [8]byte{}
+}
+
+// IsSampled tells if trace is recorded by caller
+func (t TraceContext) IsSampled() bool {
+	return t.Flags&TRACE_FLAG_SAMPLED != 0
+}
+
+// String returns trace context as traceparent
+func (t TraceContext) String() string {
+	b := append([]byte(TRACE_VERSION), '-')
+	b = t.appendPart(b, 0)
+	b = append(b, '-')
+	b = t.appendPart(b, 1)
+	b = append(b, '-')
+
+	return string(t.appendPart(b, 2))
+}
+
+// appendPart appends trace id, span id or flags by index as lower hex
+func (t *TraceContext) appendPart(b []byte, i int) []byte {
+	switch i {
+	case 0:
+		return hex.AppendEncode(b, t.TraceID[:])
+	case 1:
+		return hex.AppendEncode(b, t.SpanID[:])
+	}
+
+	return hex.AppendEncode(b, []byte{t.Flags})
+}
+
+// traceKeys are keys of trace id, span id and flags by format
+func traceKeys(format *Formatter) [3]string {
+	return [3]string{format.Keys.Names.TraceID, format.Keys.Names.SpanID, format.Keys.Names.TraceFlags}
+}
+
+// traceFields returns trace context of entry as string fields, entry without
+// trace has none
+func traceFields(format *Formatter, e *Entry) []Field {
+	if !e.Trace.IsValid() {
+		return nil
+	}
+
+	fields := make([]Field, 0, 3)
+	for i, k := range traceKeys(format) {
+		if !isOverwritten(format, e, k) {
+			fields = append(fields, String(k, string(e.Trace.appendPart(nil, i))))
+		}
+	}
+
+	return fields
+}
+
+// ContextWithTrace returns context carrying trace context
+func ContextWithTrace(ctx context.Context, t TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, t)
+}
+
+// ContextWithTraceparent returns context carrying parsed traceparent,
+// invalid traceparent leaves context as it is
+func ContextWithTraceparent(ctx context.Context, s string) context.Context {
+	if t, err := ParseTraceparent(s); err == nil {
+		return ContextWithTrace(ctx, t)
+	}
+
+	return ctx
+}
+
+// TraceFromContext returns trace context of span of tracer or stored by
+// ContextWithTrace
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	if fn := traceExtractor.Load(); fn != nil {
+		if t, ok := (*fn)(ctx); ok && t.IsValid() {
+			return t, true
+		}
+	}
+	t, ok := ctx.Value(traceContextKey{}).(TraceContext)
+
+	return t, ok && t.IsValid()
+}
+
+// TraceMiddleware stores traceparent header of request in its context when
+// no tracer has put span there
+func TraceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := TraceFromContext(r.Context()); !ok {
+			if s := r.Header.Get(TRACE_HEADER); s != EMPTY_STRING {
+				r = r.WithContext(ContextWithTraceparent(r.Context(), s))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WithContext returns logger whose records carry trace context of context,
+// logger is returned as it is when context has none
+func (ls *Logs) WithContext(ctx context.Context) *Logs {
+	t, ok := TraceFromContext(ctx)
+	if !ok {
+		return ls
+	}
+
+	return &Logs{
+		core:  ls.core,
+		name:  ls.name,
+		trace: t,
+	}
+}
@@ -0,0 +1,121 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"net/http"
+	"encoding/json"
+	"net/http/httptest"
+)
+
+// testTraceparent 
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceparent(t *testing.T) {
+	tc, err := ParseTraceparent(testTraceparent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tc.IsValid() || !tc.IsSampled() || tc.String() != testTraceparent {
+		t.Errorf("unexpected trace context %s", tc)
+	}
+	if _, err := ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra"); err != nil {
+		t.Errorf("later version is rejected: %v", err)
+	}
+
+	for _, s := range []string{
+		EMPTY_STRING,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00F067AA0BA902B7-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		if _, err := ParseTraceparent(s); err == nil {
+			t.Errorf("invalid traceparent %q is accepted", s)
+		}
+	}
+}
+
+func TestTraceMiddleware(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerJSON(&Formatter{Level: INFO_LEVEL}, w))
+
+	handler := TraceMiddleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ls.WithContext(r.Context()).Named("http").Info("request")
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(TRACE_HEADER, testTraceparent)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("%v: %s", err, w.Bytes())
+	}
+	if m["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || m["span_id"] != "00f067aa0ba902b7" || m["trace_flags"] != "01" || m["logger"] != "http" {
+		t.Errorf("unexpected record %s", w.Bytes())
+	}
+
+	w.Reset()
+	ls.WithContext(context.Background()).Info("request")
+	if strings.Contains(w.String(), "trace_id") {
+		t.Errorf("unexpected trace in %s", w.Bytes())
+	}
+}
+
+func TestTraceExtractor(t *testing.T) {
+	span, _ := ParseTraceparent(testTraceparent)
+	span.SpanID[0] = 1
+	SetTraceExtractor(func(ctx context.Context) (TraceContext, bool) {
+		return span, true
+	})
+	defer SetTraceExtractor(nil)
+
+	ctx := ContextWithTraceparent(context.Background(), testTraceparent)
+	if tc, ok := TraceFromContext(ctx); !ok || tc != span {
+		t.Errorf("span of tracer is not preferred: %s", tc)
+	}
+}
+
+func TestTraceFormats(t *testing.T) {
+	tc, _ := ParseTraceparent(testTraceparent)
+	w := &bytes.Buffer{}
+
+	ls := newTestLogs(writerFMT(&Formatter{Level: INFO_LEVEL}, w)).WithContext(ContextWithTrace(context.Background(), tc))
+	ls.Info("fmt")
+	if !strings.Contains(w.String(), "trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 trace_flags=01") {
+		t.Errorf("unexpected logfmt %s", w.Bytes())
+	}
+
+	w.Reset()
+	ls = newTestLogs(writerText(&Formatter{Level: INFO_LEVEL}, w, false)).WithContext(ContextWithTrace(context.Background(), tc))
+	ls.Info("text")
+	if !strings.Contains(w.String(), "trace_id=4bf92f3577b34da6a3ce929d0e0e4736") {
+		t.Errorf("unexpected text %s", w.Bytes())
+	}
+
+	format := &Formatter{}
+	defaultFormatter(format, false, true)
+	format.Keys.Prefix = GELF_KEYS_PREFIX
+	format.Keys.PrefixSeparator = GELF_KEYS_PREFIX_SEPARATOR
+	e := newEntry(INFO_LEVEL, EMPTY_STRING, "gelf", nil)
+	e.Trace = tc
+	defer e.free()
+	if extra := string((&GELF{}).appendExtra(nil, format, e, nil)); extra != `{"_trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","_span_id":"00f067aa0ba902b7","_trace_flags":"01"}` {
+		t.Errorf("unexpected GELF extra %s", extra)
+	}
+
+	sd := string((&Sys{settings: &SysSettings{}}).appendStructuredData(nil, format, e, nil))
+	if sd != `[trace@32473 trace_id="4bf92f3577b34da6a3ce929d0e0e4736" span_id="00f067aa0ba902b7" trace_flags="01"]` {
+		t.Errorf("unexpected structured data %s", sd)
+	}
+	e.Trace = TraceContext{}
+	if sd := string((&Sys{settings: &SysSettings{}}).appendStructuredData(nil, format, e, nil)); sd != SYS_SD_NIL {
+		t.Errorf("unexpected structured data %s", sd)
+	}
+}
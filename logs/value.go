@@ -0,0 +1,265 @@
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"reflect"
+	"strings"
+	"encoding"
+	"encoding/json"
+)
+
+// 
+const (
+	FIELD_TYPE_OBJECT    = int(10)
+	OBJECT_KEY_SEPARATOR = DOT_STRING
+	OBJECT_DEPTH_MAX     = int(16)
+)
+
+// LogMarshaler returns value which is logged instead of the marshaler, e.g.
+// masked or shortened copy
+type LogMarshaler interface {
+	MarshalLog() interface{}
+}
+
+// ObjectMarshaler adds fields of object to encoder. Object is kept nested by
+// JSON and flattened to dotted keys by other formats.
+type ObjectMarshaler interface {
+	MarshalLogObject(o ObjectEncoder)
+}
+
+// ObjectEncoder collects fields of ObjectMarshaler
+type ObjectEncoder interface {
+	Add(f ...Field)
+}
+
+// fieldsEncoder 
+type fieldsEncoder struct {
+	fields []Field
+}
+
+// Add 
+func (e *fieldsEncoder) Add(f ...Field) {
+	e.fields = append(e.fields, f...)
+}
+
+// Object 
+func Object(k string, v ObjectMarshaler) Field {
+	return Field{Key: k, Type: FIELD_TYPE_OBJECT, Interface: v}
+}
+
+// Types which keep their own rendering, so they are not encoded as objects
+var (
+	typeTime          = reflect.TypeOf(time.Time{})
+	typeError         = reflect.TypeOf((*error)(nil)).Elem()
+	typeStringer      = reflect.TypeOf((*interface{ String() string })(nil)).Elem()
+	typeTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// isObjectType is true for maps with string keys and plain structs
+func isObjectType(t reflect.Type) bool {
+	if t.Implements(typeError) || t.Implements(typeStringer) || t.Implements(typeTextMarshaler) || t.Implements(typeJSONMarshaler) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	case reflect.Struct:
+		return t != typeTime
+	case reflect.Pointer:
+		return t.Elem().Kind() == reflect.Struct && isObjectType(t.Elem())
+	}
+
+	return false
+}
+
+// isObject is true for ObjectMarshaler, maps with string keys and structs
+func isObject(v interface{}) bool {
+	switch v.(type) {
+	case ObjectMarshaler, Vars, map[string]interface{}:
+		return true
+	}
+
+	if isObjectType(reflect.TypeOf(v)) {
+		return isNotNil(v)
+	}
+
+	return false
+}
+
+// isNotNil is false for nil pointer in interface
+func isNotNil(v interface{}) bool {
+	r := reflect.ValueOf(v)
+
+	return r.Kind() != reflect.Pointer || !r.IsNil()
+}
+
+// valueField converts value of Vars or Any field to typed field, so every
+// format renders common types the same way. Depth of nested objects is kept
+// in Integer of object field.
+func valueField(k string, v interface{}, depth int) Field {
+	for i := 0; i < OBJECT_DEPTH_MAX; i++ {
+		if m, ok := v.(LogMarshaler); ok {
+			v = m.MarshalLog()
+		} else {
+			break
+		}
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return Any(k, nil)
+	case string:
+		return String(k, x)
+	case []byte:
+		return String(k, string(x))
+	case bool:
+		return Bool(k, x)
+	case int:
+		return Int64(k, int64(x))
+	case int8:
+		return Int64(k, int64(x))
+	case int16:
+		return Int64(k, int64(x))
+	case int32:
+		return Int64(k, int64(x))
+	case int64:
+		return Int64(k, x)
+	case uint:
+		return Uint64(k, uint64(x))
+	case uint8:
+		return Uint64(k, uint64(x))
+	case uint16:
+		return Uint64(k, uint64(x))
+	case uint32:
+		return Uint64(k, uint64(x))
+	case uint64:
+		return Uint64(k, x)
+	case float32:
+		return Float32(k, x)
+	case float64:
+		return Float64(k, x)
+	case time.Duration:
+		return Duration(k, x)
+	case time.Time:
+		return Datetime(k, x)
+	case error:
+		return NamedErr(k, x)
+	case fmt.Stringer:
+		if _, ok := x.(ObjectMarshaler); !ok && isNotNil(x) {
+			return String(k, x.String())
+		}
+	}
+
+	if depth < OBJECT_DEPTH_MAX && isObject(v) {
+		return Field{Key: k, Type: FIELD_TYPE_OBJECT, Integer: int64(depth), Interface: v}
+	}
+
+	return Any(k, v)
+}
+
+// resolve converts Any field to typed one
+func (f Field) resolve() Field {
+	if f.Type == FIELD_TYPE_ANY {
+		return valueField(f.Key, f.Interface, 0)
+	}
+
+	return f
+}
+
+// objectFields appends fields of object field, maps are written in order of
+// keys and structs in order of their fields
+func objectFields(fields []Field, f *Field) []Field {
+	depth := int(f.Integer) + 1
+	start := len(fields)
+
+	switch x := f.Interface.(type) {
+	case ObjectMarshaler:
+		e := &fieldsEncoder{fields: fields}
+		x.MarshalLogObject(e)
+		fields = e.fields
+		for i := start; i < len(fields); i++ {
+			if fields[i].Type == FIELD_TYPE_ANY {
+				fields[i] = valueField(fields[i].Key, fields[i].Interface, depth)
+			} else if fields[i].Type == FIELD_TYPE_OBJECT {
+				fields[i].Integer = int64(depth)
+			}
+		}
+		return fields
+	case Vars:
+		return mapFields(fields, x, depth)
+	case map[string]interface{}:
+		return mapFields(fields, x, depth)
+	}
+
+	r := reflect.ValueOf(f.Interface)
+	if r.Kind() == reflect.Pointer {
+		r = r.Elem()
+	}
+	switch r.Kind() {
+	case reflect.Map:
+		keys := r.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			fields = append(fields, valueField(key.String(), r.MapIndex(key).Interface(), depth))
+		}
+	case reflect.Struct:
+		t := r.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if name, isOmitEmpty, ok := structFieldName(t.Field(i)); ok && !(isOmitEmpty && r.Field(i).IsZero()) {
+				fields = append(fields, valueField(name, r.Field(i).Interface(), depth))
+			}
+		}
+	}
+
+	return fields
+}
+
+// mapFields 
+func mapFields(fields []Field, m map[string]interface{}, depth int) []Field {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fields = append(fields, valueField(key, m[key], depth))
+	}
+
+	return fields
+}
+
+// structFieldName returns key of exported struct field, json tag is used
+// when it is set
+func structFieldName(f reflect.StructField) (string, bool, bool) {
+	if !f.IsExported() {
+		return EMPTY_STRING, false, false
+	}
+
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return EMPTY_STRING, false, false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == EMPTY_STRING {
+		name = f.Name
+	}
+
+	return name, strings.Contains(options, "omitempty"), true
+}
+
+// isCollection is true for slices, arrays and maps which are not objects,
+// every format renders them as JSON
+func isCollection(v interface{}) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
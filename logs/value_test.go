@@ -0,0 +1,122 @@
+package logs
+
+import (
+	"time"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"encoding/json"
+)
+
+// testUser 
+type testUser struct {
+	Name     string            `json:"name"`
+	Password string            `json:"-"`
+	Email    string            `json:"email,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Created  time.Time
+	private  int
+}
+
+// testRequest 
+type testRequest struct {
+	method string
+	took   time.Duration
+}
+
+// MarshalLogObject 
+func (r testRequest) MarshalLogObject(o ObjectEncoder) {
+	o.Add(String("method", r.method), Duration("took", r.took), Any("err", errors.New("failed")))
+}
+
+// testSecret 
+type testSecret string
+
+// MarshalLog 
+func (s testSecret) MarshalLog() interface{} {
+	return "***"
+}
+
+// testState is stringer whose fields are not exported
+type testState struct {
+	code int
+}
+
+// String 
+func (s testState) String() string {
+	return [...]string{"new", "running", "ready"}[s.code]
+}
+
+// testValues returns vars with nested and typed values
+func testValues() Vars {
+	tt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return Vars{
+		"user":    testUser{Name: "joe", Password: "secret", Labels: map[string]string{"b": "2", "a": "1"}, Created: tt, private: 1},
+		"request": testRequest{"GET", 1500 * time.Millisecond},
+		"secret":  testSecret("password"),
+		"body":    []byte("text"),
+		"list":    []int{1, 2},
+		"time":    tt,
+		"state":   testState{2},
+	}
+}
+
+func TestValueJSON(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerJSON(&Formatter{Level: INFO_LEVEL}, w))
+
+	ls.Infov("message", testValues())
+
+	v := map[string]interface{}{}
+	if err := json.Unmarshal(w.Bytes(), &v); err != nil {
+		t.Fatalf("%s: %v", w.Bytes(), err)
+	}
+	for key, want := range map[string]string{
+		"user":        `{"Created":"2020-01-02T03:04:05Z","labels":{"a":"1","b":"2"},"name":"joe"}`,
+		"request":     `{"err":"failed","method":"GET","took":"1.5s"}`,
+		"secret":      `"***"`,
+		"body":        `"text"`,
+		"list":        `[1,2]`,
+		"fields.time": `"2020-01-02T03:04:05Z"`,
+		"state":       `"ready"`,
+	} {
+		if got, _ := json.Marshal(v[key]); string(got) != want {
+			t.Errorf("%s: got %s, want %s", key, got, want)
+		}
+	}
+	if !strings.Contains(w.String(), `"user":{"name":"joe","labels":{"a":"1","b":"2"},"Created":`) {
+		t.Errorf("order of struct fields is changed: %s", w.Bytes())
+	}
+}
+
+func TestValueFlatten(t *testing.T) {
+	want := []string{
+		"user.name=joe", "user.labels.a=1", "user.labels.b=2", "user.Created=2020-01-02T03:04:05Z",
+		"request.method=GET", "request.took=1.5s", "request.err=failed",
+		"secret=***", "body=text", "list=[1,2]", "state=ready",
+	}
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerFMT(&Formatter{Level: INFO_LEVEL}, w))
+
+	ls.Infow("message", Object("request", testRequest{"GET", 1500 * time.Millisecond}))
+	ls.Infov("message", testValues())
+	for _, s := range want {
+		if !strings.Contains(w.String(), s) {
+			t.Errorf("%s is not found in %s", s, w.Bytes())
+		}
+	}
+	if strings.Contains(w.String(), "password") {
+		t.Error("value of LogMarshaler is not used")
+	}
+
+	w.Reset()
+	ls = newTestLogs(writerText(&Formatter{Level: INFO_LEVEL}, w, false))
+	ls.Infov("message", testValues())
+	for _, s := range []string{"user.labels.a=1, user.labels.b=2", "request.took=1.5s", "body=text", "list=[1,2]", "state=ready"} {
+		if !strings.Contains(w.String(), s) {
+			t.Errorf("%s is not found in %s", s, w.Bytes())
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package logs
+
+import (
+	"io"
+	"log"
+	"sync"
+	"bytes"
+	"strings"
+)
+
+// WRITER_LINE_SIZE_MAX limits line kept by writer, longer line is written as
+// several records
+const WRITER_LINE_SIZE_MAX = int(65536)
+
+// WRITER_LEVEL_NAME_SIZE_MAX is size of the longest level name of prefix
+const WRITER_LEVEL_NAME_SIZE_MAX = int(7)
+
+// writerLevels are level names of line prefixes like "[ERROR]" or "WARN:",
+// panic and fatal of other programs must not end process, so they are errors
+var writerLevels = []struct {
+	name  string
+	level int
+}{
+	{PANIC_LEVEL_NAME, ERROR_LEVEL},
+	{FATAL_LEVEL_NAME, ERROR_LEVEL},
+	{ERROR_LEVEL_NAME, ERROR_LEVEL},
+	{"err", ERROR_LEVEL},
+	{WARN_LEVEL_NAME, WARN_LEVEL},
+	{"warning", WARN_LEVEL},
+	{INFO_LEVEL_NAME, INFO_LEVEL},
+	{DEBUG_LEVEL_NAME, DEBUG_LEVEL},
+	{TRACE_LEVEL_NAME, TRACE_LEVEL},
+}
+
+// WriterSettings configures writer of records, detected level prefix of line
+// replaces level of writer and it is trimmed from message
+type WriterSettings struct {
+	IsLevelDetected bool `json:"is_level_detected" yaml:"is_level_detected" xml:"is_level_detected" toml:"is_level_detected"`
+}
+
+// lineWriter writes every line as record, bytes after the last new line
+// wait for the next write or close
+type lineWriter struct {
+	logs     *Logs
+	level    int
+	settings *WriterSettings
+	mutex    sync.Mutex
+	line     []byte
+}
+
+// Writer returns writer whose lines are records of level, e.g. output of
+// subprocess. Invalid level is info.
+func (ls *Logs) Writer(l int, s ...*WriterSettings) io.WriteCloser {
+	w := &lineWriter{
+		logs:     ls,
+		level:    l,
+		settings: &WriterSettings{},
+	}
+	if !IsLevel(l) && l != PRINT_LEVEL {
+		w.level = INFO_LEVEL
+	}
+	if len(s) > 0 && s[0] != nil {
+		w.settings = s[0]
+	}
+
+	return w
+}
+
+// StdLogger returns log.Logger whose lines are records of level, e.g. for
+// ErrorLog of http.Server. Time and caller are written by logger, so it has
+// no flags.
+func (ls *Logs) StdLogger(l int, s ...*WriterSettings) *log.Logger {
+	return log.New(ls.Writer(l, s...), EMPTY_STRING, 0)
+}
+
+// RedirectStdLog writes output of log package as info records with detected
+// levels, returned function restores output, flags and prefix of log package
+func (ls *Logs) RedirectStdLog() func() {
+	flags, prefix, output := log.Flags(), log.Prefix(), log.Writer()
+	w := ls.Writer(INFO_LEVEL, &WriterSettings{IsLevelDetected: true})
+
+	log.SetFlags(0)
+	log.SetPrefix(EMPTY_STRING)
+	log.SetOutput(w)
+
+	return func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		w.Close()
+	}
+}
+
+// Write writes complete lines as records and keeps the rest
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.line = append(w.line, p...)
+			for len(w.line) >= WRITER_LINE_SIZE_MAX {
+				w.record(w.line[:WRITER_LINE_SIZE_MAX])
+				w.line = append(w.line[:0], w.line[WRITER_LINE_SIZE_MAX:]...)
+			}
+			break
+		}
+		if len(w.line) > 0 {
+			w.line = append(w.line, p[:i]...)
+			w.record(w.line)
+			w.line = w.line[:0]
+		} else {
+			w.record(p[:i])
+		}
+		p = p[i+1:]
+	}
+
+	return n, nil
+}
+
+// Close writes the rest of the last line
+func (w *lineWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if len(w.line) > 0 {
+		w.record(w.line)
+		w.line = nil
+	}
+
+	return nil
+}
+
+// record writes line without carriage return, empty lines are skipped
+func (w *lineWriter) record(line []byte) {
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	if len(line) == 0 {
+		return
+	}
+
+	l, m := w.level, string(line)
+	if w.settings.IsLevelDetected {
+		l, m = writerLevel(m, l)
+	}
+	w.logs.log(l, m, nil)
+}
+
+// writerLevel returns level of prefix like "[ERROR]" or "WARN:" and line
+// without prefix, line without known prefix keeps level
+func writerLevel(line string, l int) (int, string) {
+	var name, rest string
+
+	s := strings.TrimLeft(line, " \t")
+	if strings.HasPrefix(s, "[") {
+		if i := strings.IndexByte(s, ']'); i > 0 {
+			name, rest = s[1:i], s[i+1:]
+		}
+	} else if i := strings.IndexByte(s, ':'); i > 0 {
+		name, rest = s[:i], s[i+1:]
+	}
+
+	if name == EMPTY_STRING || len(name) > WRITER_LEVEL_NAME_SIZE_MAX {
+		return l, line
+	}
+	for _, level := range writerLevels {
+		if strings.EqualFold(name, level.name) {
+			return level.level, strings.TrimLeft(rest, " \t")
+		}
+	}
+
+	return l, line
+}
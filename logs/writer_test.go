@@ -0,0 +1,67 @@
+package logs
+
+import (
+	"log"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerFMT(&Formatter{Level: INFO_LEVEL}, w))
+
+	lw := ls.Writer(WARN_LEVEL, &WriterSettings{IsLevelDetected: true})
+	lw.Write([]byte("first\r\n[ERROR] sec"))
+	lw.Write([]byte("ond\n\nDEBUG: hidden\nfatal: not exit\nhost: localhost\npart"))
+	if strings.Contains(w.String(), "part") {
+		t.Errorf("incomplete line is written %s", w.Bytes())
+	}
+	lw.Close()
+
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	for i, want := range []string{
+		"level=warn msg=first",
+		"level=error msg=second",
+		"level=error msg=\"not exit\"",
+		"level=warn msg=\"host: localhost\"",
+		"level=warn msg=part",
+	} {
+		if i >= len(lines) || !strings.Contains(lines[i], want) {
+			t.Errorf("line %d: want %s in %q", i, want, lines)
+		}
+	}
+	if len(lines) != 5 {
+		t.Errorf("unexpected lines %q", lines)
+	}
+
+	w.Reset()
+	lw = ls.Writer(INFO_LEVEL)
+	lw.Write(bytes.Repeat([]byte{'a'}, WRITER_LINE_SIZE_MAX+1))
+	lw.Close()
+	if n := strings.Count(w.String(), "\n"); n != 2 {
+		t.Errorf("long line is written as %d records", n)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	w := &bytes.Buffer{}
+	ls := newTestLogs(writerFMT(&Formatter{Level: INFO_LEVEL}, w))
+
+	ls.StdLogger(ERROR_LEVEL).Printf("http: TLS handshake error from %s", "127.0.0.1")
+	if !strings.Contains(w.String(), `level=error msg="http: TLS handshake error from 127.0.0.1"`) {
+		t.Errorf("unexpected record %s", w.Bytes())
+	}
+
+	w.Reset()
+	output, flags := log.Writer(), log.Flags()
+	restore := ls.RedirectStdLog()
+	log.Print("[WARN] disk is almost full")
+	restore()
+	if !strings.Contains(w.String(), `level=warn msg="disk is almost full"`) {
+		t.Errorf("unexpected record %s", w.Bytes())
+	}
+	if log.Writer() != output || log.Flags() != flags {
+		t.Error("log package is not restored")
+	}
+}